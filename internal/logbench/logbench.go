@@ -0,0 +1,65 @@
+// Package logbench contains a standard suite of benchmarks for logger sinks,
+// so that the allocations and time spent per field type can be compared
+// between the different sink implementations.
+package logbench
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+)
+
+var (
+	benchErr  = errors.New("something went wrong")
+	benchTime = time.Date(2021, 2, 9, 13, 37, 0, 0, time.UTC)
+)
+
+var benchmarks = []struct {
+	name string
+	log  func(logger.Event) logger.Event
+}{
+	{"NoFields", func(ev logger.Event) logger.Event { return ev }},
+	{"String", func(ev logger.Event) logger.Event { return ev.WithString("key", "some \"quoted\" value") }},
+	{"Int", func(ev logger.Event) logger.Event { return ev.WithInt("key", 1234567) }},
+	{"Uint64", func(ev logger.Event) logger.Event { return ev.WithUint64("key", 1234567) }},
+	{"Float64", func(ev logger.Event) logger.Event { return ev.WithFloat64("key", 3.14159) }},
+	{"Bool", func(ev logger.Event) logger.Event { return ev.WithBool("key", true) }},
+	{"Time", func(ev logger.Event) logger.Event { return ev.WithTime("key", benchTime) }},
+	{"Duration", func(ev logger.Event) logger.Event { return ev.WithDuration("key", 1500*time.Millisecond) }},
+	{"Error", func(ev logger.Event) logger.Event { return ev.WithError(benchErr) }},
+	{"TenFields", func(ev logger.Event) logger.Event {
+		return ev.
+			WithString("method", "GET").
+			WithString("path", "/api/project/1").
+			WithInt("status", 200).
+			WithDuration("latency", 1500*time.Millisecond).
+			WithString("clientIp", "127.0.0.1").
+			WithInt64("bytes", 4096).
+			WithBool("cached", false).
+			WithFloat64("ratio", 0.5).
+			WithTime("requestedAt", benchTime).
+			WithError(benchErr)
+	}},
+}
+
+// RunSinkBenchmarks runs the standard suite of benchmarks against the sink,
+// with one sub-benchmark per field type. The sink is registered as the only
+// logging output while the benchmarks are running.
+func RunSinkBenchmarks(b *testing.B, sink logger.Sink) {
+	logger.ClearOutputs()
+	logger.AddOutput(logger.LevelDebug, sink)
+	b.Cleanup(logger.ClearOutputs)
+	log := logger.NewScoped("BENCH")
+
+	for _, bench := range benchmarks {
+		bench := bench
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				bench.log(log.Info()).Message("Benchmark message.")
+			}
+		})
+	}
+}
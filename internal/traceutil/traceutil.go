@@ -29,27 +29,47 @@ func init() {
 // this function and ignoring all paths from inside this repository
 // (wharf-core). unless it's also a test file ("*_test.go")
 func CallerFileWithLineNum() (string, int) {
+	_, path, line, ok := callerFrame()
+	if !ok {
+		return "", 0
+	}
+	return FileAndLastDir(path), line
+}
+
+// CallerFrame returns the function name, full file path, and line number of
+// the caller, using the same traversal as CallerFileWithLineNum.
+func CallerFrame() (function, path string, line int, ok bool) {
+	return callerFrame()
+}
+
+func callerFrame() (function, path string, line int, ok bool) {
 	const (
-		// start on 2 to disregard this func and caller of this func
-		startDepth = 2
+		// start on 3 to disregard this func, the exported func calling this
+		// func, and the caller of that func
+		startDepth = 3
 		// the max is mostly arbitrary, but we don't want an infinite loop
-		maxDepth = 15
+		maxDepth = 16
 	)
 	for i := startDepth; i <= maxDepth; i++ {
-		_, path, line, ok := runtime.Caller(i)
+		pc, path, line, ok := runtime.Caller(i)
 
 		if ok && isValidCallerFile(path) {
-			return fileAndLastDir(path), line
+			if fn := runtime.FuncForPC(pc); fn != nil {
+				function = fn.Name()
+			}
+			return function, path, line, true
 		}
 	}
-	return "", 0
+	return "", "", 0, false
 }
 
 func isValidCallerFile(path string) bool {
 	return strings.HasSuffix(path, "_test.go") || !strings.HasPrefix(path, wharfCoreDir)
 }
 
-func fileAndLastDir(path string) string {
+// FileAndLastDir returns the file name of the path with its direct parent
+// directory, such as "logger/logger.go".
+func FileAndLastDir(path string) string {
 	const unknownDir = "???" + string(filepath.Separator)
 	dir, file := filepath.Split(path)
 	if dir == "" {
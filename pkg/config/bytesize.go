@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a size in bytes, that can be unmarshaled from strings such as
+// "512MiB" or "1.5GB", as parsed by ParseByteSize.
+type ByteSize uint64
+
+// Decimal and binary byte size units.
+const (
+	Byte ByteSize = 1
+
+	Kilobyte = 1000 * Byte
+	Megabyte = 1000 * Kilobyte
+	Gigabyte = 1000 * Megabyte
+	Terabyte = 1000 * Gigabyte
+
+	Kibibyte = 1024 * Byte
+	Mebibyte = 1024 * Kibibyte
+	Gibibyte = 1024 * Mebibyte
+	Tebibyte = 1024 * Gibibyte
+)
+
+var byteSizeUnits = []struct {
+	suffix string
+	size   ByteSize
+}{
+	// sorted from largest to smallest, so the largest exact unit is used
+	// when formatting
+	{"TiB", Tebibyte},
+	{"TB", Terabyte},
+	{"GiB", Gibibyte},
+	{"GB", Gigabyte},
+	{"MiB", Mebibyte},
+	{"MB", Megabyte},
+	{"KiB", Kibibyte},
+	{"KB", Kilobyte},
+	{"B", Byte},
+}
+
+// ParseByteSize parses a byte size string, consisting of a number followed by
+// an optional unit, such as "512MiB", "1.5GB", or "100". The units are case
+// insensitive, where "KB", "MB", "GB", and "TB" are powers of 1000, and "KiB",
+// "MiB", "GiB", and "TiB" are powers of 1024. A number without a unit is in
+// bytes. Sizes larger than math.MaxInt64 bytes are rejected.
+func ParseByteSize(s string) (ByteSize, error) {
+	trimmed := strings.TrimSpace(s)
+	unit := Byte
+	number := trimmed
+	for _, u := range byteSizeUnits {
+		if len(trimmed) > len(u.suffix) &&
+			strings.EqualFold(trimmed[len(trimmed)-len(u.suffix):], u.suffix) {
+			unit = u.size
+			number = strings.TrimSpace(trimmed[:len(trimmed)-len(u.suffix)])
+			break
+		}
+	}
+	if n, err := strconv.ParseUint(number, 10, 64); err == nil {
+		if n > math.MaxInt64/uint64(unit) {
+			return 0, fmt.Errorf("byte size out of range: %q", s)
+		}
+		return ByteSize(n) * unit, nil
+	}
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil || f < 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("invalid byte size: %q", s)
+	}
+	// float64(math.MaxInt64) rounds up to 2^63, which is out of range
+	if f*float64(unit) >= math.MaxInt64 {
+		return 0, fmt.Errorf("byte size out of range: %q", s)
+	}
+	return ByteSize(f * float64(unit)), nil
+}
+
+// String returns the byte size using the largest unit that represents the
+// size without decimals, such as "512MiB".
+func (s ByteSize) String() string {
+	if s == 0 {
+		return "0B"
+	}
+	for _, u := range byteSizeUnits {
+		if s%u.size == 0 {
+			return strconv.FormatUint(uint64(s/u.size), 10) + u.suffix
+		}
+	}
+	return strconv.FormatUint(uint64(s), 10) + "B"
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s ByteSize) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *ByteSize) UnmarshalText(text []byte) error {
+	size, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*s = size
+	return nil
+}
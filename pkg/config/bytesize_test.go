@@ -0,0 +1,52 @@
+package config
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteSize(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  ByteSize
+	}{
+		{input: "100", want: 100},
+		{input: "100B", want: 100},
+		{input: "512MiB", want: 512 * Mebibyte},
+		{input: "512 mib", want: 512 * Mebibyte},
+		{input: "1.5GB", want: 1500 * Megabyte},
+		{input: "2KB", want: 2000},
+		{input: "1TiB", want: Tebibyte},
+		{input: "9223372036854775807", want: math.MaxInt64},
+		{input: "8388607TiB", want: 8388607 * Tebibyte},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := ParseByteSize(tc.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParseByteSize_invalid(t *testing.T) {
+	for _, input := range []string{
+		"", "MiB", "-1KB", "12XB",
+		"NaN", "NaNMiB", "Inf", "+InfKB", "-Inf",
+		"9223372036854775808", "8388608TiB", "1e19", "9.3e18B",
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseByteSize(input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestByteSize_String(t *testing.T) {
+	assert.Equal(t, "0B", ByteSize(0).String())
+	assert.Equal(t, "512MiB", (512 * Mebibyte).String())
+	assert.Equal(t, "3KB", (3 * Kilobyte).String())
+	assert.Equal(t, "1001B", ByteSize(1001).String())
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"net/http"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/cacertutil"
+)
+
+// CertsFile is a reusable config struct for the extra root CA certificates
+// that a Wharf component trusts when making HTTPS requests, such as for
+// self-signed certificates, meant to be embedded in the config struct of each
+// component so that they expose identically named settings:
+//
+//  type Config struct {
+//  	CA config.CertsFile // set via "WHARF_CA_CERTSFILE"
+//  }
+//
+//  client, err := cfg.CA.NewHTTPClient()
+//  if err != nil {
+//  	// ...
+//  }
+type CertsFile struct {
+	// CertsFile is the path to a file of PEM formatted certificates, that are
+	// trusted in addition to the system's certificates. Only the system's
+	// certificates are used when empty.
+	CertsFile string `validate:"omitempty,file" usage:"Path to a file of PEM formatted root CA certificates to trust."`
+}
+
+// NewHTTPClient creates a new HTTP client that trusts the certificates from
+// the CertsFile, in addition to the system's certificates, using the
+// cacertutil.NewHTTPClientWithCerts function. Returns a new HTTP client with
+// the default settings if no CertsFile is set.
+func (c CertsFile) NewHTTPClient() (*http.Client, error) {
+	if c.CertsFile == "" {
+		return &http.Client{}, nil
+	}
+	return cacertutil.NewHTTPClientWithCerts(c.CertsFile)
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertsFile_NewHTTPClient_empty(t *testing.T) {
+	client, err := CertsFile{}.NewHTTPClient()
+	require.NoError(t, err)
+	assert.NotNil(t, client)
+}
+
+func TestCertsFile_NewHTTPClient_missingFile(t *testing.T) {
+	_, err := CertsFile{CertsFile: "testdata/does-not-exist.pem"}.NewHTTPClient()
+	assert.Error(t, err)
+}
+
+func TestCertsFile_validate(t *testing.T) {
+	assert.NoError(t, Validate(struct{ CA CertsFile }{CertsFile{}}))
+	err := Validate(struct{ CA CertsFile }{CertsFile{CertsFile: "testdata/does-not-exist.pem"}})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
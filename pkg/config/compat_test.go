@@ -0,0 +1,213 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The tests in this file cover the merge semantics that this package kept
+// when replacing its previous implementation, based on the
+// github.com/spf13/viper package, with the internal store.
+
+type compatLogging struct {
+	Level string
+}
+
+type compatDB struct {
+	Host    string
+	Port    int
+	Timeout time.Duration
+}
+
+type compatConfig struct {
+	compatLogging `mapstructure:",squash" yaml:",inline"`
+	Name          string
+	Enabled       bool
+	Tags          []string
+	Labels        map[string]string
+	DB            compatDB
+	Cache         *compatDB
+}
+
+var compatDefaults = compatConfig{
+	compatLogging: compatLogging{Level: "info"},
+	Name:          "default",
+	Tags:          []string{"a", "b"},
+	DB: compatDB{
+		Host:    "localhost",
+		Port:    5432,
+		Timeout: 10 * time.Second,
+	},
+}
+
+func TestCompat_mergeSemantics(t *testing.T) {
+	testCases := []struct {
+		name  string
+		env   map[string]string
+		build func(b Builder)
+		want  func(c *compatConfig)
+	}{
+		{
+			name:  "defaults only",
+			build: func(b Builder) {},
+			want:  func(c *compatConfig) {},
+		},
+		{
+			name: "later sources override earlier per field",
+			build: func(b Builder) {
+				b.AddConfigYAML(strings.NewReader("name: first\ndb:\n  host: first"))
+				b.AddConfigYAML(strings.NewReader("db:\n  host: second"))
+			},
+			want: func(c *compatConfig) {
+				c.Name = "first"
+				c.DB.Host = "second"
+			},
+		},
+		{
+			name: "keys are case-insensitive",
+			build: func(b Builder) {
+				b.AddConfigYAML(strings.NewReader("NAME: upper\nDb:\n  PoRt: 1"))
+			},
+			want: func(c *compatConfig) {
+				c.Name = "upper"
+				c.DB.Port = 1
+			},
+		},
+		{
+			name: "null values do not override",
+			build: func(b Builder) {
+				b.AddConfigYAML(strings.NewReader("name: ~\ndb: ~"))
+			},
+			want: func(c *compatConfig) {},
+		},
+		{
+			name: "slices are replaced, not merged",
+			build: func(b Builder) {
+				b.AddConfigYAML(strings.NewReader("tags: [c]"))
+			},
+			want: func(c *compatConfig) {
+				c.Tags = []string{"c"}
+			},
+		},
+		{
+			name: "map fields merge keys across sources",
+			build: func(b Builder) {
+				b.AddConfigYAML(strings.NewReader("labels:\n  foo: 1\n  bar: 2"))
+				b.AddConfigYAML(strings.NewReader("labels:\n  bar: 3"))
+			},
+			want: func(c *compatConfig) {
+				c.Labels = map[string]string{"foo": "1", "bar": "3"}
+			},
+		},
+		{
+			name: "squashed embedded structs",
+			build: func(b Builder) {
+				b.AddConfigYAML(strings.NewReader("level: debug"))
+			},
+			want: func(c *compatConfig) {
+				c.Level = "debug"
+			},
+		},
+		{
+			name: "nil pointer structs are created when set",
+			build: func(b Builder) {
+				b.AddConfigYAML(strings.NewReader("cache:\n  port: 6379"))
+			},
+			want: func(c *compatConfig) {
+				c.Cache = &compatDB{Port: 6379}
+			},
+		},
+		{
+			name: "weakly typed values",
+			build: func(b Builder) {
+				b.AddConfigYAML(strings.NewReader(`enabled: "true"` + "\ndb:\n  port: \"1\"\n  timeout: 1m"))
+			},
+			want: func(c *compatConfig) {
+				c.Enabled = true
+				c.DB.Port = 1
+				c.DB.Timeout = time.Minute
+			},
+		},
+		{
+			name: "environment variables take precedence regardless of order",
+			env: map[string]string{
+				"COMPAT_NAME":    "env",
+				"COMPAT_DB_PORT": "2",
+			},
+			build: func(b Builder) {
+				b.AddEnvironmentVariables("COMPAT")
+				b.AddConfigYAML(strings.NewReader("name: yaml\ndb:\n  port: 1\n  host: yaml"))
+			},
+			want: func(c *compatConfig) {
+				c.Name = "env"
+				c.DB.Port = 2
+				c.DB.Host = "yaml"
+			},
+		},
+		{
+			name: "environment variables of keys only defined by later sources",
+			env: map[string]string{
+				"COMPAT_LABELS_FOO": "env",
+			},
+			build: func(b Builder) {
+				b.AddEnvironmentVariables("COMPAT")
+				b.AddConfigYAML(strings.NewReader("labels:\n  foo: yaml\n  bar: yaml"))
+			},
+			want: func(c *compatConfig) {
+				c.Labels = map[string]string{"foo": "env", "bar": "yaml"}
+			},
+		},
+		{
+			name: "empty environment variables are ignored",
+			env: map[string]string{
+				"COMPAT_NAME": "",
+			},
+			build: func(b Builder) {
+				b.AddEnvironmentVariables("COMPAT")
+			},
+			want: func(c *compatConfig) {},
+		},
+		{
+			name: "environment variables of squashed embedded structs",
+			env: map[string]string{
+				"COMPAT_LEVEL": "warn",
+			},
+			build: func(b Builder) {
+				b.AddEnvironmentVariables("COMPAT")
+			},
+			want: func(c *compatConfig) {
+				c.Level = "warn"
+			},
+		},
+		{
+			name: "missing files are ignored",
+			build: func(b Builder) {
+				b.AddConfigYAMLFile("testdata/does-not-exist.yml")
+				b.AddConfigYAMLFile("")
+			},
+			want: func(c *compatConfig) {},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Clearenv()
+			for k, v := range tc.env {
+				os.Setenv(k, v)
+			}
+			b := NewBuilder(compatDefaults)
+			tc.build(b)
+			var got compatConfig
+			require.NoError(t, b.Unmarshal(&got))
+
+			want := compatDefaults
+			want.Tags = append([]string(nil), compatDefaults.Tags...)
+			tc.want(&want)
+			assert.Equal(t, want, got)
+		})
+	}
+}
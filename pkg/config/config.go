@@ -1,18 +1,26 @@
 package config
 
 import (
-	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
+	"os"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/spf13/viper"
+	"github.com/mitchellh/mapstructure"
 	"gopkg.in/yaml.v2"
 )
 
-const configTypeYAML = "yaml"
+const (
+	configTypeYAML = "yaml"
+	configTypeJSON = "json"
+)
 
 // Builder type has methods for registering configuration sources, and
 // then using those sources you can unmarshal into a struct to read the
@@ -34,20 +42,57 @@ type Builder interface {
 	// Useful for reading from embedded files, database stored configs, and from
 	// HTTP response bodies.
 	//
+	// Environment variables referenced in string values are expanded, for all
+	// YAML config sources, using the syntax "${VAR}", or "${VAR:-default}" to
+	// use a default value if the variable is unset or empty. Use "$${" to
+	// write a literal "${". Example:
+	//
+	//  db:
+	//    password: ${DB_PASSWORD}
+	//    host: ${DB_HOST:-localhost}
+	//
 	// Later added config sources will merge on top of the previous on a
 	// per config field basis. Later added sources will override earlier added
 	// sources.
 	AddConfigYAML(reader io.Reader)
 
+	// AddConfigYAMLFS appends the path of a YAML file inside a file system to
+	// the list of sources for this configuration. Useful for reading from an
+	// embed.FS, or from an fstest.MapFS in tests.
+	//
+	// Later added config sources will merge on top of the previous on a
+	// per config field basis. Later added sources will override earlier added
+	// sources.
+	AddConfigYAMLFS(fsys fs.FS, path string)
+
+	// AddConfigJSONFile appends the path of a JSON file to the list of sources
+	// for this configuration.
+	//
+	// Later added config sources will merge on top of the previous on a
+	// per config field basis. Later added sources will override earlier added
+	// sources.
+	AddConfigJSONFile(path string)
+
+	// AddConfigJSON appends a byte reader for UTF-8 and JSON formatted content.
+	// Useful for reading from embedded files, database stored configs, and from
+	// HTTP response bodies.
+	//
+	// Later added config sources will merge on top of the previous on a
+	// per config field basis. Later added sources will override earlier added
+	// sources.
+	AddConfigJSON(reader io.Reader)
+
 	// AddEnvironmentVariables appends an environment variable source.
 	//
 	// Later added config sources will merge on top of the previous on a
 	// per config field basis. Later added sources will override earlier added
 	// sources.
 	//
-	// However, multiple environment variable sources cannot be added, due to
-	// technical limitations with the implementation. Even if they use different
-	// prefixes.
+	// Multiple environment variable sources can be added, such as with
+	// different prefixes, where later added environment variable sources
+	// override earlier added ones. However, environment variables always take
+	// precedence over the other kinds of config sources, regardless of the
+	// order they were added in.
 	//
 	// Environment variables must be in all uppercase letters, and nested
 	// structs use a single underscore "_" as delimiter. Example:
@@ -66,20 +111,108 @@ type Builder interface {
 	// The prefix shall be without a trailing underscore "_" as this package
 	// adds that in by itself. To not use a prefix, pass in an empty string as
 	// prefix instead.
+	//
+	// If an environment variable is unset or empty, then the same name with
+	// the "_FILE" suffix is checked, and if set then the value is read from the
+	// file at that path, with leading and trailing whitespace trimmed. This
+	// follows the convention used for Docker and Kubernetes secrets:
+	//
+	//  FOO_HELLO_WORLD_FILE=/run/secrets/hello-world
 	AddEnvironmentVariables(prefix string)
 
+	// AddRemoteProvider appends a remote key-value store source, where the
+	// value of the key at the path contains YAML or JSON formatted content.
+	// Supported providers are RemoteProviderEtcd and RemoteProviderConsul,
+	// where the endpoint is the base URL of the HTTP API. Example:
+	//
+	//  c.AddRemoteProvider(config.RemoteProviderConsul,
+	//  	"http://consul:8500", "wharf/api/config.yml")
+	//
+	// The path is used as-is as the etcd key, while any leading slash is
+	// trimmed from the Consul key.
+	//
+	// The key is read on each call to Unmarshal, where a key that does not
+	// exist is ignored, the same way as with AddConfigYAMLFile.
+	//
+	// Later added config sources will merge on top of the previous on a
+	// per config field basis. Later added sources will override earlier added
+	// sources.
+	AddRemoteProvider(provider, endpoint, path string)
+
+	// WatchRemoteProviders polls all sources added via AddRemoteProvider with
+	// the given interval, and calls onChange with a nil error whenever any of
+	// their values have changed, or with a non-nil error if a key could not be
+	// read. Call Unmarshal again from onChange to read the updated config.
+	//
+	// Blocks until the context is canceled, and returns immediately if no
+	// remote providers have been added. Example:
+	//
+	//  go c.WatchRemoteProviders(ctx, 30*time.Second, func(err error) {
+	//  	if err == nil {
+	//  		err = c.Unmarshal(&cfg)
+	//  	}
+	//  	...
+	//  })
+	WatchRemoteProviders(ctx context.Context, interval time.Duration, onChange func(error))
+
+	// AddSource appends a custom config source, such as for reading config
+	// stored in a database.
+	//
+	// Later added config sources will merge on top of the previous on a
+	// per config field basis. Later added sources will override earlier added
+	// sources.
+	AddSource(s Source)
+
+	// SetStrict enables or disables strict mode. In strict mode, Unmarshal
+	// returns an error of type *UnknownKeysError if any of the config sources
+	// contains keys that don't map to any field in the config struct, which
+	// helps catching typos in config files.
+	//
+	// Environment variables are also checked, where any environment variable
+	// that starts with the prefix, as given to AddEnvironmentVariables, is
+	// regarded as unknown if it doesn't map to any field. Environment
+	// variables are not checked when using an empty prefix.
+	//
+	// Strict mode is disabled by default.
+	SetStrict(strict bool)
+
+	// Explain returns the effective value of each config key, together with
+	// the name of the config source that supplied it, sorted by key. Useful
+	// for debugging why a setting has a certain value, or for logging the
+	// config on startup. Example:
+	//
+	//  origins, err := c.Explain()
+	//  for _, o := range origins {
+	//  	log.Debug().WithString("source", o.Source).
+	//  		Messagef("%s: %v", o.Key, o.Value)
+	//  }
+	//
+	// The values are as given by the config sources, before being decoded
+	// into the config struct, and may therefore contain secrets such as
+	// passwords.
+	Explain() ([]KeyOrigin, error)
+
 	// Unmarshal applies the configuration, based on the numerous added sources,
 	// on to an existing struct.
 	//
 	// For any field of type pointer and is set to nil, this function will
 	// create a new instance and assign that before populating that branch.
 	//
+	// Fields of the types time.Duration ("30s"), url.URL and *url.URL,
+	// net.IP, logger.Level ("info"), ByteSize ("512MiB"), and slices
+	// (comma-separated, such as "a, b, c") can be set from string values.
+	//
 	// If none of the Builder.Add...() functions has been called before
 	// this function, then this function will effectively only apply the default
 	// configuration onto this new object.
 	//
 	// The error that is returned is caused by any of the added config sources,
-	// such as from invalid YAML syntax in an added YAML file.
+	// such as from invalid YAML syntax in an added YAML file, or by the config
+	// failing validation, as described in the Validate function.
+	//
+	// All config sources are read even if some of them fail. A single failing
+	// source results in a *SourceError, while multiple failing sources result
+	// in a *SourcesError that lists all of them.
 	Unmarshal(config any) error
 }
 
@@ -87,7 +220,20 @@ type Builder interface {
 //
 // Due to technical limitations, it's vital that this default configuration is
 // of the same type that the config that you wish to unmarshal later, or at
-// least that it contains fields with the same names.
+// least that it contains fields with the same names. The generic Unmarshal
+// function checks this for you.
+//
+// Defaults can also be declared using the "default" struct tag, which is used
+// for fields that are left unset in the default configuration. The tag values
+// are parsed the same way as values from environment variables. Example:
+//
+//  type DBConfig struct {
+//  	Host    string        `default:"localhost"`
+//  	Port    int           `default:"5432"`
+//  	Timeout time.Duration `default:"30s"`
+//  }
+//
+//  c := config.NewBuilder(Config{})
 func NewBuilder(defaultConfig any) Builder {
 	return &builder{
 		defaultConfig: defaultConfig,
@@ -97,19 +243,32 @@ func NewBuilder(defaultConfig any) Builder {
 type builder struct {
 	defaultConfig any
 	sources       []configSource
+	strict        bool
 }
 
 type configSource interface {
 	name() string
-	apply(v *viper.Viper) error
+	apply(st *store) error
 }
 
 func (b *builder) AddConfigYAMLFile(path string) {
-	b.sources = append(b.sources, yamlFileSource{path})
+	b.sources = append(b.sources, fileSource{path, configTypeYAML})
 }
 
 func (b *builder) AddConfigYAML(reader io.Reader) {
-	b.sources = append(b.sources, yamlSource{reader})
+	b.sources = append(b.sources, readerSource{reader, configTypeYAML})
+}
+
+func (b *builder) AddConfigYAMLFS(fsys fs.FS, path string) {
+	b.sources = append(b.sources, fsSource{fsys, path, configTypeYAML})
+}
+
+func (b *builder) AddConfigJSONFile(path string) {
+	b.sources = append(b.sources, fileSource{path, configTypeJSON})
+}
+
+func (b *builder) AddConfigJSON(reader io.Reader) {
+	b.sources = append(b.sources, readerSource{reader, configTypeJSON})
 }
 
 func (b *builder) AddEnvironmentVariables(prefix string) {
@@ -117,71 +276,161 @@ func (b *builder) AddEnvironmentVariables(prefix string) {
 }
 
 func (b *builder) Unmarshal(config any) error {
-	v := viper.New()
-	initDefaults(v, b.defaultConfig)
+	if err := b.unmarshal(config); err != nil {
+		return err
+	}
+	return Validate(config)
+}
+
+func (b *builder) SetStrict(strict bool) {
+	b.strict = strict
+}
+
+// newStore creates a new store with the defaults and all config sources
+// applied, where all sources are applied even if some of them fail,
+// so that all errors can be reported at once. It calls the optional afterEach function after the defaults
+// and after each config source has been applied.
+func (b *builder) newStore(afterEach func(st *store, source string)) (*store, error) {
+	st := newStore()
+	if err := initDefaults(st, b.defaultConfig); err != nil {
+		return nil, err
+	}
+	if afterEach != nil {
+		afterEach(st, defaultsSourceName)
+	}
+	// The environment variable sources are applied last, so that they look
+	// up the keys from all other config sources, regardless of the order
+	// they were added in.
+	var sources, envSources []configSource
 	for _, s := range b.sources {
-		if err := s.apply(v); err != nil {
-			return fmt.Errorf("applying config source: %s: %T: %w", s.name(), err, err)
+		if _, ok := s.(envVarsSource); ok {
+			envSources = append(envSources, s)
+		} else {
+			sources = append(sources, s)
+		}
+	}
+	var errs []*SourceError
+	for _, s := range append(sources, envSources...) {
+		if err := s.apply(st); err != nil {
+			errs = append(errs, &SourceError{s.name(), err})
+			continue
+		}
+		if afterEach != nil {
+			afterEach(st, s.name())
 		}
 	}
-	return v.Unmarshal(config)
+	switch len(errs) {
+	case 0:
+		return st, nil
+	case 1:
+		return nil, errs[0]
+	default:
+		return nil, &SourcesError{errs}
+	}
+}
+
+func (b *builder) unmarshal(config any) error {
+	var sources keySources
+	var afterEach func(st *store, source string)
+	if b.strict {
+		afterEach = sources.add
+	}
+	st, err := b.newStore(afterEach)
+	if err != nil {
+		return err
+	}
+	var metadata mapstructure.Metadata
+	if err := st.decode(config, &metadata); err != nil {
+		return err
+	}
+	if b.strict {
+		return b.unknownKeys(st, metadata.Unused, &sources)
+	}
+	return nil
 }
 
-func initDefaults(v *viper.Viper, defaultConfig any) error {
-	// Uses a workaround to force viper to read environment variables
-	// by making it aware of all fields that exists so it can later map
-	// environment variables correctly.
-	// https://github.com/spf13/viper/issues/188#issuecomment-413368673
+func initDefaults(st *store, defaultConfig any) error {
+	// The defaults are marshaled as YAML to get the config keys of all fields,
+	// so the environment variable sources know which variables to look up.
+	defaultConfig, err := withTagDefaults(defaultConfig)
+	if err != nil {
+		return fmt.Errorf("setting config defaults: %w", err)
+	}
 	b, err := yaml.Marshal(defaultConfig)
 	if err != nil {
 		return fmt.Errorf("setting config defaults: %w", err)
 	}
-	defaultCfg := bytes.NewReader(b)
-	v.SetConfigType(configTypeYAML)
-	if err := v.MergeConfig(defaultCfg); err != nil {
+	var defaults map[string]any
+	if err := yaml.Unmarshal(b, &defaults); err != nil {
 		return fmt.Errorf("setting config defaults: %w", err)
 	}
+	stringifyDefaults(defaults, reflect.ValueOf(defaultConfig))
+	st.setDefaults(defaults)
 	return nil
 }
 
-type yamlFileSource struct {
-	path string
+type fileSource struct {
+	path       string
+	configType string
 }
 
-func (s yamlFileSource) name() string {
+func (s fileSource) name() string {
 	return s.path
 }
 
-func (s yamlFileSource) apply(v *viper.Viper) error {
+func (s fileSource) apply(st *store) error {
 	if s.path == "" {
-		// viper does not set config file if its empty, so viper.MergeInConfig()
-		// would then use the previously set config path value
 		return nil
 	}
-	v.SetConfigType(configTypeYAML)
-	v.SetConfigFile(s.path)
-	err := v.MergeInConfig()
+	file, err := os.Open(s.path)
 	// ignore not-found errors
 	if errors.Is(err, fs.ErrNotExist) {
 		return nil
 	}
-	if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return readerSource{file, s.configType}.apply(st)
+}
+
+type fsSource struct {
+	fsys       fs.FS
+	path       string
+	configType string
+}
+
+func (s fsSource) name() string {
+	return s.path
+}
+
+func (s fsSource) apply(st *store) error {
+	file, err := s.fsys.Open(s.path)
+	// ignore not-found errors
+	if errors.Is(err, fs.ErrNotExist) {
 		return nil
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return readerSource{file, s.configType}.apply(st)
 }
 
-type yamlSource struct {
-	reader io.Reader
+type readerSource struct {
+	reader     io.Reader
+	configType string
 }
 
-func (s yamlSource) name() string {
-	return "YAML io.Reader"
+func (s readerSource) name() string {
+	return strings.ToUpper(s.configType) + " io.Reader"
 }
 
-func (s yamlSource) apply(v *viper.Viper) error {
-	v.SetConfigType(configTypeYAML)
-	return v.MergeConfig(s.reader)
+func (s readerSource) apply(st *store) error {
+	if s.configType == configTypeJSON {
+		return mergeJSON(st, s.reader)
+	}
+	return mergeYAML(st, s.reader)
 }
 
 type envVarsSource struct {
@@ -189,12 +438,114 @@ type envVarsSource struct {
 }
 
 func (s envVarsSource) name() string {
-	return "environment variables"
+	if s.prefix == "" {
+		return "environment variables"
+	}
+	return fmt.Sprintf("environment variables with prefix %q", s.envPrefix())
 }
 
-func (s envVarsSource) apply(v *viper.Viper) error {
-	v.SetEnvPrefix(s.prefix)
-	v.AutomaticEnv()
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+func (s envVarsSource) apply(st *store) error {
+	// Only keys known from the defaults and the other config sources are
+	// looked up, as the environment variable sources are applied after all
+	// other sources in builder.newStore. The values are set as overrides, so
+	// environment variables take precedence over all other kinds of config
+	// sources.
+	for _, key := range st.keys() {
+		name := s.envVarName(key)
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			st.set(key, value)
+			continue
+		}
+		value, ok, err := readEnvVarFile(name + envVarFileSuffix)
+		if err != nil {
+			return err
+		}
+		if ok {
+			st.set(key, value)
+		}
+	}
 	return nil
 }
+
+// envVarFileSuffix is the suffix of environment variables that contain the
+// path to a file to read the value from, following the convention used by
+// Docker secrets and Kubernetes mounted secrets.
+const envVarFileSuffix = "_FILE"
+
+func readEnvVarFile(name string) (string, bool, error) {
+	path, ok := os.LookupEnv(name)
+	if !ok || path == "" {
+		return "", false, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("read file from environment variable %s: %w", name, err)
+	}
+	return strings.TrimSpace(string(b)), true, nil
+}
+
+func (s envVarsSource) envPrefix() string {
+	if s.prefix == "" {
+		return ""
+	}
+	return strings.ToUpper(s.prefix) + "_"
+}
+
+func (s envVarsSource) envVarName(key string) string {
+	return s.envPrefix() + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// mergeYAML decodes the YAML content and merges it into the store, with any
+// environment variable references in string values expanded, as described in
+// Builder.AddConfigYAML.
+func mergeYAML(st *store, reader io.Reader) error {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	var cfg map[string]any
+	if err := yaml.Unmarshal(content, &cfg); err != nil {
+		return fmt.Errorf("decode YAML: %w", err)
+	}
+	st.merge(expandEnvValues(cfg).(map[string]any))
+	return nil
+}
+
+// mergeJSON decodes the JSON content and merges it into the store. Numbers
+// are decoded as json.Number and then converted, instead of as float64, so
+// that integers get the same types as when decoded from YAML.
+func mergeJSON(st *store, reader io.Reader) error {
+	dec := json.NewDecoder(reader)
+	dec.UseNumber()
+	var cfg map[string]any
+	if err := dec.Decode(&cfg); err != nil {
+		return fmt.Errorf("decode JSON: %w", err)
+	}
+	st.merge(convertJSONNumbers(cfg).(map[string]any))
+	return nil
+}
+
+// convertJSONNumbers replaces all json.Number values with int, if the number
+// is an integer, or else float64, to match the types decoded from YAML.
+func convertJSONNumbers(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for k, v := range value {
+			value[k] = convertJSONNumbers(v)
+		}
+		return value
+	case []any:
+		for i, v := range value {
+			value[i] = convertJSONNumbers(v)
+		}
+		return value
+	case json.Number:
+		if i, err := strconv.ParseInt(value.String(), 10, strconv.IntSize); err == nil {
+			return int(i)
+		}
+		f, _ := value.Float64()
+		return f
+	default:
+		return value
+	}
+}
@@ -2,10 +2,13 @@ package config
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -76,6 +79,53 @@ func TestConfig_AddEnvironmentVariables(t *testing.T) {
 	assertUnmarshaledConfig(t, cb)
 }
 
+func TestConfig_AddEnvironmentVariables_multiplePrefixes(t *testing.T) {
+	cb := NewBuilder(defaultConfig)
+	cb.AddEnvironmentVariables("WHARF")
+	cb.AddEnvironmentVariables("MYAPP")
+
+	os.Clearenv()
+	os.Setenv("WHARF_DB_PORT", "1")
+	os.Setenv("WHARF_LOGLEVEL", updatedLogLevel)
+	os.Setenv("MYAPP_DB_PORT", strconv.FormatInt(updatedPort, 10))
+	os.Setenv("MYAPP_PASSWORD", updatedPassword)
+
+	assertUnmarshaledConfig(t, cb)
+}
+
+func TestConfig_AddEnvironmentVariables_file(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte(updatedPassword+"\n"), 0600))
+	portFile := filepath.Join(dir, "port")
+	require.NoError(t, os.WriteFile(portFile, []byte("1"), 0600))
+
+	cb := NewBuilder(defaultConfig)
+	cb.AddEnvironmentVariables("FOO")
+
+	os.Clearenv()
+	os.Setenv("FOO_PASSWORD_FILE", passwordFile)
+	os.Setenv("FOO_LOGLEVEL", updatedLogLevel)
+	// the variable itself takes precedence over the file
+	os.Setenv("FOO_DB_PORT", strconv.FormatInt(updatedPort, 10))
+	os.Setenv("FOO_DB_PORT_FILE", portFile)
+
+	assertUnmarshaledConfig(t, cb)
+}
+
+func TestConfig_AddEnvironmentVariables_fileNotFound(t *testing.T) {
+	cb := NewBuilder(defaultConfig)
+	cb.AddEnvironmentVariables("FOO")
+
+	os.Clearenv()
+	os.Setenv("FOO_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	var cfg TestConfig
+	err := cb.Unmarshal(&cfg)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.ErrorContains(t, err, "FOO_PASSWORD_FILE")
+}
+
 func TestConfig_AddConfigYAML(t *testing.T) {
 	yamlContent := fmt.Sprintf(`
 logLevel: %s
@@ -94,3 +144,48 @@ func TestConfig_AddConfigYAMLFile(t *testing.T) {
 	cb.AddConfigYAMLFile("testdata/add-config-yaml-file.yml")
 	assertUnmarshaledConfig(t, cb)
 }
+
+func TestConfig_AddConfigYAMLFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config/config.yml": &fstest.MapFile{
+			Data: []byte(fmt.Sprintf(`
+logLevel: %s
+password: %s
+db:
+  port: %d
+`, updatedLogLevel, updatedPassword, updatedPort)),
+		},
+	}
+	cb := NewBuilder(defaultConfig)
+	cb.AddConfigYAMLFS(fsys, "config/config.yml")
+	cb.AddConfigYAMLFS(fsys, "config/missing.yml")
+	assertUnmarshaledConfig(t, cb)
+}
+
+func TestConfig_AddConfigJSON(t *testing.T) {
+	jsonContent := fmt.Sprintf(`{
+	"logLevel": %q,
+	"pAssWOrD": %q,
+	"db": {"port": %d}
+}`, updatedLogLevel, updatedPassword, updatedPort)
+	cb := NewBuilder(defaultConfig)
+	cb.AddConfigJSON(strings.NewReader(jsonContent))
+	assertUnmarshaledConfig(t, cb)
+}
+
+func TestConfig_AddConfigJSONFile(t *testing.T) {
+	cb := NewBuilder(defaultConfig)
+	cb.AddConfigJSONFile("testdata/add-config-json-file.json")
+	assertUnmarshaledConfig(t, cb)
+}
+
+func TestConfig_AddConfigJSONFile_mergesWithYAML(t *testing.T) {
+	cb := NewBuilder(defaultConfig)
+	cb.AddConfigYAML(strings.NewReader("username: from yaml\npassword: from yaml"))
+	cb.AddConfigJSONFile("testdata/add-config-json-file.json")
+
+	var cfg TestConfig
+	require.NoError(t, cb.Unmarshal(&cfg))
+	assert.Equal(t, "from yaml", cfg.Username)
+	assert.Equal(t, updatedPassword, cfg.Password)
+}
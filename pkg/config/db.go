@@ -0,0 +1,115 @@
+package config
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DB is a reusable config struct for the PostgreSQL database connection of a
+// Wharf component, meant to be embedded in the config struct of each component
+// so that they expose identically named settings:
+//
+//  type Config struct {
+//  	DB config.DB // set via "WHARF_DB_HOST", etc.
+//  }
+//
+//  defaultConfig := Config{DB: config.DefaultDB}
+//
+//  db, err := gorm.Open(postgres.Open(cfg.DB.DSN()), &gorm.Config{})
+//  if err != nil {
+//  	// ...
+//  }
+//  sqlDB, err := db.DB()
+//  if err != nil {
+//  	// ...
+//  }
+//  cfg.DB.ApplyPool(sqlDB)
+type DB struct {
+	// Host is the hostname or IP address of the database server.
+	Host string `validate:"required" usage:"Hostname or IP address of the database server."`
+	// Port is the port of the database server.
+	Port int `validate:"min=1,max=65535" usage:"Port of the database server."`
+	// Username is the username used when connecting to the database.
+	Username string `usage:"Username used when connecting to the database."`
+	// Password is the password used when connecting to the database.
+	Password string `usage:"Password used when connecting to the database."`
+	// Name is the name of the database to connect to.
+	Name string `validate:"required" usage:"Name of the database to connect to."`
+	// SSLMode is the PostgreSQL SSL mode, such as "disable", "require", or
+	// "verify-full". Uses the driver's default when empty.
+	SSLMode string `validate:"omitempty,oneof=disable allow prefer require verify-ca verify-full" usage:"PostgreSQL SSL mode, such as disable, require, or verify-full."`
+	// MaxIdleConns is the maximum number of idle connections in the
+	// connection pool. Uses the database/sql default when zero.
+	MaxIdleConns int `validate:"gte=0" usage:"Maximum number of idle connections in the pool."`
+	// MaxOpenConns is the maximum number of open connections to the database.
+	// Unlimited when zero.
+	MaxOpenConns int `validate:"gte=0" usage:"Maximum number of open connections. Unlimited when zero."`
+	// ConnMaxLifetime is the maximum duration a connection may be reused.
+	// Connections are reused forever when zero.
+	ConnMaxLifetime time.Duration `validate:"gte=0" usage:"Maximum duration a connection may be reused."`
+}
+
+// DefaultDB is the default DB config, meant to be used in the default config
+// passed to NewBuilder.
+var DefaultDB = DB{
+	Host:            "localhost",
+	Port:            5432,
+	Username:        "postgres",
+	Name:            "wharf",
+	MaxIdleConns:    2,
+	MaxOpenConns:    10,
+	ConnMaxLifetime: time.Hour,
+}
+
+// DSN returns the PostgreSQL connection string of the config, in the
+// space-separated keyword/value format, such as:
+//
+//  host=localhost port=5432 user=postgres password='my secret' dbname=wharf
+//
+// Empty values are left out. Values that contain spaces or quotes are quoted.
+func (c DB) DSN() string {
+	var sb strings.Builder
+	writeDSNValue(&sb, "host", c.Host)
+	if c.Port != 0 {
+		writeDSNValue(&sb, "port", fmt.Sprint(c.Port))
+	}
+	writeDSNValue(&sb, "user", c.Username)
+	writeDSNValue(&sb, "password", c.Password)
+	writeDSNValue(&sb, "dbname", c.Name)
+	writeDSNValue(&sb, "sslmode", c.SSLMode)
+	return sb.String()
+}
+
+// ApplyPool sets the connection pool settings of the config on the database.
+func (c DB) ApplyPool(db *sql.DB) {
+	if c.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(c.MaxIdleConns)
+	}
+	db.SetMaxOpenConns(c.MaxOpenConns)
+	db.SetConnMaxLifetime(c.ConnMaxLifetime)
+}
+
+func writeDSNValue(sb *strings.Builder, key, value string) {
+	if value == "" {
+		return
+	}
+	if sb.Len() > 0 {
+		sb.WriteByte(' ')
+	}
+	sb.WriteString(key)
+	sb.WriteByte('=')
+	if !strings.ContainsAny(value, ` '\`) {
+		sb.WriteString(value)
+		return
+	}
+	sb.WriteByte('\'')
+	for _, r := range value {
+		if r == '\'' || r == '\\' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	sb.WriteByte('\'')
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDB_DSN(t *testing.T) {
+	testCases := []struct {
+		name string
+		db   DB
+		want string
+	}{
+		{
+			name: "defaults",
+			db:   DefaultDB,
+			want: "host=localhost port=5432 user=postgres dbname=wharf",
+		},
+		{
+			name: "all fields",
+			db: DB{
+				Host:     "db.example.com",
+				Port:     6543,
+				Username: "wharf",
+				Password: "secret",
+				Name:     "wharf-api",
+				SSLMode:  "require",
+			},
+			want: "host=db.example.com port=6543 user=wharf password=secret dbname=wharf-api sslmode=require",
+		},
+		{
+			name: "quoted values",
+			db: DB{
+				Host:     "localhost",
+				Password: `it's a \ secret`,
+			},
+			want: `host=localhost password='it\'s a \\ secret'`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.db.DSN())
+		})
+	}
+}
+
+func TestDB_validate(t *testing.T) {
+	assert.NoError(t, Validate(struct{ DB DB }{DefaultDB}))
+
+	invalid := DefaultDB
+	invalid.SSLMode = "sometimes"
+	invalid.MaxOpenConns = -1
+	var err *ValidationError
+	assert.ErrorAs(t, Validate(struct{ DB DB }{invalid}), &err)
+	assert.Len(t, err.Violations, 2)
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/mitchellh/mapstructure"
+)
+
+var (
+	urlType      = reflect.TypeOf(url.URL{})
+	levelType    = reflect.TypeOf(logger.Level(0))
+	byteSizeType = reflect.TypeOf(ByteSize(0))
+)
+
+// decodeHook returns the hooks used when decoding the config, allowing fields
+// of the following types to be set from strings:
+//
+//  time.Duration  // "30s", "1h30m"
+//  url.URL        // "https://example.com/path", also as *url.URL
+//  net.IP         // "127.0.0.1"
+//  logger.Level   // "info", "warn", as parsed by logger.ParseLevel
+//  ByteSize       // "512MiB", as parsed by ParseByteSize
+//  []string       // "a, b, c", comma-separated and trimmed
+//
+// Byte slices, such as []byte, are not split and get the raw string instead.
+func decodeHook() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToIPHookFunc(),
+		stringToURLHookFunc,
+		stringToLevelHookFunc,
+		stringToByteSizeHookFunc,
+		stringToSliceHookFunc,
+	)
+}
+
+func stringToURLHookFunc(f, t reflect.Type, data any) (any, error) {
+	if f.Kind() != reflect.String || t != urlType {
+		return data, nil
+	}
+	u, err := url.Parse(data.(string))
+	if err != nil {
+		return nil, err
+	}
+	return *u, nil
+}
+
+func stringToLevelHookFunc(f, t reflect.Type, data any) (any, error) {
+	if f.Kind() != reflect.String || t != levelType {
+		return data, nil
+	}
+	return logger.ParseLevel(data.(string))
+}
+
+func stringToByteSizeHookFunc(f, t reflect.Type, data any) (any, error) {
+	if f.Kind() != reflect.String || t != byteSizeType {
+		return data, nil
+	}
+	return ParseByteSize(data.(string))
+}
+
+func stringToSliceHookFunc(f, t reflect.Type, data any) (any, error) {
+	if f.Kind() != reflect.String || t.Kind() != reflect.Slice ||
+		t.Elem().Kind() == reflect.Uint8 {
+		return data, nil
+	}
+	values := []string{}
+	for _, value := range strings.Split(data.(string), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+// stringifyDefaults replaces the url.URL values in the YAML decoded defaults
+// with their string representation, as they are otherwise marshaled as a map
+// of their fields, which does not merge with URL strings from the config
+// sources, and loses any user info.
+func stringifyDefaults(m any, v reflect.Value) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(opts, "inline") {
+			stringifyDefaults(m, v.Field(i))
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		value, ok := mapValue(m, name)
+		if !ok {
+			continue
+		}
+		if u, ok := urlValue(v.Field(i)); ok {
+			setMapValue(m, name, u.String())
+			continue
+		}
+		stringifyDefaults(value, v.Field(i))
+	}
+}
+
+func urlValue(v reflect.Value) (*url.URL, bool) {
+	if !v.CanInterface() {
+		return nil, false
+	}
+	if v.Type() == urlType {
+		u := v.Interface().(url.URL)
+		return &u, true
+	}
+	if v.Type() == reflect.PointerTo(urlType) && !v.IsNil() {
+		return v.Interface().(*url.URL), true
+	}
+	return nil, false
+}
+
+func mapValue(m any, key string) (any, bool) {
+	switch m := m.(type) {
+	case map[string]any:
+		value, ok := m[key]
+		return value, ok
+	case map[any]any:
+		value, ok := m[key]
+		return value, ok
+	default:
+		return nil, false
+	}
+}
+
+func setMapValue(m any, key string, value any) {
+	switch m := m.(type) {
+	case map[string]any:
+		m[key] = value
+	case map[any]any:
+		m[key] = value
+	}
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// defaultTag is the struct tag used to declare default values of config
+// fields, as an alternative to setting them in the default config given to
+// NewBuilder.
+const defaultTag = "default"
+
+// withTagDefaults returns a copy of the default config, with the values from
+// the "default" struct tags applied to all fields that have their zero value.
+func withTagDefaults(defaultConfig any) (any, error) {
+	v := reflect.ValueOf(defaultConfig)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return defaultConfig, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return defaultConfig, nil
+	}
+	cfg := reflect.New(v.Type()).Elem()
+	cfg.Set(v)
+	if _, err := applyTagDefaults(cfg, ""); err != nil {
+		return nil, err
+	}
+	return cfg.Interface(), nil
+}
+
+// applyTagDefaults sets the zero-valued fields of the addressable struct value
+// from their "default" struct tags, recursively, and reports if any field was
+// set.
+func applyTagDefaults(v reflect.Value, path string) (bool, error) {
+	applied := false
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+		fieldValue := v.Field(i)
+		if tag, ok := field.Tag.Lookup(defaultTag); ok {
+			if !fieldValue.IsZero() {
+				continue
+			}
+			if err := decodeTagDefault(fieldValue, tag); err != nil {
+				return false, fmt.Errorf("default tag of config field %s: %w", fieldPath, err)
+			}
+			applied = true
+			continue
+		}
+		ok, err := applyNestedTagDefaults(fieldValue, fieldPath)
+		if err != nil {
+			return false, err
+		}
+		applied = applied || ok
+	}
+	return applied, nil
+}
+
+func applyNestedTagDefaults(v reflect.Value, path string) (bool, error) {
+	switch {
+	case v.Kind() == reflect.Struct:
+		return applyTagDefaults(v, path)
+	case v.Kind() == reflect.Pointer && v.Type().Elem().Kind() == reflect.Struct:
+		if !v.IsNil() {
+			return applyTagDefaults(v.Elem(), path)
+		}
+		// only allocate nil pointers if there are any defaults to apply
+		elem := reflect.New(v.Type().Elem())
+		ok, err := applyTagDefaults(elem.Elem(), path)
+		if ok {
+			v.Set(elem)
+		}
+		return ok, err
+	default:
+		return false, nil
+	}
+}
+
+func decodeTagDefault(v reflect.Value, tag string) error {
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       decodeHook(),
+		WeaklyTypedInput: true,
+		Result:           v.Addr().Interface(),
+	})
+	if err != nil {
+		return err
+	}
+	return dec.Decode(tag)
+}
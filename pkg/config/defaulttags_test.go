@@ -0,0 +1,59 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testTagDefaultsDB struct {
+	Host    string        `default:"localhost"`
+	Port    int           `default:"5432"`
+	Timeout time.Duration `default:"30s"`
+}
+
+type testTagDefaultsConfig struct {
+	Enabled bool     `default:"true"`
+	Hosts   []string `default:"a, b"`
+	MaxSize ByteSize `default:"1MiB"`
+	DB      testTagDefaultsDB
+	Cache   *testTagDefaultsDB
+	NoTags  *struct{ Value string }
+}
+
+func TestConfig_defaultTags(t *testing.T) {
+	cb := NewBuilderFromYAML(testTagDefaultsConfig{
+		DB: testTagDefaultsDB{Port: 1234},
+	}, `
+db:
+  timeout: 1m
+`)
+	var cfg testTagDefaultsConfig
+	require.NoError(t, cb.Unmarshal(&cfg))
+	assert.True(t, cfg.Enabled)
+	assert.Equal(t, []string{"a", "b"}, cfg.Hosts)
+	assert.Equal(t, Mebibyte, cfg.MaxSize)
+	assert.Equal(t, testTagDefaultsDB{
+		Host:    "localhost",
+		Port:    1234, // set in the default config literal
+		Timeout: time.Minute,
+	}, cfg.DB)
+	require.NotNil(t, cfg.Cache)
+	assert.Equal(t, testTagDefaultsDB{
+		Host:    "localhost",
+		Port:    5432,
+		Timeout: 30 * time.Second,
+	}, *cfg.Cache)
+	assert.Nil(t, cfg.NoTags)
+}
+
+func TestConfig_defaultTags_invalid(t *testing.T) {
+	type invalidConfig struct {
+		Port int `default:"not a number"`
+	}
+	var cfg invalidConfig
+	err := NewBuilder(invalidConfig{}).Unmarshal(&cfg)
+	assert.ErrorContains(t, err, "default tag of config field Port")
+}
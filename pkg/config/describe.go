@@ -0,0 +1,135 @@
+package config
+
+import (
+	"encoding"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// usageTag is the struct tag used to document config fields, as returned by
+// Describe.
+const usageTag = "usage"
+
+// KeyDescription describes a single config key, as returned by Describe.
+type KeyDescription struct {
+	// Key is the config key, such as "db.port".
+	Key string `json:"key"`
+	// Type is the Go type of the field, such as "int" or "time.Duration".
+	Type string `json:"type"`
+	// Default is the default value of the field, from the default config or
+	// from the "default" struct tag.
+	Default any `json:"default"`
+	// EnvVar is the name of the environment variable that sets the field,
+	// without the prefix, such as "DB_PORT". When using a prefix with
+	// Builder.AddEnvironmentVariables, then the full name is the prefix and
+	// an underscore followed by this name, such as "MYAPP_DB_PORT".
+	EnvVar string `json:"envVar"`
+	// Usage is the documentation of the field, from the "usage" struct tag.
+	Usage string `json:"usage,omitempty"`
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Describe returns a description of all config keys of the default config,
+// using reflection, which can be used to print the available config keys or
+// to generate documentation. Fields are documented using the "usage" struct
+// tag. Example:
+//
+//  type Config struct {
+//  	Port int `usage:"Port to listen on." default:"8080"`
+//  }
+//
+//  for _, d := range config.Describe(Config{}) {
+//  	fmt.Printf("%s (%s, env %s): %s Defaults to %v.\n",
+//  		d.Key, d.Type, d.EnvVar, d.Usage, d.Default)
+//  }
+//
+// The keys are returned in the same order as the fields are declared.
+func Describe(defaultConfig any) []KeyDescription {
+	if withDefaults, err := withTagDefaults(defaultConfig); err == nil {
+		defaultConfig = withDefaults
+	}
+	v := reflect.ValueOf(defaultConfig)
+	if !v.IsValid() {
+		return nil
+	}
+	return describeStruct(nil, v.Type(), v, "")
+}
+
+func describeStruct(descs []KeyDescription, t reflect.Type, v reflect.Value, prefix string) []KeyDescription {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		if v.IsValid() && !v.IsNil() {
+			v = v.Elem()
+		} else {
+			v = reflect.Value{}
+		}
+	}
+	if t.Kind() != reflect.Struct {
+		return descs
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		var fieldValue reflect.Value
+		if v.IsValid() {
+			fieldValue = v.Field(i)
+		}
+		if strings.Contains(opts, "inline") {
+			descs = describeStruct(descs, field.Type, fieldValue, prefix)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		key := strings.ToLower(name)
+		if prefix != "" {
+			key = prefix + keyDelimiter + key
+		}
+		if !isLeafType(field.Type) {
+			descs = describeStruct(descs, field.Type, fieldValue, key)
+			continue
+		}
+		descs = append(descs, KeyDescription{
+			Key:     key,
+			Type:    field.Type.String(),
+			Default: describeDefault(fieldValue),
+			EnvVar:  envVarsSource{}.envVarName(key),
+			Usage:   field.Tag.Get(usageTag),
+		})
+	}
+	return descs
+}
+
+// isLeafType returns false for struct types, and pointers to struct types,
+// that are described as nested config keys.
+func isLeafType(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return true
+	}
+	return t == urlType || t == timeType ||
+		reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func describeDefault(v reflect.Value) any {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return nil
+	}
+	return v.Interface()
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestDescribeLogging struct {
+	Level string `usage:"Minimum logging level."`
+}
+
+type testDescribeDB struct {
+	Host    string        `usage:"Database host name."`
+	Port    int           `default:"5432"`
+	Timeout time.Duration `yaml:"connTimeout" usage:"Connection timeout."`
+}
+
+type testDescribeConfig struct {
+	TestDescribeLogging `mapstructure:",squash" yaml:",inline"`
+	URL                 url.URL
+	Hosts               []string
+	DB                  testDescribeDB
+	Cache               *testDescribeDB
+	Ignored             string `yaml:"-"`
+}
+
+func TestDescribe(t *testing.T) {
+	descs := Describe(testDescribeConfig{
+		TestDescribeLogging: TestDescribeLogging{Level: "info"},
+		DB:                  testDescribeDB{Timeout: time.Second},
+	})
+	assert.Equal(t, []KeyDescription{
+		{Key: "level", Type: "string", Default: "info", EnvVar: "LEVEL", Usage: "Minimum logging level."},
+		{Key: "url", Type: "url.URL", Default: url.URL{}, EnvVar: "URL"},
+		{Key: "hosts", Type: "[]string", Default: []string(nil), EnvVar: "HOSTS"},
+		{Key: "db.host", Type: "string", Default: "", EnvVar: "DB_HOST", Usage: "Database host name."},
+		{Key: "db.port", Type: "int", Default: 5432, EnvVar: "DB_PORT"},
+		{Key: "db.conntimeout", Type: "time.Duration", Default: time.Second, EnvVar: "DB_CONNTIMEOUT", Usage: "Connection timeout."},
+		{Key: "cache.host", Type: "string", Default: "", EnvVar: "CACHE_HOST", Usage: "Database host name."},
+		{Key: "cache.port", Type: "int", Default: 5432, EnvVar: "CACHE_PORT"},
+		{Key: "cache.conntimeout", Type: "time.Duration", Default: time.Duration(0), EnvVar: "CACHE_CONNTIMEOUT", Usage: "Connection timeout."},
+	}, descs)
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SourceError is returned when a config source could not be applied, such as
+// from invalid YAML syntax in an added YAML file.
+type SourceError struct {
+	// Source is the name of the config source, such as the path of a YAML
+	// file.
+	Source string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the name of the source and the underlying error.
+func (e *SourceError) Error() string {
+	return fmt.Sprintf("applying config source: %s: %T: %s", e.Source, e.Err, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+// SourcesError is returned when multiple config sources could not be applied.
+type SourcesError struct {
+	Errors []*SourceError
+}
+
+// Error returns all the errors in a single message.
+func (e *SourcesError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d config sources failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Is reports whether any of the source errors matches the target, allowing
+// errors.Is checks.
+func (e *SourcesError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first source error that matches the target, allowing errors.As
+// checks.
+func (e *SourcesError) As(target any) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
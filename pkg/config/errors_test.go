@@ -0,0 +1,41 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Unmarshal_aggregatesSourceErrors(t *testing.T) {
+	errTest := errors.New("test error")
+	cb := NewBuilder(defaultConfig)
+	cb.AddConfigYAML(strings.NewReader("db: [invalid"))
+	cb.AddConfigYAMLFile("testdata") // directory, so cannot be read
+	cb.AddConfigYAML(strings.NewReader("password: valid"))
+	cb.AddSource(testSource{err: errTest})
+
+	var cfg TestConfig
+	err := cb.Unmarshal(&cfg)
+	var sourcesErr *SourcesError
+	require.ErrorAs(t, err, &sourcesErr)
+	require.Len(t, sourcesErr.Errors, 3)
+	assert.Equal(t, "YAML io.Reader", sourcesErr.Errors[0].Source)
+	assert.Equal(t, "testdata", sourcesErr.Errors[1].Source)
+	assert.Equal(t, "test source", sourcesErr.Errors[2].Source)
+	assert.ErrorIs(t, err, errTest)
+	assert.ErrorContains(t, err, "3 config sources failed")
+}
+
+func TestConfig_Unmarshal_singleSourceError(t *testing.T) {
+	cb := NewBuilder(defaultConfig)
+	cb.AddConfigYAML(strings.NewReader("db: [invalid"))
+
+	var cfg TestConfig
+	err := cb.Unmarshal(&cfg)
+	var sourceErr *SourceError
+	require.ErrorAs(t, err, &sourceErr)
+	assert.Equal(t, "YAML io.Reader", sourceErr.Source)
+}
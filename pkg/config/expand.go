@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// expandEnvValues expands the environment variable references in all string
+// values, recursively, as described in Builder.AddConfigYAML.
+func expandEnvValues(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		if value == nil {
+			return map[string]any{}
+		}
+		for k, v := range value {
+			value[k] = expandEnvValues(v)
+		}
+		return value
+	case map[any]any:
+		for k, v := range value {
+			value[k] = expandEnvValues(v)
+		}
+		return value
+	case []any:
+		for i, v := range value {
+			value[i] = expandEnvValues(v)
+		}
+		return value
+	case string:
+		return expandEnv(value)
+	default:
+		return value
+	}
+}
+
+// expandEnv replaces "${VAR}" and "${VAR:-default}" with the value of the
+// environment variable, and "$${" with a literal "${". References without a
+// closing brace are left as-is.
+func expandEnv(s string) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+	var sb strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i == -1 {
+			break
+		}
+		if i > 0 && s[i-1] == '$' {
+			sb.WriteString(s[:i-1])
+			sb.WriteString("${")
+			s = s[i+2:]
+			continue
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end == -1 {
+			break
+		}
+		sb.WriteString(s[:i])
+		sb.WriteString(lookupEnvRef(s[i+2 : i+end]))
+		s = s[i+end+1:]
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
+
+func lookupEnvRef(ref string) string {
+	name, def, hasDefault := strings.Cut(ref, ":-")
+	if value := os.Getenv(name); value != "" || !hasDefault {
+		return value
+	}
+	return def
+}
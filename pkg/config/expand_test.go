@@ -0,0 +1,55 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iver-wharf/wharf-core/v2/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandEnv(t *testing.T) {
+	testutil.SetEnv(t, "EXPAND_SET", "value")
+	testutil.SetEnv(t, "EXPAND_EMPTY", "")
+	testCases := []struct {
+		input string
+		want  string
+	}{
+		{input: "no refs", want: "no refs"},
+		{input: "${EXPAND_SET}", want: "value"},
+		{input: "a-${EXPAND_SET}-b-${EXPAND_SET}", want: "a-value-b-value"},
+		{input: "${EXPAND_UNSET}", want: ""},
+		{input: "${EXPAND_UNSET:-default}", want: "default"},
+		{input: "${EXPAND_EMPTY:-default}", want: "default"},
+		{input: "${EXPAND_SET:-default}", want: "value"},
+		{input: "$${EXPAND_SET}", want: "${EXPAND_SET}"},
+		{input: "pa$$word", want: "pa$$word"},
+		{input: "${EXPAND_SET", want: "${EXPAND_SET"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.want, expandEnv(tc.input))
+		})
+	}
+}
+
+func TestConfig_AddConfigYAML_expandsEnv(t *testing.T) {
+	testutil.SetEnv(t, "EXPAND_PASSWORD", updatedPassword)
+	cb := NewBuilder(defaultConfig)
+	cb.AddConfigYAML(strings.NewReader(`
+logLevel: ${EXPAND_LOGLEVEL:-updated log level}
+password: ${EXPAND_PASSWORD}
+db:
+  port: 8080
+`))
+	assertUnmarshaledConfig(t, cb)
+}
+
+func TestConfig_AddConfigYAML_empty(t *testing.T) {
+	cb := NewBuilder(defaultConfig)
+	cb.AddConfigYAML(strings.NewReader(""))
+	var cfg TestConfig
+	require.NoError(t, cb.Unmarshal(&cfg))
+	assert.Equal(t, defaultConfig, cfg)
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"reflect"
+)
+
+// KeyOrigin is the effective value of a config key, and the config source
+// that supplied it, as returned by Builder.Explain.
+type KeyOrigin struct {
+	// Key is the config key, such as "db.port".
+	Key string
+	// Value is the effective value of the key, as given by the config source.
+	Value any
+	// Source is the name of the config source that last changed the value,
+	// such as the path of a YAML file, or "defaults" if it has not been
+	// changed from the default config.
+	Source string
+}
+
+func (b *builder) Explain() ([]KeyOrigin, error) {
+	values := map[string]any{}
+	origins := map[string]string{}
+	st, err := b.newStore(func(st *store, source string) {
+		for key, value := range st.values() {
+			if prev, ok := values[key]; ok && reflect.DeepEqual(prev, value) {
+				continue
+			}
+			values[key] = value
+			origins[key] = source
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	keys := st.keys()
+	result := make([]KeyOrigin, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, KeyOrigin{
+			Key:    key,
+			Value:  values[key],
+			Source: origins[key],
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iver-wharf/wharf-core/v2/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Explain(t *testing.T) {
+	testutil.SetEnv(t, "EXPLAIN_PASSWORD", "env password")
+
+	cb := NewBuilder(defaultConfig)
+	cb.AddConfigYAMLFile("testdata/add-config-yaml-file.yml")
+	cb.AddConfigYAML(strings.NewReader(`
+username: default username
+db:
+  port: 1
+`))
+	cb.AddEnvironmentVariables("EXPLAIN")
+
+	origins, err := cb.Explain()
+	require.NoError(t, err)
+	assert.Equal(t, []KeyOrigin{
+		{Key: "db.host", Value: defaultDBHost, Source: "defaults"},
+		{Key: "db.port", Value: 1, Source: "YAML io.Reader"},
+		{Key: "loglevel", Value: updatedLogLevel, Source: "testdata/add-config-yaml-file.yml"},
+		{Key: "password", Value: "env password", Source: `environment variables with prefix "EXPLAIN_"`},
+		// same value as the default, so not regarded as changed
+		{Key: "username", Value: defaultUsername, Source: "defaults"},
+	}, origins)
+}
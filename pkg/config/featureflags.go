@@ -0,0 +1,163 @@
+package config
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FeatureFlag is the definition of a single named feature flag, used to gate
+// experimental behavior.
+type FeatureFlag struct {
+	// Name is the name of the flag. Flag names are case-insensitive, as they
+	// are commonly read from YAML files and environment variables.
+	Name string
+	// Description is a human-readable explanation of what the flag toggles.
+	Description string
+	// Default is the value used when the flag has not been set by any config
+	// source.
+	Default bool
+}
+
+// FeatureFlagChangeFunc is the signature of the function called when a feature
+// flag changes its effective value.
+type FeatureFlagChangeFunc func(name string, enabled bool)
+
+// FeatureFlags is a set of named boolean flags with defaults and descriptions.
+// It is safe for concurrent use.
+//
+// Meant to be paired with a map[string]bool field in your config struct, that
+// is populated using the Builder and then fed into FeatureFlags.Update:
+//
+//  type MyConfig struct {
+//  	Features map[string]bool
+//  }
+//
+//  flags := config.NewFeatureFlags(
+//  	config.FeatureFlag{Name: "newScheduler", Description: "Use the new build scheduler."},
+//  )
+//  defaultConfig := MyConfig{Features: flags.Defaults()}
+//
+//  var cfg MyConfig
+//  builder := config.NewBuilder(defaultConfig)
+//  builder.AddEnvironmentVariables("MYAPP")
+//  if err := builder.Unmarshal(&cfg); err != nil {
+//  	// ...
+//  }
+//  flags.Update(cfg.Features)
+//
+// When reloading the configuration, calling Update again will notify all
+// functions registered via OnChange about the flags that changed value.
+type FeatureFlags struct {
+	mu        sync.RWMutex
+	defs      []FeatureFlag
+	values    map[string]bool
+	listeners []FeatureFlagChangeFunc
+}
+
+// NewFeatureFlags creates a new set of feature flags, where all flags initially
+// use their default values.
+func NewFeatureFlags(flags ...FeatureFlag) *FeatureFlags {
+	f := &FeatureFlags{
+		defs:   flags,
+		values: make(map[string]bool, len(flags)),
+	}
+	for _, flag := range flags {
+		f.values[featureFlagKey(flag.Name)] = flag.Default
+	}
+	return f
+}
+
+// IsEnabled returns true if the named flag is enabled. Unknown flags are
+// considered disabled.
+func (f *FeatureFlags) IsEnabled(name string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.values[featureFlagKey(name)]
+}
+
+// Defaults returns a new map of all defined flags and their default values.
+// Useful when composing the default configuration given to NewBuilder, so that
+// the flags can be set via environment variables.
+func (f *FeatureFlags) Defaults() map[string]bool {
+	defaults := make(map[string]bool, len(f.defs))
+	for _, flag := range f.defs {
+		defaults[flag.Name] = flag.Default
+	}
+	return defaults
+}
+
+// Flags returns the definitions of all flags, sorted by name. Useful for
+// documenting the available flags.
+func (f *FeatureFlags) Flags() []FeatureFlag {
+	flags := make([]FeatureFlag, len(f.defs))
+	copy(flags, f.defs)
+	sort.Slice(flags, func(i, j int) bool {
+		return featureFlagKey(flags[i].Name) < featureFlagKey(flags[j].Name)
+	})
+	return flags
+}
+
+// Update sets the values of the flags, such as from a freshly unmarshaled
+// config. Any defined flags missing from the map falls back to their default
+// values.
+//
+// All functions registered via OnChange are called for each flag that changed
+// its effective value, after the new values have been applied.
+func (f *FeatureFlags) Update(values map[string]bool) {
+	newValues := make(map[string]bool, len(f.defs)+len(values))
+	for _, flag := range f.defs {
+		newValues[featureFlagKey(flag.Name)] = flag.Default
+	}
+	for name, enabled := range values {
+		newValues[featureFlagKey(name)] = enabled
+	}
+
+	f.mu.Lock()
+	type change struct {
+		name    string
+		enabled bool
+	}
+	var changes []change
+	for key, enabled := range newValues {
+		if old, ok := f.values[key]; !ok || old != enabled {
+			changes = append(changes, change{f.flagName(key), enabled})
+		}
+	}
+	for key := range f.values {
+		if _, ok := newValues[key]; !ok {
+			changes = append(changes, change{f.flagName(key), false})
+		}
+	}
+	f.values = newValues
+	listeners := f.listeners
+	f.mu.Unlock()
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].name < changes[j].name })
+	for _, c := range changes {
+		for _, listener := range listeners {
+			listener(c.name, c.enabled)
+		}
+	}
+}
+
+// OnChange registers a function that is called whenever a flag changes its
+// effective value via Update.
+func (f *FeatureFlags) OnChange(listener FeatureFlagChangeFunc) {
+	f.mu.Lock()
+	f.listeners = append(f.listeners, listener)
+	f.mu.Unlock()
+}
+
+func (f *FeatureFlags) flagName(key string) string {
+	for _, flag := range f.defs {
+		if featureFlagKey(flag.Name) == key {
+			return flag.Name
+		}
+	}
+	return key
+}
+
+func featureFlagKey(name string) string {
+	return strings.ToLower(name)
+}
@@ -0,0 +1,43 @@
+package config_test
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/config"
+)
+
+func ExampleFeatureFlags() {
+	type MyConfig struct {
+		Features map[string]bool
+	}
+
+	flags := config.NewFeatureFlags(
+		config.FeatureFlag{Name: "newScheduler", Description: "Use the new build scheduler."},
+		config.FeatureFlag{Name: "legacyUI", Description: "Serve the old UI.", Default: true},
+	)
+	flags.OnChange(func(name string, enabled bool) {
+		fmt.Printf("Changed: %s=%t\n", name, enabled)
+	})
+
+	cfgBuilder := config.NewBuilder(MyConfig{Features: flags.Defaults()})
+	cfgBuilder.AddEnvironmentVariables("MYAPP")
+
+	os.Setenv("MYAPP_FEATURES_NEWSCHEDULER", "true")
+	defer os.Unsetenv("MYAPP_FEATURES_NEWSCHEDULER")
+
+	var cfg MyConfig
+	if err := cfgBuilder.Unmarshal(&cfg); err != nil {
+		fmt.Println("Failed to read config:", err)
+		return
+	}
+	flags.Update(cfg.Features)
+
+	fmt.Println("newScheduler:", flags.IsEnabled("newScheduler"))
+	fmt.Println("legacyUI:    ", flags.IsEnabled("legacyUI"))
+
+	// Output:
+	// Changed: newScheduler=true
+	// newScheduler: true
+	// legacyUI:     true
+}
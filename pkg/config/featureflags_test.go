@@ -0,0 +1,42 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatureFlags_IsEnabled(t *testing.T) {
+	flags := NewFeatureFlags(
+		FeatureFlag{Name: "on", Default: true},
+		FeatureFlag{Name: "off"},
+	)
+	assert.True(t, flags.IsEnabled("on"))
+	assert.True(t, flags.IsEnabled("ON"), "case-insensitive")
+	assert.False(t, flags.IsEnabled("off"))
+	assert.False(t, flags.IsEnabled("unknown"))
+}
+
+func TestFeatureFlags_Update(t *testing.T) {
+	flags := NewFeatureFlags(
+		FeatureFlag{Name: "fooBar", Default: true},
+		FeatureFlag{Name: "lorem"},
+	)
+	var changes []string
+	flags.OnChange(func(name string, enabled bool) {
+		if enabled {
+			changes = append(changes, "+"+name)
+		} else {
+			changes = append(changes, "-"+name)
+		}
+	})
+
+	flags.Update(map[string]bool{"foobar": false, "lorem": false})
+	assert.Equal(t, []string{"-fooBar"}, changes)
+	assert.False(t, flags.IsEnabled("fooBar"))
+
+	changes = nil
+	flags.Update(nil)
+	assert.Equal(t, []string{"+fooBar"}, changes, "falls back to defaults")
+	assert.True(t, flags.IsEnabled("fooBar"))
+}
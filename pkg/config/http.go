@@ -0,0 +1,97 @@
+package config
+
+import (
+	"net/http"
+	"time"
+)
+
+// HTTP is a reusable config struct for the HTTP server of a Wharf component,
+// meant to be embedded in the config struct of each component so that they
+// expose identically named settings:
+//
+//  type Config struct {
+//  	HTTP config.HTTP // set via "WHARF_HTTP_BINDADDRESS", etc.
+//  }
+//
+//  defaultConfig := Config{HTTP: config.DefaultHTTP}
+//
+//  srv := cfg.HTTP.NewServer(router)
+//  if err := srv.ListenAndServe(); err != nil {
+//  	// ...
+//  }
+type HTTP struct {
+	// BindAddress is the IP address and port to listen on, such as
+	// "0.0.0.0:8080", or ":8080" to listen on all interfaces.
+	BindAddress string `validate:"required,hostname_port" usage:"IP address and port to listen on."`
+	// ReadTimeout is the maximum duration for reading an entire request,
+	// including the body. Disabled when zero.
+	ReadTimeout time.Duration `validate:"gte=0" usage:"Maximum duration for reading an entire request."`
+	// ReadHeaderTimeout is the maximum duration for reading the request
+	// headers. Uses the ReadTimeout when zero.
+	ReadHeaderTimeout time.Duration `validate:"gte=0" usage:"Maximum duration for reading the request headers."`
+	// WriteTimeout is the maximum duration before timing out writes of the
+	// response. Disabled when zero.
+	WriteTimeout time.Duration `validate:"gte=0" usage:"Maximum duration for writing the response."`
+	// IdleTimeout is the maximum duration to wait for the next request on
+	// keep-alive connections. Uses the ReadTimeout when zero.
+	IdleTimeout time.Duration `validate:"gte=0" usage:"Maximum duration to wait for the next request on keep-alive connections."`
+	// CORS is the Cross-Origin Resource Sharing (CORS) config of the HTTP
+	// server.
+	CORS CORS
+}
+
+// CORS is a reusable config struct for the Cross-Origin Resource Sharing
+// (CORS) settings of the HTTP server of a Wharf component.
+//
+// It uses the same fields as the ginutil.CORSConfig struct, and can be
+// converted to it without depending on the Gin package from this package:
+//
+//  router.Use(ginutil.CORSWithConfig(ginutil.CORSConfig(cfg.HTTP.CORS)))
+type CORS struct {
+	// AllowOrigins is the list of origins that are allowed to make
+	// cross-origin requests, such as "https://*.example.com", or "*" to allow
+	// all origins. CORS is disabled when empty.
+	AllowOrigins []string `usage:"Origins allowed to make cross-origin requests. Disabled when empty."`
+	// AllowMethods is the list of HTTP methods allowed in cross-origin
+	// requests.
+	AllowMethods []string `usage:"HTTP methods allowed in cross-origin requests."`
+	// AllowHeaders is the list of request headers allowed in cross-origin
+	// requests.
+	AllowHeaders []string `usage:"Request headers allowed in cross-origin requests."`
+	// ExposeHeaders is the list of response headers that the browser is
+	// allowed to expose to the client-side scripts.
+	ExposeHeaders []string `usage:"Response headers exposed to client-side scripts."`
+	// AllowCredentials allows cookies and the Authorization header to be
+	// included in cross-origin requests when set to true.
+	AllowCredentials bool `usage:"Allow cookies and the Authorization header in cross-origin requests."`
+	// MaxAge is how long the browser may cache the result of a preflight
+	// request. Left up to the browser when zero.
+	MaxAge time.Duration `validate:"gte=0" usage:"How long browsers may cache preflight responses."`
+}
+
+// DefaultHTTP is the default HTTP config, meant to be used in the default
+// config passed to NewBuilder.
+var DefaultHTTP = HTTP{
+	BindAddress:       "0.0.0.0:8080",
+	ReadTimeout:       30 * time.Second,
+	ReadHeaderTimeout: 10 * time.Second,
+	WriteTimeout:      30 * time.Second,
+	IdleTimeout:       2 * time.Minute,
+	CORS: CORS{
+		AllowMethods: []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"},
+		AllowHeaders: []string{"Accept", "Authorization", "Content-Type", "X-Request-ID"},
+	},
+}
+
+// NewServer creates a new HTTP server that uses the bind address and timeouts
+// from the config.
+func (c HTTP) NewServer(handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              c.BindAddress,
+		Handler:           handler,
+		ReadTimeout:       c.ReadTimeout,
+		ReadHeaderTimeout: c.ReadHeaderTimeout,
+		WriteTimeout:      c.WriteTimeout,
+		IdleTimeout:       c.IdleTimeout,
+	}
+}
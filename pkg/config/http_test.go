@@ -0,0 +1,60 @@
+package config
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/ginutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testHTTPConfig struct {
+	HTTP HTTP
+}
+
+func TestHTTP_defaultsAreValid(t *testing.T) {
+	assert.NoError(t, Validate(testHTTPConfig{HTTP: DefaultHTTP}))
+}
+
+func TestHTTP_unmarshal(t *testing.T) {
+	b := NewBuilder(testHTTPConfig{HTTP: DefaultHTTP})
+	b.AddConfigYAML(strings.NewReader(`
+http:
+  bindAddress: localhost:5000
+  readTimeout: 1m
+  cors:
+    allowOrigins: https://*.example.com, http://localhost
+`))
+	var cfg testHTTPConfig
+	require.NoError(t, b.Unmarshal(&cfg))
+	want := DefaultHTTP
+	want.BindAddress = "localhost:5000"
+	want.ReadTimeout = time.Minute
+	want.CORS.AllowOrigins = []string{"https://*.example.com", "http://localhost"}
+	want.CORS.ExposeHeaders = []string{} // nil slices in defaults decode as empty
+	assert.Equal(t, want, cfg.HTTP)
+}
+
+func TestHTTP_invalidBindAddress(t *testing.T) {
+	cfg := testHTTPConfig{HTTP: DefaultHTTP}
+	cfg.HTTP.BindAddress = "localhost"
+	assert.ErrorIs(t, Validate(cfg), ErrInvalidConfig)
+}
+
+func TestHTTP_NewServer(t *testing.T) {
+	handler := http.NotFoundHandler()
+	srv := DefaultHTTP.NewServer(handler)
+	assert.Equal(t, DefaultHTTP.BindAddress, srv.Addr)
+	assert.Equal(t, DefaultHTTP.ReadTimeout, srv.ReadTimeout)
+	assert.Equal(t, DefaultHTTP.ReadHeaderTimeout, srv.ReadHeaderTimeout)
+	assert.Equal(t, DefaultHTTP.WriteTimeout, srv.WriteTimeout)
+	assert.Equal(t, DefaultHTTP.IdleTimeout, srv.IdleTimeout)
+}
+
+func TestCORS_convertsToGinutilCORSConfig(t *testing.T) {
+	cors := ginutil.CORSConfig(DefaultHTTP.CORS)
+	assert.Equal(t, DefaultHTTP.CORS.AllowMethods, cors.AllowMethods)
+}
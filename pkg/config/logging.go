@@ -0,0 +1,115 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger/console"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger/consolejson"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger/consolepretty"
+)
+
+// LogFormat is the output format of the logs, as configured via
+// Logging.Format.
+type LogFormat string
+
+const (
+	// LogFormatAuto outputs human-readable logs when STDOUT is a terminal, and
+	// JSON-formatted logs otherwise, as decided by the console.NewAuto
+	// function.
+	LogFormatAuto LogFormat = ""
+	// LogFormatPretty outputs human-readable logs using the consolepretty
+	// package.
+	LogFormatPretty LogFormat = "pretty"
+	// LogFormatJSON outputs JSON-formatted logs using the consolejson package.
+	LogFormatJSON LogFormat = "json"
+)
+
+// ErrUnknownLogFormat is returned by ApplyLogging when the Logging.Format is
+// not one of the supported log formats.
+var ErrUnknownLogFormat = errors.New("unknown log format")
+
+// Logging is a reusable config struct for configuring the logger package,
+// meant to be embedded in the config struct of each Wharf component and then
+// applied using ApplyLogging:
+//
+//  type Config struct {
+//  	Logging config.Logging
+//  }
+//
+//  defaultConfig := Config{Logging: config.DefaultLogging}
+//
+//  var cfg Config
+//  if err := config.NewBuilder(defaultConfig).Unmarshal(&cfg); err != nil {
+//  	// ...
+//  }
+//  if err := config.ApplyLogging(cfg.Logging); err != nil {
+//  	// ...
+//  }
+//
+// Which can then be configured via YAML:
+//
+//  logging:
+//    level: info
+//    scopes:
+//      gorm: warn
+//    format: json
+//    caller: false
+type Logging struct {
+	// Level is the minimum logging level of all log events, such as "debug",
+	// "info", or "warn".
+	Level logger.Level `usage:"Minimum logging level, such as debug, info, or warn."`
+	// Scopes is the minimum logging level per logger scope, as created via
+	// logger.NewScoped. The scope names are case-insensitive. A scope can only
+	// raise the minimum logging level above the global Level.
+	Scopes map[string]logger.Level `usage:"Minimum logging level per logger scope."`
+	// Format is the output format of the logs. Defaults to LogFormatAuto.
+	Format LogFormat `validate:"omitempty,oneof=pretty json" usage:"Log format: pretty or json. Detected from STDOUT when unset."`
+	// Caller adds the caller file name and line number to the logs when set
+	// to true.
+	Caller bool `usage:"Add the caller file name and line number to the logs."`
+}
+
+// DefaultLogging is the default Logging config, meant to be used in the
+// default config passed to NewBuilder.
+var DefaultLogging = Logging{
+	Level:  logger.LevelInfo,
+	Format: LogFormatAuto,
+	Caller: true,
+}
+
+// ApplyLogging configures the logger package from the Logging config, by
+// setting the global and scoped logging levels and by adding a console sink
+// using the configured format.
+//
+// Meant to be called once when the program starts, as each call adds another
+// sink via logger.AddOutput.
+func ApplyLogging(cfg Logging) error {
+	sink, err := loggingSink(cfg)
+	if err != nil {
+		return err
+	}
+	logger.SetLevel(cfg.Level)
+	for scope, level := range cfg.Scopes {
+		logger.SetLevelScoped(level, scope)
+	}
+	logger.AddOutput(logger.LevelDebug, sink)
+	return nil
+}
+
+func loggingSink(cfg Logging) (logger.Sink, error) {
+	prettyConf := consolepretty.DefaultConfig
+	prettyConf.DisableCaller = !cfg.Caller
+	jsonConf := consolejson.Config{DisableCaller: !cfg.Caller}
+	switch cfg.Format {
+	case LogFormatAuto:
+		return console.NewAuto(prettyConf, jsonConf), nil
+	case LogFormatPretty:
+		return consolepretty.New(prettyConf), nil
+	case LogFormatJSON:
+		return consolejson.New(jsonConf), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownLogFormat, cfg.Format)
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLoggingConfig struct {
+	Logging Logging
+}
+
+func TestLogging_unmarshal(t *testing.T) {
+	b := NewBuilder(testLoggingConfig{Logging: DefaultLogging})
+	b.AddConfigYAML(strings.NewReader(`
+logging:
+  level: warn
+  scopes:
+    GORM: error
+  format: json
+  caller: false
+`))
+	var cfg testLoggingConfig
+	require.NoError(t, b.Unmarshal(&cfg))
+	want := Logging{
+		Level:  logger.LevelWarn,
+		Scopes: map[string]logger.Level{"gorm": logger.LevelError},
+		Format: LogFormatJSON,
+		Caller: false,
+	}
+	assert.Equal(t, want, cfg.Logging)
+}
+
+func TestLogging_unmarshalInvalidFormat(t *testing.T) {
+	b := NewBuilder(testLoggingConfig{Logging: DefaultLogging})
+	b.AddConfigYAML(strings.NewReader("logging:\n  format: xml"))
+	var cfg testLoggingConfig
+	assert.ErrorIs(t, b.Unmarshal(&cfg), ErrInvalidConfig)
+}
+
+func TestApplyLogging(t *testing.T) {
+	t.Cleanup(func() {
+		logger.ClearOutputs()
+		logger.SetLevel(logger.LevelDebug)
+		logger.SetLevelScoped(logger.LevelDebug, "applyLogging")
+	})
+	logger.ClearOutputs()
+	err := ApplyLogging(Logging{
+		Level:  logger.LevelInfo,
+		Scopes: map[string]logger.Level{"applyLogging": logger.LevelError},
+		Format: LogFormatJSON,
+	})
+	require.NoError(t, err)
+	mock := logger.NewMock()
+	logger.AddOutput(logger.LevelDebug, mock)
+
+	logger.New().Debug().Message("global debug")
+	logger.NewScoped("applyLogging").Warn().Message("scoped warn")
+	logger.NewScoped("applyLogging").Error().Message("scoped error")
+
+	assert.Equal(t, []string{"scoped error"}, mock.LogMessages)
+}
+
+func TestApplyLogging_unknownFormat(t *testing.T) {
+	err := ApplyLogging(Logging{Format: "xml"})
+	assert.ErrorIs(t, err, ErrUnknownLogFormat)
+}
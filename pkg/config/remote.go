@@ -0,0 +1,194 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// RemoteProviderEtcd is the provider name for reading config from the
+	// etcd v3 key-value store, via its HTTP/JSON gateway.
+	RemoteProviderEtcd = "etcd"
+	// RemoteProviderConsul is the provider name for reading config from the
+	// Consul key-value store, via its HTTP API.
+	RemoteProviderConsul = "consul"
+)
+
+// ErrUnsupportedRemoteProvider is returned when a remote config source was
+// added via Builder.AddRemoteProvider using an unsupported provider name.
+var ErrUnsupportedRemoteProvider = errors.New("unsupported remote provider")
+
+// ErrUnexpectedRemoteStatus is returned when the remote key-value store
+// responds with a non-successful HTTP status code.
+var ErrUnexpectedRemoteStatus = errors.New("unexpected HTTP status from remote provider")
+
+// remoteTimeout is the timeout for each request to the remote key-value store.
+const remoteTimeout = 10 * time.Second
+
+type remoteSource struct {
+	provider string
+	endpoint string
+	path     string
+	client   *http.Client
+}
+
+func (b *builder) AddRemoteProvider(provider, endpoint, path string) {
+	b.sources = append(b.sources, remoteSource{
+		provider: provider,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		path:     path,
+		client:   &http.Client{Timeout: remoteTimeout},
+	})
+}
+
+func (s remoteSource) name() string {
+	return fmt.Sprintf("%s %s/%s", s.provider, s.endpoint, s.path)
+}
+
+func (s remoteSource) apply(st *store) error {
+	content, err := s.fetch(context.Background())
+	if err != nil || content == nil {
+		return err
+	}
+	return mergeYAML(st, bytes.NewReader(content))
+}
+
+// fetch returns the YAML or JSON formatted content of the key, or nil if the
+// key was not found.
+func (s remoteSource) fetch(ctx context.Context) ([]byte, error) {
+	switch s.provider {
+	case RemoteProviderConsul:
+		return s.fetchConsul(ctx)
+	case RemoteProviderEtcd:
+		return s.fetchEtcd(ctx)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedRemoteProvider, s.provider)
+	}
+}
+
+func (s remoteSource) fetchConsul(ctx context.Context) ([]byte, error) {
+	// Consul keys are paths without a leading slash, while etcd keys are
+	// opaque and used as-is.
+	path := strings.TrimPrefix(s.path, "/")
+	u := fmt.Sprintf("%s/v1/kv/%s?raw", s.endpoint, (&url.URL{Path: path}).EscapedPath())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.do(req)
+}
+
+type etcdRangeRequest struct {
+	Key string `json:"key"`
+}
+
+type etcdRangeResponse struct {
+	Kvs []struct {
+		Value string `json:"value"`
+	} `json:"kvs"`
+}
+
+func (s remoteSource) fetchEtcd(ctx context.Context) ([]byte, error) {
+	body, err := json.Marshal(etcdRangeRequest{
+		Key: base64.StdEncoding.EncodeToString([]byte(s.path)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		s.endpoint+"/v3/kv/range", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	content, err := s.do(req)
+	if err != nil || content == nil {
+		return nil, err
+	}
+	var resp etcdRangeResponse
+	if err := json.Unmarshal(content, &resp); err != nil {
+		return nil, fmt.Errorf("decode etcd response: %w", err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+	value, err := base64.StdEncoding.DecodeString(resp.Kvs[0].Value)
+	if err != nil {
+		return nil, fmt.Errorf("decode etcd value: %w", err)
+	}
+	return value, nil
+}
+
+func (s remoteSource) do(req *http.Request) ([]byte, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedRemoteStatus, resp.Status)
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if content == nil {
+		content = []byte{}
+	}
+	return content, nil
+}
+
+func (b *builder) WatchRemoteProviders(ctx context.Context, interval time.Duration, onChange func(error)) {
+	var sources []remoteSource
+	for _, s := range b.sources {
+		if remote, ok := s.(remoteSource); ok {
+			sources = append(sources, remote)
+		}
+	}
+	if len(sources) == 0 {
+		return
+	}
+	last := make([][]byte, len(sources))
+	for i, s := range sources {
+		last[i], _ = s.fetch(ctx)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		changed := false
+		for i, s := range sources {
+			content, err := s.fetch(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				onChange(fmt.Errorf("watching config source: %s: %w", s.name(), err))
+				continue
+			}
+			if !bytes.Equal(content, last[i]) || (content == nil) != (last[i] == nil) {
+				last[i] = content
+				changed = true
+			}
+		}
+		if changed {
+			onChange(nil)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const remoteTestContent = `
+logLevel: updated log level
+password: updated password
+db:
+  port: 8080
+`
+
+func newConsulTestServer(t *testing.T, content *string, mu *sync.Mutex) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/kv/wharf/config.yml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		w.Write([]byte(*content))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestConfig_AddRemoteProvider_consul(t *testing.T) {
+	content := remoteTestContent
+	server := newConsulTestServer(t, &content, &sync.Mutex{})
+
+	cb := NewBuilder(defaultConfig)
+	cb.AddRemoteProvider(RemoteProviderConsul, server.URL, "/wharf/config.yml")
+	assertUnmarshaledConfig(t, cb)
+}
+
+func TestConfig_AddRemoteProvider_etcd(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req etcdRangeRequest
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&req)) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		assert.Equal(t, "/v3/kv/range", r.URL.Path)
+		key, err := base64.StdEncoding.DecodeString(req.Key)
+		if !assert.NoError(t, err) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		assert.Equal(t, "/wharf/config.yml", string(key))
+		json.NewEncoder(w).Encode(map[string]any{
+			"kvs": []map[string]string{
+				{"value": base64.StdEncoding.EncodeToString([]byte(remoteTestContent))},
+			},
+		})
+	}))
+	defer server.Close()
+
+	cb := NewBuilder(defaultConfig)
+	cb.AddRemoteProvider(RemoteProviderEtcd, server.URL, "/wharf/config.yml")
+	assertUnmarshaledConfig(t, cb)
+}
+
+func TestConfig_AddRemoteProvider_notFound(t *testing.T) {
+	content := remoteTestContent
+	server := newConsulTestServer(t, &content, &sync.Mutex{})
+
+	cb := NewBuilder(defaultConfig)
+	cb.AddRemoteProvider(RemoteProviderConsul, server.URL, "missing.yml")
+	var cfg TestConfig
+	require.NoError(t, cb.Unmarshal(&cfg))
+	assert.Equal(t, defaultConfig, cfg)
+}
+
+func TestConfig_AddRemoteProvider_unsupported(t *testing.T) {
+	cb := NewBuilder(defaultConfig)
+	cb.AddRemoteProvider("zookeeper", "http://localhost", "config.yml")
+	var cfg TestConfig
+	assert.ErrorIs(t, cb.Unmarshal(&cfg), ErrUnsupportedRemoteProvider)
+}
+
+func TestConfig_WatchRemoteProviders(t *testing.T) {
+	var mu sync.Mutex
+	content := "password: first"
+	server := newConsulTestServer(t, &content, &mu)
+
+	cb := NewBuilder(defaultConfig)
+	cb.AddRemoteProvider(RemoteProviderConsul, server.URL, "wharf/config.yml")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan error, 1)
+	done := make(chan struct{})
+	go func() {
+		cb.WatchRemoteProviders(ctx, time.Millisecond, func(err error) {
+			changes <- err
+		})
+		close(done)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	mu.Lock()
+	content = "password: second"
+	mu.Unlock()
+
+	select {
+	case err := <-changes:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for change")
+	}
+	var cfg TestConfig
+	require.NoError(t, cb.Unmarshal(&cfg))
+	assert.Equal(t, "second", cfg.Password)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("watch did not return after context was canceled")
+	}
+}
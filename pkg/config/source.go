@@ -0,0 +1,44 @@
+package config
+
+// Source is a custom config source, such as for reading config stored in a
+// database or from an HTTP config service, that can be added to a Builder via
+// Builder.AddSource.
+type Source interface {
+	// Name returns a human readable name of the source, used in errors and
+	// by Builder.Explain.
+	Name() string
+	// Read returns the config values as a nested map, such as:
+	//
+	//  map[string]any{
+	//  	"db": map[string]any{
+	//  		"port": 8080,
+	//  	},
+	//  }
+	//
+	// The keys are case-insensitive. The values should be of the same types
+	// as when decoded from YAML, as a value is not merged on top of a value of
+	// a different type from an earlier source. A nil map means the source
+	// has no config values, the same way as a non-existing YAML file.
+	Read() (map[string]any, error)
+}
+
+type customSource struct {
+	source Source
+}
+
+func (s customSource) name() string {
+	return s.source.Name()
+}
+
+func (s customSource) apply(st *store) error {
+	values, err := s.source.Read()
+	if err != nil || values == nil {
+		return err
+	}
+	st.merge(values)
+	return nil
+}
+
+func (b *builder) AddSource(s Source) {
+	b.sources = append(b.sources, customSource{s})
+}
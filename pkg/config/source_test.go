@@ -0,0 +1,47 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testSource struct {
+	values map[string]any
+	err    error
+}
+
+func (s testSource) Name() string {
+	return "test source"
+}
+
+func (s testSource) Read() (map[string]any, error) {
+	return s.values, s.err
+}
+
+func TestConfig_AddSource(t *testing.T) {
+	cb := NewBuilder(defaultConfig)
+	cb.AddConfigYAML(strings.NewReader("password: overridden by test source"))
+	cb.AddSource(testSource{values: map[string]any{
+		"logLevel": updatedLogLevel,
+		"password": updatedPassword,
+		"db": map[string]any{
+			"port": 1,
+		},
+	}})
+	cb.AddSource(testSource{})
+	cb.AddConfigYAML(strings.NewReader("db:\n  port: 8080"))
+	assertUnmarshaledConfig(t, cb)
+}
+
+func TestConfig_AddSource_error(t *testing.T) {
+	errTest := errors.New("test error")
+	cb := NewBuilder(defaultConfig)
+	cb.AddSource(testSource{err: errTest})
+	var cfg TestConfig
+	err := cb.Unmarshal(&cfg)
+	assert.ErrorIs(t, err, errTest)
+	assert.ErrorContains(t, err, "test source")
+}
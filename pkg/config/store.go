@@ -0,0 +1,186 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// keyDelimiter is the delimiter between the names of nested keys, such as in
+// "db.port".
+const keyDelimiter = "."
+
+// store holds the values read from the default config and the config sources,
+// as nested maps with lowercased keys, and decodes them into the config
+// struct.
+//
+// The values are kept in three layers, with increasing precedence: the
+// defaults, the config values from the config sources, and the overrides from
+// the environment variable sources.
+type store struct {
+	defaults  map[string]any
+	config    map[string]any
+	overrides map[string]any
+}
+
+func newStore() *store {
+	return &store{
+		defaults:  map[string]any{},
+		config:    map[string]any{},
+		overrides: map[string]any{},
+	}
+}
+
+// setDefaults merges the values into the defaults layer.
+func (st *store) setDefaults(values map[string]any) {
+	mergeValues(st.defaults, values)
+}
+
+// merge merges the values on top of the previously merged config values,
+// where nested maps are merged per key, and all other values replace the
+// previous values. Nil values are ignored.
+func (st *store) merge(values map[string]any) {
+	mergeValues(st.config, values)
+}
+
+// set sets the value of the dot-delimited key, such as "db.port", in the
+// overrides layer.
+func (st *store) set(key string, value any) {
+	path := strings.Split(strings.ToLower(key), keyDelimiter)
+	m := st.overrides
+	for _, k := range path[:len(path)-1] {
+		next, ok := m[k].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			m[k] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = value
+}
+
+// settings returns the values of all layers merged together, without any
+// empty maps.
+func (st *store) settings() map[string]any {
+	m := map[string]any{}
+	mergeValues(m, st.defaults)
+	mergeValues(m, st.config)
+	mergeValues(m, st.overrides)
+	pruneEmptyMaps(m)
+	return m
+}
+
+// values returns the values of all layers merged together, flattened to
+// dot-delimited keys of only the leaf values, such as "db.port".
+func (st *store) values() map[string]any {
+	flat := map[string]any{}
+	flattenValues(flat, "", st.settings())
+	return flat
+}
+
+// keys returns the sorted dot-delimited keys of all leaf values.
+func (st *store) keys() []string {
+	values := st.values()
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// decode decodes the merged values into the config, which must be a pointer.
+func (st *store) decode(config any, metadata *mapstructure.Metadata) error {
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       decodeHook(),
+		WeaklyTypedInput: true,
+		Metadata:         metadata,
+		Result:           config,
+	})
+	if err != nil {
+		return err
+	}
+	return dec.Decode(st.settings())
+}
+
+func mergeValues(dst, src map[string]any) {
+	for key, value := range src {
+		key = strings.ToLower(key)
+		srcMap, ok := asStringMap(value)
+		if !ok {
+			if value != nil {
+				dst[key] = normalizeValue(value)
+			}
+			continue
+		}
+		dstMap, ok := dst[key].(map[string]any)
+		if !ok {
+			// copies the map, so later merges do not alter the source
+			dstMap = map[string]any{}
+			dst[key] = dstMap
+		}
+		mergeValues(dstMap, srcMap)
+	}
+}
+
+// asStringMap returns the value as a map[string]any, such as for the
+// map[any]any values decoded from YAML.
+func asStringMap(value any) (map[string]any, bool) {
+	switch value := value.(type) {
+	case map[string]any:
+		return value, true
+	case map[any]any:
+		m := make(map[string]any, len(value))
+		for k, v := range value {
+			m[fmt.Sprint(k)] = v
+		}
+		return m, true
+	default:
+		return nil, false
+	}
+}
+
+// normalizeValue returns a copy of the value where all nested maps are
+// converted to map[string]any with lowercased keys, the same way as the maps
+// merged via mergeValues.
+func normalizeValue(value any) any {
+	if m, ok := asStringMap(value); ok {
+		normalized := map[string]any{}
+		mergeValues(normalized, m)
+		return normalized
+	}
+	if s, ok := value.([]any); ok {
+		normalized := make([]any, len(s))
+		for i, v := range s {
+			normalized[i] = normalizeValue(v)
+		}
+		return normalized
+	}
+	return value
+}
+
+func pruneEmptyMaps(m map[string]any) {
+	for key, value := range m {
+		if nested, ok := value.(map[string]any); ok {
+			pruneEmptyMaps(nested)
+			if len(nested) == 0 {
+				delete(m, key)
+			}
+		}
+	}
+}
+
+func flattenValues(flat map[string]any, prefix string, m map[string]any) {
+	for key, value := range m {
+		if prefix != "" {
+			key = prefix + keyDelimiter + key
+		}
+		if nested, ok := value.(map[string]any); ok {
+			flattenValues(flat, key, nested)
+			continue
+		}
+		flat[key] = value
+	}
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownKey is returned when unmarshaling in strict mode and a config
+// source contains a key that does not map to any field in the config struct.
+// The actual error is of type *UnknownKeysError, which wraps this error.
+var ErrUnknownKey = errors.New("unknown config key")
+
+// UnknownKey is a config key that does not map to any field in the config
+// struct, as found when unmarshaling in strict mode.
+type UnknownKey struct {
+	// Key is the config key, such as "db.hots", or the name of the
+	// environment variable, such as "WHARF_DB_HOTS".
+	Key string
+	// Source is the name of the config source that first set the key, such
+	// as the path of a YAML file.
+	Source string
+}
+
+// String returns the key in the form `"key" (from source)`.
+func (k UnknownKey) String() string {
+	return fmt.Sprintf("%q (from %s)", k.Key, k.Source)
+}
+
+// UnknownKeysError contains all unknown keys found when unmarshaling in
+// strict mode.
+type UnknownKeysError struct {
+	Keys []UnknownKey
+}
+
+// Error returns all the unknown keys in a single message.
+func (e *UnknownKeysError) Error() string {
+	keys := make([]string, len(e.Keys))
+	for i, k := range e.Keys {
+		keys[i] = k.String()
+	}
+	return fmt.Sprintf("%s: %s", ErrUnknownKey, strings.Join(keys, ", "))
+}
+
+// Unwrap returns ErrUnknownKey, allowing errors.Is checks.
+func (e *UnknownKeysError) Unwrap() error {
+	return ErrUnknownKey
+}
+
+const defaultsSourceName = "defaults"
+
+// keySources keeps track of which config source first set each config key.
+type keySources struct {
+	names []string
+	keys  map[string]int
+}
+
+func (ks *keySources) add(st *store, source string) {
+	if ks.keys == nil {
+		ks.keys = map[string]int{}
+	}
+	ks.names = append(ks.names, source)
+	for _, key := range st.keys() {
+		if _, ok := ks.keys[key]; !ok {
+			ks.keys[key] = len(ks.names) - 1
+		}
+	}
+}
+
+func (ks *keySources) lookup(key string) string {
+	if i, ok := ks.keys[key]; ok {
+		return ks.names[i]
+	}
+	// the unused key may be a map, while the store only lists the leaf keys
+	first := -1
+	for k, i := range ks.keys {
+		if strings.HasPrefix(k, key+".") && (first == -1 || i < first) {
+			first = i
+		}
+	}
+	if first == -1 {
+		return "unknown source"
+	}
+	return ks.names[first]
+}
+
+func (b *builder) unknownKeys(st *store, unused []string, sources *keySources) error {
+	var unknown []UnknownKey
+	for i, key := range unused {
+		// mapstructure uses the struct field names for the parent keys
+		unused[i] = strings.ToLower(key)
+		unknown = append(unknown, UnknownKey{unused[i], sources.lookup(unused[i])})
+	}
+	for _, s := range b.sources {
+		if env, ok := s.(envVarsSource); ok {
+			unknown = append(unknown, unknownEnvVars(knownKeys(st, unused), env)...)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Slice(unknown, func(i, j int) bool {
+		return unknown[i].Key < unknown[j].Key
+	})
+	return &UnknownKeysError{unknown}
+}
+
+func knownKeys(st *store, unused []string) []string {
+	var known []string
+	for _, key := range st.keys() {
+		if !isUnusedKey(key, unused) {
+			known = append(known, key)
+		}
+	}
+	return known
+}
+
+func isUnusedKey(key string, unused []string) bool {
+	for _, u := range unused {
+		if key == u || strings.HasPrefix(key, u+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func unknownEnvVars(knownKeys []string, s envVarsSource) []UnknownKey {
+	if s.prefix == "" {
+		// all environment variables would be regarded as unknown
+		return nil
+	}
+	envPrefix := s.envPrefix()
+	known := map[string]struct{}{}
+	for _, key := range knownKeys {
+		known[s.envVarName(key)] = struct{}{}
+		known[s.envVarName(key)+envVarFileSuffix] = struct{}{}
+	}
+	var unknown []UnknownKey
+	for _, env := range os.Environ() {
+		name, _, _ := strings.Cut(env, "=")
+		if !strings.HasPrefix(name, envPrefix) {
+			continue
+		}
+		if _, ok := known[name]; !ok {
+			unknown = append(unknown, UnknownKey{name, s.name()})
+		}
+	}
+	return unknown
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iver-wharf/wharf-core/v2/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_SetStrict(t *testing.T) {
+	testutil.SetEnv(t, "STRICT_DB_HOTS", "typo")
+	testutil.SetEnv(t, "STRICT_DB_PORT", "8080")
+
+	cb := NewBuilder(defaultConfig)
+	cb.SetStrict(true)
+	cb.AddConfigYAMLFile("testdata/add-config-yaml-file.yml")
+	cb.AddConfigYAML(strings.NewReader(`
+usernme: typo
+db:
+  hots: typo
+extra:
+  nested: value
+`))
+	cb.AddEnvironmentVariables("STRICT")
+
+	var cfg TestConfig
+	err := cb.Unmarshal(&cfg)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	var unknownErr *UnknownKeysError
+	require.ErrorAs(t, err, &unknownErr)
+	assert.Equal(t, []UnknownKey{
+		{Key: "STRICT_DB_HOTS", Source: `environment variables with prefix "STRICT_"`},
+		{Key: "db.hots", Source: "YAML io.Reader"},
+		{Key: "extra", Source: "YAML io.Reader"},
+		{Key: "usernme", Source: "YAML io.Reader"},
+	}, unknownErr.Keys)
+}
+
+func TestConfig_SetStrict_noUnknownKeys(t *testing.T) {
+	testutil.SetEnv(t, "STRICT_DB_PORT", "8080")
+
+	cb := NewBuilder(defaultConfig)
+	cb.SetStrict(true)
+	cb.AddConfigYAMLFile("testdata/add-config-yaml-file.yml")
+	cb.AddEnvironmentVariables("STRICT")
+
+	var cfg TestConfig
+	require.NoError(t, cb.Unmarshal(&cfg))
+	assert.Equal(t, 8080, cfg.DB.Port)
+}
+
+func TestConfig_SetStrict_disabled(t *testing.T) {
+	cb := NewBuilder(defaultConfig)
+	cb.AddConfigYAML(strings.NewReader("usernme: typo"))
+
+	var cfg TestConfig
+	assert.NoError(t, cb.Unmarshal(&cfg))
+}
@@ -0,0 +1,56 @@
+package config
+
+import "strings"
+
+// NewTestBuilder creates a new Builder based on a default configuration, and
+// applies the override functions onto the unmarshaled config. The overrides
+// are applied after all other config sources, and therefore take precedence.
+//
+// Meant to be used in tests of config-dependent components, as an alternative
+// to writing temporary config files:
+//
+//  c := config.NewTestBuilder(defaultConfig, func(c *MyConfig) {
+//  	c.DB.Port = 8080
+//  })
+//
+// The overrides are only applied when unmarshaling into a value of type *T.
+// The config is validated after the overrides have been applied.
+func NewTestBuilder[T any](base T, overrides ...func(*T)) Builder {
+	return testBuilder[T]{
+		builder:   &builder{defaultConfig: base},
+		overrides: overrides,
+	}
+}
+
+type testBuilder[T any] struct {
+	*builder
+	overrides []func(*T)
+}
+
+func (b testBuilder[T]) Unmarshal(config any) error {
+	if err := b.builder.unmarshal(config); err != nil {
+		return err
+	}
+	if ptr, ok := config.(*T); ok {
+		for _, override := range b.overrides {
+			override(ptr)
+		}
+	}
+	return Validate(config)
+}
+
+// NewBuilderFromYAML creates a new Builder based on a default configuration,
+// with the YAML formatted string added as a config source via
+// Builder.AddConfigYAML.
+//
+// Meant to be used in tests with inline YAML content:
+//
+//  c := config.NewBuilderFromYAML(defaultConfig, `
+//  db:
+//    port: 8080
+//  `)
+func NewBuilderFromYAML(defaultConfig any, yamlContent string) Builder {
+	b := NewBuilder(defaultConfig)
+	b.AddConfigYAML(strings.NewReader(yamlContent))
+	return b
+}
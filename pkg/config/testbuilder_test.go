@@ -0,0 +1,40 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTestBuilder(t *testing.T) {
+	cb := NewTestBuilder(defaultConfig, func(c *TestConfig) {
+		c.LogLevel = updatedLogLevel
+		c.Password = updatedPassword
+	}, func(c *TestConfig) {
+		c.DB.Port = updatedPort
+	})
+	assertUnmarshaledConfig(t, cb)
+}
+
+func TestNewTestBuilder_overridesTakePrecedence(t *testing.T) {
+	cb := NewTestBuilder(defaultConfig, func(c *TestConfig) {
+		c.Username = "from override"
+	})
+	cb.AddConfigYAML(strings.NewReader("username: from yaml"))
+
+	var cfg TestConfig
+	require.NoError(t, cb.Unmarshal(&cfg))
+	assert.Equal(t, "from override", cfg.Username)
+}
+
+func TestNewBuilderFromYAML(t *testing.T) {
+	cb := NewBuilderFromYAML(defaultConfig, `
+logLevel: updated log level
+password: updated password
+db:
+  port: 8080
+`)
+	assertUnmarshaledConfig(t, cb)
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrConfigTypeMismatch is returned by Unmarshal when the type of the default
+// config given to NewBuilder differs from the type being unmarshaled into.
+var ErrConfigTypeMismatch = errors.New("config type does not match default config type")
+
+// Unmarshal creates a new config of type T and applies the configuration from
+// the Builder on to it, as with Builder.Unmarshal.
+//
+// Returns an error wrapping ErrConfigTypeMismatch if the Builder was created
+// using a default configuration of a different type than T. Example:
+//
+//  b := config.NewBuilder(defaultConfig)
+//  b.AddConfigYAMLFile("config.yml")
+//  cfg, err := config.Unmarshal[Config](b)
+func Unmarshal[T any](b Builder) (T, error) {
+	var cfg T
+	if d, ok := b.(interface{ defaultConfigType() reflect.Type }); ok {
+		want := indirectType(reflect.TypeOf(cfg))
+		if got := d.defaultConfigType(); got != nil && got != want {
+			return cfg, fmt.Errorf("%w: default config is %s, but unmarshaling into %s",
+				ErrConfigTypeMismatch, got, want)
+		}
+	}
+	err := b.Unmarshal(&cfg)
+	return cfg, err
+}
+
+// MustUnmarshal is the same as Unmarshal, but panics on errors. Useful when
+// reading config on startup, where there is no way to recover.
+func MustUnmarshal[T any](b Builder) T {
+	cfg, err := Unmarshal[T](b)
+	if err != nil {
+		panic(fmt.Sprintf("config: unmarshal: %s", err))
+	}
+	return cfg
+}
+
+func (b *builder) defaultConfigType() reflect.Type {
+	if b.defaultConfig == nil {
+		return nil
+	}
+	return indirectType(reflect.TypeOf(b.defaultConfig))
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
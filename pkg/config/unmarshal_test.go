@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshal(t *testing.T) {
+	cfg, err := Unmarshal[TestConfig](NewBuilderFromYAML(defaultConfig, "password: updated password"))
+	require.NoError(t, err)
+	assert.Equal(t, updatedPassword, cfg.Password)
+	assert.Equal(t, defaultUsername, cfg.Username)
+}
+
+func TestUnmarshal_pointerDefaultConfig(t *testing.T) {
+	cfg, err := Unmarshal[TestConfig](NewBuilder(&defaultConfig))
+	require.NoError(t, err)
+	assert.Equal(t, defaultConfig, cfg)
+}
+
+func TestUnmarshal_typeMismatch(t *testing.T) {
+	_, err := Unmarshal[TestDBConfig](NewBuilder(defaultConfig))
+	assert.ErrorIs(t, err, ErrConfigTypeMismatch)
+}
+
+func TestMustUnmarshal(t *testing.T) {
+	assert.Equal(t, defaultConfig, MustUnmarshal[TestConfig](NewBuilder(defaultConfig)))
+	assert.Panics(t, func() {
+		MustUnmarshal[TestDBConfig](NewBuilder(defaultConfig))
+	})
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrInvalidConfig is returned when a config failed validation. The actual
+// error is of type *ValidationError, which wraps this error.
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Validator is implemented by config structs that need custom validation,
+// which is called by Validate and Builder.Unmarshal after the config has been
+// unmarshaled. Nested structs are also checked for this interface.
+//
+// Example:
+//
+//  type DBConfig struct {
+//  	Host string
+//  	Port int
+//  }
+//
+//  func (c DBConfig) Validate() error {
+//  	if c.Host == "" && c.Port != 0 {
+//  		return errors.New("port set without host")
+//  	}
+//  	return nil
+//  }
+type Validator interface {
+	Validate() error
+}
+
+// Violation is a single failed validation of a config field.
+type Violation struct {
+	// Field is the path to the field, such as "DB.Port", or an empty string
+	// if the violation is from the Validator implementation of the top-level
+	// config struct.
+	Field string
+	// Message describes why the field failed validation.
+	Message string
+}
+
+// String returns the violation in the form "field: message".
+func (v Violation) String() string {
+	if v.Field == "" {
+		return v.Message
+	}
+	return v.Field + ": " + v.Message
+}
+
+// ValidationError contains all violations found when validating a config.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Error returns all the violations in a single message.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.String()
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidConfig, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns ErrInvalidConfig, allowing errors.Is checks.
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidConfig
+}
+
+var validate = validator.New()
+
+// Validate checks the config using the "validate" struct tags, as defined by
+// the github.com/go-playground/validator/v10 package, as well as the Validator
+// interface implementations of the config struct and any of its nested
+// structs. All violations are aggregated into a single *ValidationError.
+//
+// Builder.Unmarshal calls this function automatically, so you only need to
+// call this function yourself if you modify the config afterwards.
+//
+// Example:
+//
+//  type Config struct {
+//  	BindAddress string `validate:"required"`
+//  	Workers     int    `validate:"min=1"`
+//  }
+func Validate(config any) error {
+	var violations []Violation
+	var validationErrs validator.ValidationErrors
+	if err := validate.Struct(config); errors.As(err, &validationErrs) {
+		for _, fieldErr := range validationErrs {
+			violations = append(violations, newTagViolation(fieldErr))
+		}
+	} else if err != nil && !isInvalidValidationError(err) {
+		return fmt.Errorf("validate config: %w", err)
+	}
+	violations = appendValidatorViolations(violations, reflect.ValueOf(config), "", true)
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ValidationError{violations}
+}
+
+func isInvalidValidationError(err error) bool {
+	var invalidErr *validator.InvalidValidationError
+	return errors.As(err, &invalidErr)
+}
+
+func newTagViolation(fieldErr validator.FieldError) Violation {
+	msg := fmt.Sprintf("failed the %q validation rule", fieldErr.Tag())
+	if fieldErr.Param() != "" {
+		msg = fmt.Sprintf("failed the %q validation rule with parameter %q",
+			fieldErr.Tag(), fieldErr.Param())
+	}
+	return Violation{
+		Field:   fieldViolationName(fieldErr),
+		Message: msg,
+	}
+}
+
+// fieldViolationName returns the namespace of the field without the name of
+// the top-level struct, such as "DB.Port" instead of "Config.DB.Port".
+func fieldViolationName(fieldErr validator.FieldError) string {
+	ns := fieldErr.StructNamespace()
+	if i := strings.IndexByte(ns, '.'); i != -1 {
+		return ns[i+1:]
+	}
+	return ns
+}
+
+var validatorType = reflect.TypeOf((*Validator)(nil)).Elem()
+
+func appendValidatorViolations(violations []Violation, v reflect.Value, path string, callSelf bool) []Violation {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return violations
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return violations
+	}
+	implements := implementsValidator(v)
+	if callSelf && implements {
+		if err := callValidate(v); err != nil {
+			violations = append(violations, Violation{Field: path, Message: err.Error()})
+		}
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous {
+			// The Validate method of an embedded struct is either promoted,
+			// and has then already been called, or shadowed by the parent.
+			violations = appendValidatorViolations(violations, v.Field(i), path, !implements)
+			continue
+		}
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+		violations = appendValidatorViolations(violations, v.Field(i), fieldPath, true)
+	}
+	return violations
+}
+
+func implementsValidator(v reflect.Value) bool {
+	return v.Type().Implements(validatorType) ||
+		(v.CanAddr() && v.Addr().Type().Implements(validatorType))
+}
+
+func callValidate(v reflect.Value) error {
+	if v.CanAddr() {
+		v = v.Addr()
+	}
+	return v.Interface().(Validator).Validate()
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testValidatedDB struct {
+	Host string `validate:"required"`
+	Port int    `validate:"min=1,max=65535"`
+}
+
+func (c testValidatedDB) Validate() error {
+	if c.Host == "localhost" && c.Port == 80 {
+		return errors.New("port 80 is reserved on localhost")
+	}
+	return nil
+}
+
+type testValidatedEmbedded struct {
+	Name string
+}
+
+func (c *testValidatedEmbedded) Validate() error {
+	if c.Name == "" {
+		return errors.New("name not set")
+	}
+	return nil
+}
+
+type testValidatedConfig struct {
+	testValidatedEmbedded `mapstructure:",squash" yaml:",inline"`
+	Workers               int `validate:"min=1"`
+	DB                    testValidatedDB
+	Optional              *testValidatedDB
+}
+
+func TestValidate(t *testing.T) {
+	valid := testValidatedConfig{
+		testValidatedEmbedded: testValidatedEmbedded{Name: "wharf"},
+		Workers:               1,
+		DB:                    testValidatedDB{Host: "db", Port: 5432},
+	}
+	testCases := []struct {
+		name   string
+		modify func(c *testValidatedConfig)
+		want   []Violation
+	}{
+		{
+			name:   "valid",
+			modify: func(c *testValidatedConfig) {},
+		},
+		{
+			name: "struct tags",
+			modify: func(c *testValidatedConfig) {
+				c.Workers = 0
+				c.DB.Host = ""
+			},
+			want: []Violation{
+				{Field: "Workers", Message: `failed the "min" validation rule with parameter "1"`},
+				{Field: "DB.Host", Message: `failed the "required" validation rule`},
+			},
+		},
+		{
+			name: "nested Validator",
+			modify: func(c *testValidatedConfig) {
+				c.DB = testValidatedDB{Host: "localhost", Port: 80}
+			},
+			want: []Violation{
+				{Field: "DB", Message: "port 80 is reserved on localhost"},
+			},
+		},
+		{
+			name: "embedded pointer receiver Validator",
+			modify: func(c *testValidatedConfig) {
+				c.Name = ""
+			},
+			want: []Violation{
+				{Field: "", Message: "name not set"},
+			},
+		},
+		{
+			name: "non-nil pointer field",
+			modify: func(c *testValidatedConfig) {
+				c.Optional = &testValidatedDB{Host: "localhost", Port: 80}
+			},
+			want: []Violation{
+				{Field: "Optional", Message: "port 80 is reserved on localhost"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := valid
+			tc.modify(&cfg)
+			err := Validate(&cfg)
+			if tc.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.ErrorIs(t, err, ErrInvalidConfig)
+			assert.Equal(t, tc.want, validationErr.Violations)
+		})
+	}
+}
+
+func TestValidate_errorMessage(t *testing.T) {
+	err := &ValidationError{[]Violation{
+		{Field: "", Message: "name not set"},
+		{Field: "DB.Host", Message: `failed the "required" validation rule`},
+	}}
+	assert.Equal(t, `invalid config: name not set; DB.Host: failed the "required" validation rule`, err.Error())
+}
+
+func TestBuilder_UnmarshalValidates(t *testing.T) {
+	b := NewBuilderFromYAML(testValidatedConfig{Workers: 1}, `
+name: wharf
+db:
+  host: localhost
+  port: 0
+`)
+	var cfg testValidatedConfig
+	err := b.Unmarshal(&cfg)
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []Violation{
+		{Field: "DB.Port", Message: `failed the "min" validation rule with parameter "1"`},
+	}, validationErr.Violations)
+}
+
+func TestNewTestBuilder_validatesAfterOverrides(t *testing.T) {
+	b := NewTestBuilder(testValidatedConfig{}, func(c *testValidatedConfig) {
+		c.Name = "wharf"
+		c.Workers = 1
+		c.DB = testValidatedDB{Host: "db", Port: 5432}
+	})
+	var cfg testValidatedConfig
+	assert.NoError(t, b.Unmarshal(&cfg))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -53,6 +54,34 @@ func (err ParseError) Unwrap() error {
 	return err.Err
 }
 
+// SliceParseError is an error type returned by BindSlice when an element of
+// the slice fails to parse. The embedded ParseError holds the element that
+// failed to parse as its EnvValue.
+type SliceParseError struct {
+	ParseError
+	// Index is the zero-based index of the element that failed to parse,
+	// counting all elements delimited by the separator, including empty
+	// elements that are skipped.
+	Index int
+}
+
+// Error returns the error string. Makes it compliant with the error interface.
+func (err SliceParseError) Error() string {
+	return fmt.Sprintf("env %q[%d]=%q: %s", err.EnvKey, err.Index, err.EnvValue, err.Err)
+}
+
+// As returns true if the target error is a ParseError, or if this error could
+// be unwrapped into the type of the target error.
+//
+// This method provides compatibility with the errors.As function.
+func (err SliceParseError) As(target any) bool {
+	if parseErr, ok := target.(*ParseError); ok {
+		*parseErr = err.ParseError
+		return true
+	}
+	return err.ParseError.As(target)
+}
+
 // BindConstraint is a generic type constraint of all the types that the Bind
 // function supports.
 type BindConstraint interface {
@@ -81,94 +110,157 @@ func Bind[T BindConstraint](i T, key string) error {
 	if !ok {
 		return nil
 	}
-	switch ptr := (any)(i).(type) {
+	if err := parseValue(i, envStr); err != nil {
+		if err == ErrUnsupportedType {
+			return fmt.Errorf("env %q: %w: %T", key, ErrUnsupportedType, i)
+		}
+		return ParseError{key, envStr, err}
+	}
+	return nil
+}
+
+// BindSliceConstraint is a generic type constraint of all the slice element
+// types that the BindSlice function supports.
+type BindSliceConstraint interface {
+	string | bool | int | int32 | int64 | uint | uint32 | uint64 |
+		float32 | float64 | time.Time | time.Duration
+}
+
+// DefaultSliceSeparator is the separator used by BindSlice when an empty
+// separator is given.
+const DefaultSliceSeparator = ","
+
+// BindSlice works the same as Bind, but parses the environment variable, if
+// set and not empty, as a list of values separated by the separator, or by
+// DefaultSliceSeparator if the separator is empty. Whitespace around each
+// element is trimmed, and empty elements are skipped. Example:
+//
+//	os.Setenv("PORTS", "8080, 8081")
+//	var ports []int
+//	env.BindSlice(&ports, "PORTS", ",") // ports = []int{8080, 8081}
+//
+// If the environment variable is not set, is empty, or the function returns an
+// error, the value of the target slice is left unchanged.
+//
+// Returns an env.SliceParseError on parsing errors, with the index of the
+// element that failed to parse, which also unwraps to an env.ParseError when
+// using errors.As.
+func BindSlice[T BindSliceConstraint](ptr *[]T, key, sep string) error {
+	var envStr, ok = LookupNoEmpty(key)
+	if !ok {
+		return nil
+	}
+	if sep == "" {
+		sep = DefaultSliceSeparator
+	}
+	values := []T{}
+	for i, elem := range strings.Split(envStr, sep) {
+		elem = strings.TrimSpace(elem)
+		if elem == "" {
+			continue
+		}
+		var value T
+		if err := parseValue(&value, elem); err != nil {
+			return SliceParseError{ParseError{key, elem, err}, i}
+		}
+		values = append(values, value)
+	}
+	*ptr = values
+	return nil
+}
+
+// BindMultiple updates the Go variables via the pointers with the values of the
+// environment variables, if set and not empty, for each respective pair in
+// the map.
+//
+// If the environment variable is not set, is empty, or the function returns an
+// error, the value of the respective target interface is left unchanged.
+//
+// An error is returned if any of the bindings failed to bind.
+func BindMultiple[T BindConstraint](bindings map[T]string) error {
+	for ptr, key := range bindings {
+		if err := Bind(ptr, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseValue parses the string into the value pointed to by ptr. Returns
+// ErrUnsupportedType if the type of the pointer is not supported, or the
+// parsing error as-is otherwise.
+func parseValue(ptr any, str string) error {
+	switch ptr := ptr.(type) {
 	case *string:
-		*ptr = envStr
+		*ptr = str
 	case *bool:
-		value, err := strconv.ParseBool(envStr)
+		value, err := strconv.ParseBool(str)
 		if err != nil {
-			return ParseError{key, envStr, err}
+			return err
 		}
 		*ptr = value
 	case *int:
-		value, err := strconv.ParseInt(envStr, 10, strconv.IntSize)
+		value, err := strconv.ParseInt(str, 10, strconv.IntSize)
 		if err != nil {
-			return ParseError{key, envStr, err}
+			return err
 		}
 		*ptr = int(value)
 	case *int32:
-		value, err := strconv.ParseInt(envStr, 10, 32)
+		value, err := strconv.ParseInt(str, 10, 32)
 		if err != nil {
-			return ParseError{key, envStr, err}
+			return err
 		}
 		*ptr = int32(value)
 	case *int64:
-		value, err := strconv.ParseInt(envStr, 10, 64)
+		value, err := strconv.ParseInt(str, 10, 64)
 		if err != nil {
-			return ParseError{key, envStr, err}
+			return err
 		}
 		*ptr = value
 	case *uint:
-		value, err := strconv.ParseUint(envStr, 10, strconv.IntSize)
+		value, err := strconv.ParseUint(str, 10, strconv.IntSize)
 		if err != nil {
-			return ParseError{key, envStr, err}
+			return err
 		}
 		*ptr = uint(value)
 	case *uint32:
-		value, err := strconv.ParseUint(envStr, 10, 32)
+		value, err := strconv.ParseUint(str, 10, 32)
 		if err != nil {
-			return ParseError{key, envStr, err}
+			return err
 		}
 		*ptr = uint32(value)
 	case *uint64:
-		value, err := strconv.ParseUint(envStr, 10, 64)
+		value, err := strconv.ParseUint(str, 10, 64)
 		if err != nil {
-			return ParseError{key, envStr, err}
+			return err
 		}
 		*ptr = value
 	case *float32:
-		value, err := strconv.ParseFloat(envStr, 32)
+		value, err := strconv.ParseFloat(str, 32)
 		if err != nil {
-			return ParseError{key, envStr, err}
+			return err
 		}
 		*ptr = float32(value)
 	case *float64:
-		value, err := strconv.ParseFloat(envStr, 64)
+		value, err := strconv.ParseFloat(str, 64)
 		if err != nil {
-			return ParseError{key, envStr, err}
+			return err
 		}
 		*ptr = value
 	case *time.Time:
-		value, err := time.Parse(time.RFC3339, envStr)
+		value, err := time.Parse(time.RFC3339, str)
 		if err != nil {
-			return ParseError{key, envStr, err}
+			return err
 		}
 		*ptr = value
 	case *time.Duration:
-		value, err := time.ParseDuration(envStr)
+		value, err := time.ParseDuration(str)
 		if err != nil {
-			return ParseError{key, envStr, err}
+			return err
 		}
 		*ptr = value
 	default:
-		return fmt.Errorf("env %q: %w: %T", key, ErrUnsupportedType, i)
-	}
-	return nil
-}
-
-// BindMultiple updates the Go variables via the pointers with the values of the
-// environment variables, if set and not empty, for each respective pair in
-// the map.
-//
-// If the environment variable is not set, is empty, or the function returns an
-// error, the value of the respective target interface is left unchanged.
-//
-// An error is returned if any of the bindings failed to bind.
-func BindMultiple[T BindConstraint](bindings map[T]string) error {
-	for ptr, key := range bindings {
-		if err := Bind(ptr, key); err != nil {
-			return err
-		}
+		return ErrUnsupportedType
 	}
 	return nil
 }
@@ -51,3 +51,50 @@ func TestBind(t *testing.T) {
 func TestBindMultiple_noErrorOnNilMap(t *testing.T) {
 	assert.NoError(t, BindMultiple[*int](nil))
 }
+
+func TestBindSlice(t *testing.T) {
+	t.Run("strings", func(t *testing.T) {
+		testutil.SetEnv(t, "MY_STRS", "a, b,,c ")
+		var got []string
+		require.NoError(t, BindSlice(&got, "MY_STRS", ""))
+		assert.Equal(t, []string{"a", "b", "c"}, got)
+	})
+	t.Run("ints with custom separator", func(t *testing.T) {
+		testutil.SetEnv(t, "MY_INTS", "1;-2;3")
+		var got []int
+		require.NoError(t, BindSlice(&got, "MY_INTS", ";"))
+		assert.Equal(t, []int{1, -2, 3}, got)
+	})
+	t.Run("durations", func(t *testing.T) {
+		testutil.SetEnv(t, "MY_DURATIONS", "1s,2m")
+		var got []time.Duration
+		require.NoError(t, BindSlice(&got, "MY_DURATIONS", ","))
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Minute}, got)
+	})
+	t.Run("unset leaves value unchanged", func(t *testing.T) {
+		got := []uint{1}
+		require.NoError(t, BindSlice(&got, "MY_UNSET_UINTS", ","))
+		assert.Equal(t, []uint{1}, got)
+	})
+}
+
+func TestBindSlice_parseErrorIndex(t *testing.T) {
+	testutil.SetEnv(t, "MY_UINTS", "1,,foo")
+	got := []uint{1}
+	err := BindSlice(&got, "MY_UINTS", ",")
+	var sliceErr SliceParseError
+	require.ErrorAs(t, err, &sliceErr)
+	assert.ErrorIs(t, err, ErrParse)
+	assert.Equal(t, 2, sliceErr.Index)
+	assert.Equal(t, "foo", sliceErr.EnvValue)
+	var parseErr ParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "MY_UINTS", parseErr.EnvKey)
+	assert.Equal(t, `env "MY_UINTS"[2]="foo": strconv.ParseUint: parsing "foo": invalid syntax`, err.Error())
+	assert.Equal(t, []uint{1}, got)
+}
+
+func TestParseError_keyedLiteral(t *testing.T) {
+	err := ParseError{EnvKey: "KEY", EnvValue: "foo", Err: ErrParse}
+	assert.Equal(t, `env "KEY"="foo": failed to parse`, err.Error())
+}
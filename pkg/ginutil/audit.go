@@ -0,0 +1,101 @@
+package ginutil
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+)
+
+const contextKeyAuditActor = "wharf-core/ginutil/audit-actor"
+
+// AuditConfig holds configuration for the audit logging middleware.
+type AuditConfig struct {
+	// Logger is the logger implementation used when logging the audit events.
+	// Defaults to a new scoped logger with the scope "AUDIT".
+	Logger logger.Logger
+	// Methods is the list of HTTP methods that are audited. Defaults to the
+	// mutating methods POST, PUT, PATCH, and DELETE.
+	Methods []string
+	// ActorFunc is used to obtain the actor, such as the username, of the user
+	// that issued the request. Defaults to the actor set via SetAuditActor.
+	ActorFunc func(c *gin.Context) string
+}
+
+// DefaultAuditHandler is a Gin middleware that logs audit events for all
+// mutating requests using the default configuration.
+var DefaultAuditHandler = AuditWithConfig(AuditConfig{})
+
+// AuditWithConfig creates a Gin middleware handler function that, after the
+// request has been processed, logs an audit event for each request that uses
+// one of the audited HTTP methods.
+//
+// Each audit event is logged with the information level, and contains the
+// actor, the HTTP method, the route, all path parameters (such as resource
+// IDs) as fields prefixed with "params.", the response status, and the outcome
+// of either "success" or "failure" based on the response status.
+func AuditWithConfig(config AuditConfig) gin.HandlerFunc {
+	if config.Logger == nil {
+		config.Logger = logger.NewScoped("AUDIT")
+	}
+	if len(config.Methods) == 0 {
+		config.Methods = []string{
+			http.MethodPost,
+			http.MethodPut,
+			http.MethodPatch,
+			http.MethodDelete,
+		}
+	}
+	if config.ActorFunc == nil {
+		config.ActorFunc = GetAuditActor
+	}
+	return func(c *gin.Context) {
+		c.Next()
+		if !isAuditedMethod(config.Methods, c.Request.Method) {
+			return
+		}
+
+		status := c.Writer.Status()
+		outcome := "success"
+		if status >= http.StatusBadRequest {
+			outcome = "failure"
+		}
+		ev := config.Logger.Info().
+			WithString("actor", config.ActorFunc(c)).
+			WithString(httpKey("method"), c.Request.Method).
+			WithString(httpKey("route"), c.FullPath()).
+			WithString(httpKey("path"), c.Request.URL.Path).
+			WithInt(httpKey("status"), status).
+			WithString("outcome", outcome)
+		for _, p := range c.Params {
+			ev = ev.WithString("params."+p.Key, p.Value)
+		}
+		if err := c.Errors.Last(); err != nil {
+			ev = ev.WithError(err.Err)
+		}
+		ev.WithProvidedFields(c.Request.Context()).
+			Message("Audit event.")
+	}
+}
+
+// SetAuditActor sets the actor, such as the username, used in the audit events
+// logged by AuditWithConfig. Meant to be called by authentication middleware.
+func SetAuditActor(c *gin.Context, actor string) {
+	c.Set(contextKeyAuditActor, actor)
+}
+
+// GetAuditActor returns the actor set via SetAuditActor, or an empty string if
+// none has been set.
+func GetAuditActor(c *gin.Context) string {
+	return c.GetString(contextKeyAuditActor)
+}
+
+func isAuditedMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package ginutil
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditWithConfig(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	mock := logger.NewMock()
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		SetAuditActor(c, "alice")
+	})
+	r.Use(AuditWithConfig(AuditConfig{Logger: mock}))
+	r.GET("/projects/:projectId", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.PUT("/projects/:projectId", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.DELETE("/projects/:projectId", func(c *gin.Context) {
+		c.Error(errors.New("not allowed"))
+		c.Status(http.StatusForbidden)
+	})
+
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/projects/12", nil))
+	}
+
+	require.Len(t, mock.Logs, 2, "GET should not be audited")
+
+	put := mock.Logs[0].Fields
+	assert.Equal(t, "alice", put["actor"])
+	assert.Equal(t, http.MethodPut, put["method"])
+	assert.Equal(t, "/projects/:projectId", put["route"])
+	assert.Equal(t, "12", put["params.projectId"])
+	assert.Equal(t, http.StatusOK, put["status"])
+	assert.Equal(t, "success", put["outcome"])
+
+	del := mock.Logs[1].Fields
+	assert.Equal(t, http.StatusForbidden, del["status"])
+	assert.Equal(t, "failure", del["outcome"])
+	assert.EqualError(t, del["error"].(error), "not allowed")
+}
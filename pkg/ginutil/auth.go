@@ -0,0 +1,164 @@
+package ginutil
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+)
+
+// Authentication schemes supported by AuthWithConfig, as used in the HTTP
+// Authorization header.
+const (
+	AuthSchemeBasic  = "Basic"
+	AuthSchemeBearer = "Bearer"
+)
+
+// Credentials are the credentials parsed from the HTTP Authorization header.
+type Credentials struct {
+	// Scheme is the authentication scheme, such as AuthSchemeBasic or
+	// AuthSchemeBearer.
+	Scheme string
+	// Username is the username when using basic authentication.
+	Username string
+	// Password is the password when using basic authentication.
+	Password string
+	// Token is the token when using bearer authentication.
+	Token string
+}
+
+// AuthConfig holds configuration for the authentication middleware. At least
+// one of BearerTokens, BasicAccounts, or Verify must be set, or else all
+// requests are denied.
+type AuthConfig struct {
+	// BearerTokens is a list of static tokens accepted via bearer
+	// authentication.
+	BearerTokens []string
+	// BasicAccounts is a map of usernames to passwords accepted via basic
+	// authentication.
+	BasicAccounts map[string]string
+	// Verify is a callback used to verify credentials that were not accepted
+	// by BearerTokens or BasicAccounts, such as to look up tokens from a
+	// database. The user is authenticated if it returns true.
+	Verify func(c *gin.Context, cred Credentials) bool
+	// Realm is the realm sent in the WWW-Authenticate response header when
+	// basic authentication is enabled via BasicAccounts. Defaults to "wharf".
+	Realm string
+	// Logger is the logger implementation used when logging failed attempts,
+	// together with the request fields added by Logger, such as the client IP
+	// address. Defaults to the logger set via SetLogger, or else a logger with
+	// the scope "GIN".
+	Logger logger.Logger
+}
+
+// BearerAuth creates a Gin middleware handler function that uses
+// AuthWithConfig to only accept the given static bearer tokens.
+//
+// Example usage:
+//
+// 	r.Use(ginutil.BearerAuth(os.Getenv("API_TOKEN")))
+func BearerAuth(tokens ...string) gin.HandlerFunc {
+	return AuthWithConfig(AuthConfig{BearerTokens: tokens})
+}
+
+// BasicAuth creates a Gin middleware handler function that uses
+// AuthWithConfig to only accept the given usernames and passwords via basic
+// authentication.
+func BasicAuth(accounts map[string]string) gin.HandlerFunc {
+	return AuthWithConfig(AuthConfig{BasicAccounts: accounts})
+}
+
+// AuthWithConfig creates a Gin middleware handler function that
+// authenticates requests using the HTTP Authorization header.
+//
+// Requests that fail authentication are aborted with a HTTP "Unauthorized"
+// problem response using WriteUnauthorized, and the failed attempt is logged
+// using the warning logging level. The credentials themselves are never
+// logged.
+//
+// On successful basic authentication, the username is set as the audit actor
+// via SetAuditActor.
+func AuthWithConfig(config AuthConfig) gin.HandlerFunc {
+	if config.Realm == "" {
+		config.Realm = "wharf"
+	}
+	return func(c *gin.Context) {
+		cred, ok := parseAuthorization(c.GetHeader("Authorization"))
+		if !ok {
+			config.deny(c, "Missing or malformed Authorization header.")
+			return
+		}
+		if !config.verify(c, cred) {
+			config.deny(c, fmt.Sprintf("Invalid %s credentials.", strings.ToLower(cred.Scheme)))
+			return
+		}
+		if cred.Username != "" {
+			SetAuditActor(c, cred.Username)
+		}
+		c.Next()
+	}
+}
+
+func (config AuthConfig) verify(c *gin.Context, cred Credentials) bool {
+	switch cred.Scheme {
+	case AuthSchemeBearer:
+		for _, token := range config.BearerTokens {
+			if secureEqual(token, cred.Token) {
+				return true
+			}
+		}
+	case AuthSchemeBasic:
+		if password, ok := config.BasicAccounts[cred.Username]; ok &&
+			secureEqual(password, cred.Password) {
+			return true
+		}
+	}
+	return config.Verify != nil && config.Verify(c, cred)
+}
+
+func (config AuthConfig) deny(c *gin.Context, detail string) {
+	log := Logger(c)
+	if config.Logger != nil {
+		log = newRequestLogger(c, config.Logger)
+	}
+	log.Warn().WithString("reason", detail).
+		Message("Failed authentication attempt.")
+	if len(config.BasicAccounts) > 0 {
+		c.Header("WWW-Authenticate", fmt.Sprintf("%s realm=%q", AuthSchemeBasic, config.Realm))
+	} else {
+		c.Header("WWW-Authenticate", AuthSchemeBearer)
+	}
+	WriteUnauthorized(c, detail)
+	c.Abort()
+}
+
+func parseAuthorization(header string) (Credentials, bool) {
+	scheme, value, ok := strings.Cut(header, " ")
+	if !ok {
+		return Credentials{}, false
+	}
+	value = strings.TrimSpace(value)
+	switch {
+	case strings.EqualFold(scheme, AuthSchemeBearer) && value != "":
+		return Credentials{Scheme: AuthSchemeBearer, Token: value}, true
+	case strings.EqualFold(scheme, AuthSchemeBasic):
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return Credentials{}, false
+		}
+		username, password, ok := strings.Cut(string(decoded), ":")
+		if !ok {
+			return Credentials{}, false
+		}
+		return Credentials{Scheme: AuthSchemeBasic, Username: username, Password: password}, true
+	default:
+		return Credentials{}, false
+	}
+}
+
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
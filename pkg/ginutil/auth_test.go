@@ -0,0 +1,102 @@
+package ginutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthWithConfig(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	tests := []struct {
+		name       string
+		config     AuthConfig
+		header     string
+		wantStatus int
+		wantActor  string
+	}{
+		{
+			name:       "missing header",
+			config:     AuthConfig{BearerTokens: []string{"secret"}},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "valid bearer token",
+			config:     AuthConfig{BearerTokens: []string{"other", "secret"}},
+			header:     "Bearer secret",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid bearer token",
+			config:     AuthConfig{BearerTokens: []string{"secret"}},
+			header:     "Bearer wrong",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "valid basic auth",
+			config:     AuthConfig{BasicAccounts: map[string]string{"admin": "pass"}},
+			header:     "Basic YWRtaW46cGFzcw==",
+			wantStatus: http.StatusOK,
+			wantActor:  "admin",
+		},
+		{
+			name:       "invalid basic auth",
+			config:     AuthConfig{BasicAccounts: map[string]string{"admin": "other"}},
+			header:     "Basic YWRtaW46cGFzcw==",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "malformed basic auth",
+			config:     AuthConfig{BasicAccounts: map[string]string{"admin": "pass"}},
+			header:     "Basic !!!",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "verify callback",
+			config: AuthConfig{Verify: func(c *gin.Context, cred Credentials) bool {
+				return cred.Token == "dynamic"
+			}},
+			header:     "bearer dynamic",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "no methods configured",
+			header:     "Bearer secret",
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := logger.NewMock()
+			tc.config.Logger = mock
+			var gotActor string
+			r := gin.New()
+			r.GET("/", AuthWithConfig(tc.config), func(c *gin.Context) {
+				gotActor = GetAuditActor(c)
+				c.Status(http.StatusOK)
+			})
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tc.wantStatus, w.Code)
+			assert.Equal(t, tc.wantActor, gotActor)
+			if tc.wantStatus == http.StatusOK {
+				assert.Empty(t, mock.Logs)
+				return
+			}
+			assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+			require.Len(t, mock.Logs, 1)
+			assert.Equal(t, logger.LevelWarn, mock.Logs[0].Level)
+			assert.Equal(t, "10.0.0.1", mock.Logs[0].Fields[httpKey("clientIp")])
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package ginutil
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// BindJSON binds the JSON request body into a new value of type T using
+// gin.Context.ShouldBindJSON, which also validates the value using its
+// "binding" struct tags.
+//
+// If it fails, it will write out a problem response using
+// WriteValidationError with the status code 400 (Bad Request), and return
+// false.
+//
+// Example usage:
+//
+// 	func createProjectHandler(c *gin.Context) {
+// 		project, ok := ginutil.BindJSON[Project](c)
+// 		if !ok {
+// 			return
+// 		}
+// 		// ...
+// 	}
+func BindJSON[T any](c *gin.Context) (T, bool) {
+	var value T
+	if err := c.ShouldBindJSON(&value); err != nil {
+		WriteValidationError(c, err)
+		return value, false
+	}
+	return value, true
+}
+
+// BindQuery binds the query parameters into a new value of type T using
+// gin.Context.ShouldBindQuery, which also validates the value using its
+// "binding" struct tags.
+//
+// If it fails, it will write out a problem response using
+// WriteValidationError with the status code 400 (Bad Request), and return
+// false.
+func BindQuery[T any](c *gin.Context) (T, bool) {
+	var value T
+	if err := c.ShouldBindQuery(&value); err != nil {
+		WriteValidationError(c, err)
+		return value, false
+	}
+	return value, true
+}
@@ -0,0 +1,88 @@
+package ginutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindJSON(t *testing.T) {
+	type project struct {
+		Name string `json:"name" binding:"required"`
+	}
+	tests := []struct {
+		name       string
+		body       string
+		wantOK     bool
+		wantStatus int
+		want       project
+	}{
+		{
+			name:       "valid",
+			body:       `{"name": "wharf"}`,
+			wantOK:     true,
+			wantStatus: http.StatusOK,
+			want:       project{Name: "wharf"},
+		},
+		{
+			name:       "invalid",
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	gin.SetMode(gin.ReleaseMode)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			got, ok := BindJSON[project](c)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestBindQuery(t *testing.T) {
+	type pagination struct {
+		Limit int `form:"limit" binding:"required,max=100"`
+	}
+	tests := []struct {
+		name       string
+		query      string
+		wantOK     bool
+		wantStatus int
+		want       pagination
+	}{
+		{
+			name:       "valid",
+			query:      "?limit=10",
+			wantOK:     true,
+			wantStatus: http.StatusOK,
+			want:       pagination{Limit: 10},
+		},
+		{
+			name:       "invalid",
+			query:      "?limit=1000",
+			wantStatus: http.StatusBadRequest,
+			want:       pagination{Limit: 1000},
+		},
+	}
+	gin.SetMode(gin.ReleaseMode)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/"+tc.query, nil)
+			got, ok := BindQuery[pagination](c)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package ginutil
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig holds configuration for the Cross-Origin Resource Sharing (CORS)
+// middleware. Meant to be embedded in the application's configuration struct
+// and read using the config package. Example:
+//
+// 	type Config struct {
+// 		CORS ginutil.CORSConfig // set via "WHARF_CORS_ALLOWORIGINS", etc.
+// 	}
+type CORSConfig struct {
+	// AllowOrigins is the list of origins that are allowed to make
+	// cross-origin requests. No CORS headers are written when empty.
+	//
+	// When set to "*": all origins are allowed.
+	//
+	// When set to "https://*.example.com": all subdomains of example.com are
+	// allowed when using HTTPS.
+	AllowOrigins []string
+	// AllowMethods is the list of HTTP methods allowed in cross-origin
+	// requests. Defaults to GET, HEAD, POST, PUT, PATCH, and DELETE.
+	AllowMethods []string
+	// AllowHeaders is the list of request headers allowed in cross-origin
+	// requests. Defaults to Accept, Authorization, Content-Type, and
+	// X-Request-ID.
+	AllowHeaders []string
+	// ExposeHeaders is the list of response headers that the browser is
+	// allowed to expose to the client-side scripts.
+	ExposeHeaders []string
+	// AllowCredentials allows cookies and the Authorization header to be
+	// included in cross-origin requests when set to true.
+	//
+	// Cannot be combined with allowing any origin via "*" in AllowOrigins, as
+	// that would give any website credentialed access, which is why the CORS
+	// specification forbids it. CORSWithConfig panics if both are set.
+	AllowCredentials bool
+	// MaxAge is how long the browser may cache the result of a preflight
+	// request. Not sent when zero, leaving it up to the browser.
+	MaxAge time.Duration
+}
+
+// CORSWithConfig creates a Gin middleware handler function that writes the
+// CORS response headers for requests from the allowed origins, and responds
+// to preflight requests with 204 "No Content" without calling the subsequent
+// handlers.
+//
+// Requests from origins that are not allowed are passed on without any CORS
+// headers, leaving it up to the browser to reject the response.
+//
+// Panics if CORSConfig.AllowCredentials is set while allowing any origin via
+// "*" in CORSConfig.AllowOrigins.
+func CORSWithConfig(config CORSConfig) gin.HandlerFunc {
+	if config.AllowCredentials && config.allowsAnyOrigin() {
+		panic(`ginutil: CORS: AllowCredentials cannot be used when allowing any origin via "*"`)
+	}
+	if len(config.AllowMethods) == 0 {
+		config.AllowMethods = []string{
+			http.MethodGet,
+			http.MethodHead,
+			http.MethodPost,
+			http.MethodPut,
+			http.MethodPatch,
+			http.MethodDelete,
+		}
+	}
+	if len(config.AllowHeaders) == 0 {
+		config.AllowHeaders = []string{"Accept", "Authorization", "Content-Type", HeaderRequestID}
+	}
+	allowMethods := strings.Join(config.AllowMethods, ", ")
+	allowHeaders := strings.Join(config.AllowHeaders, ", ")
+	exposeHeaders := strings.Join(config.ExposeHeaders, ", ")
+	var maxAge string
+	if config.MaxAge > 0 {
+		maxAge = strconv.Itoa(int(config.MaxAge / time.Second))
+	}
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Origin")
+		allowOrigin, ok := config.allowOrigin(origin)
+		if !ok {
+			c.Next()
+			return
+		}
+		c.Header("Access-Control-Allow-Origin", allowOrigin)
+		if config.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+		if c.Request.Method != http.MethodOptions || c.GetHeader("Access-Control-Request-Method") == "" {
+			if exposeHeaders != "" {
+				c.Header("Access-Control-Expose-Headers", exposeHeaders)
+			}
+			c.Next()
+			return
+		}
+		c.Header("Access-Control-Allow-Methods", allowMethods)
+		c.Header("Access-Control-Allow-Headers", allowHeaders)
+		if maxAge != "" {
+			c.Header("Access-Control-Max-Age", maxAge)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+func (config CORSConfig) allowsAnyOrigin() bool {
+	for _, allowed := range config.AllowOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// allowOrigin returns the value of the Access-Control-Allow-Origin header for
+// the requesting origin, and false if the origin is not allowed.
+func (config CORSConfig) allowOrigin(origin string) (string, bool) {
+	for _, allowed := range config.AllowOrigins {
+		switch {
+		case allowed == "*":
+			return "*", true
+		case strings.EqualFold(allowed, origin):
+			return origin, true
+		case matchOriginPattern(allowed, origin):
+			return origin, true
+		}
+	}
+	return "", false
+}
+
+func matchOriginPattern(pattern, origin string) bool {
+	prefix, suffix, ok := strings.Cut(pattern, "*")
+	if !ok || len(origin) < len(prefix)+len(suffix) {
+		return false
+	}
+	return strings.EqualFold(origin[:len(prefix)], prefix) &&
+		strings.EqualFold(origin[len(origin)-len(suffix):], suffix)
+}
@@ -0,0 +1,133 @@
+package ginutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSWithConfig(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	tests := []struct {
+		name        string
+		config      CORSConfig
+		method      string
+		origin      string
+		preflight   bool
+		wantStatus  int
+		wantHeaders map[string]string
+	}{
+		{
+			name:        "no origin",
+			config:      CORSConfig{AllowOrigins: []string{"*"}},
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:        "any origin",
+			config:      CORSConfig{AllowOrigins: []string{"*"}},
+			origin:      "https://wharf.example.com",
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": "*"},
+		},
+		{
+			name: "credentials",
+			config: CORSConfig{
+				AllowOrigins:     []string{"https://wharf.example.com"},
+				AllowCredentials: true,
+			},
+			origin:     "https://wharf.example.com",
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://wharf.example.com",
+				"Access-Control-Allow-Credentials": "true",
+			},
+		},
+		{
+			name:        "subdomain pattern",
+			config:      CORSConfig{AllowOrigins: []string{"https://*.example.com"}},
+			origin:      "https://wharf.example.com",
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": "https://wharf.example.com"},
+		},
+		{
+			name:        "disallowed origin",
+			config:      CORSConfig{AllowOrigins: []string{"https://wharf.example.com"}},
+			origin:      "https://evil.example.org",
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": "", "Vary": "Origin"},
+		},
+		{
+			name:        "disabled",
+			origin:      "https://wharf.example.com",
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name: "preflight",
+			config: CORSConfig{
+				AllowOrigins: []string{"https://wharf.example.com"},
+				AllowHeaders: []string{"Content-Type"},
+				MaxAge:       10 * time.Minute,
+			},
+			method:     http.MethodOptions,
+			origin:     "https://wharf.example.com",
+			preflight:  true,
+			wantStatus: http.StatusNoContent,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "https://wharf.example.com",
+				"Access-Control-Allow-Methods": "GET, HEAD, POST, PUT, PATCH, DELETE",
+				"Access-Control-Allow-Headers": "Content-Type",
+				"Access-Control-Max-Age":       "600",
+			},
+		},
+		{
+			name: "expose headers",
+			config: CORSConfig{
+				AllowOrigins:  []string{"https://wharf.example.com"},
+				ExposeHeaders: []string{"X-Total-Count"},
+			},
+			origin:     "https://wharf.example.com",
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Expose-Headers": "X-Total-Count",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(CORSWithConfig(tc.config))
+			r.Any("/", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+			method := tc.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, "/", nil)
+			if tc.origin != "" {
+				req.Header.Set("Origin", tc.origin)
+			}
+			if tc.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tc.wantStatus, w.Code)
+			for key, want := range tc.wantHeaders {
+				assert.Equal(t, want, w.Header().Get(key), key)
+			}
+		})
+	}
+}
+
+func TestCORSWithConfig_panicsOnAnyOriginWithCredentials(t *testing.T) {
+	assert.Panics(t, func() {
+		CORSWithConfig(CORSConfig{AllowOrigins: []string{"*"}, AllowCredentials: true})
+	})
+}
@@ -0,0 +1,45 @@
+package ginutil
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
+)
+
+const contextKeyDeprecation = "wharf-core/ginutil/deprecation"
+
+// Deprecated creates a Gin middleware that marks all endpoints it's applied to
+// as deprecated, using WriteDeprecation.
+//
+// Meant to be used on single routes or route groups that are slated for
+// removal:
+//
+// 	r.GET("/api/v4/projects", ginutil.Deprecated(problem.Deprecation{
+// 		Sunset: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC),
+// 		Link:   "https://wharf.iver.com/#/development/migrations/v5",
+// 	}), getProjectsHandler)
+func Deprecated(d problem.Deprecation) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		WriteDeprecation(c, d)
+		c.Next()
+	}
+}
+
+// WriteDeprecation sets the Deprecation, Sunset, and Link HTTP headers on
+// the response, as documented in problem.Deprecation.WriteHeaders.
+//
+// The deprecation is also stored in the gin.Context, so that any
+// problem response written via WriteProblem gets the deprecation notice added
+// to its problem.Response.Deprecation field.
+func WriteDeprecation(c *gin.Context, d problem.Deprecation) {
+	d.WriteHeaders(c.Writer.Header())
+	c.Set(contextKeyDeprecation, d)
+}
+
+func getDeprecation(c *gin.Context) (problem.Deprecation, bool) {
+	value, ok := c.Get(contextKeyDeprecation)
+	if !ok {
+		return problem.Deprecation{}, false
+	}
+	d, ok := value.(problem.Deprecation)
+	return d, ok
+}
@@ -0,0 +1,55 @@
+package ginutil_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/ginutil"
+	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
+)
+
+func ExampleDeprecated() {
+	r := gin.New()
+	r.GET("/old", ginutil.Deprecated(problem.Deprecation{
+		Date:   time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+		Sunset: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		Link:   "https://wharf.iver.com/#/development/migrations/v5",
+	}), func(c *gin.Context) {
+		ginutil.WriteUnauthorized(c, "Sample detail.")
+	})
+
+	// Faking a request here
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/old", nil)
+	r.ServeHTTP(w, req)
+
+	resp := w.Result()
+	fmt.Println("HTTP/1.1", resp.Status)
+	fmt.Println("Deprecation:", resp.Header.Get("Deprecation"))
+	fmt.Println("Sunset:", resp.Header.Get("Sunset"))
+	fmt.Println("Link:", resp.Header.Get("Link"))
+	fmt.Println()
+	fmt.Println(indentedBodyFromResponse(resp))
+
+	// Output:
+	// HTTP/1.1 401 Unauthorized
+	// Deprecation: @1654041600
+	// Sunset: Sun, 01 Jan 2023 00:00:00 GMT
+	// Link: <https://wharf.iver.com/#/development/migrations/v5>; rel="deprecation"
+	//
+	// {
+	//   "type": "https://wharf.iver.com/#/prob/api/unauthorized",
+	//   "title": "Unauthorized.",
+	//   "status": 401,
+	//   "detail": "Sample detail.",
+	//   "instance": "/old",
+	//   "errors": null,
+	//   "deprecation": {
+	//     "date": "2022-06-01T00:00:00Z",
+	//     "sunset": "2023-01-01T00:00:00Z",
+	//     "link": "https://wharf.iver.com/#/development/migrations/v5"
+	//   }
+	// }
+}
@@ -0,0 +1,104 @@
+package ginutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewETag computes a strong entity tag from the data, such as the
+// serialized representation of a resource. The result is quoted, as required
+// by the ETag header.
+func NewETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// NewETagJSON computes a strong entity tag from the JSON serialization of
+// the value, using NewETag.
+func NewETagJSON(value any) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("compute ETag: %w", err)
+	}
+	return NewETag(data), nil
+}
+
+// CheckIfNoneMatch sets the ETag response header, and compares the entity tag
+// with the If-None-Match request header using weak comparison, as defined in
+// RFC 7232.
+//
+// If they match, it will write out a 304 "Not Modified" response without a
+// body and return false, meaning the handler should return without writing
+// the resource. Meant to be used in GET and HEAD handlers.
+//
+// Example usage:
+//
+// 	etag, err := ginutil.NewETagJSON(project)
+// 	if err != nil {
+// 		// ...
+// 	}
+// 	if !ginutil.CheckIfNoneMatch(c, etag) {
+// 		return
+// 	}
+// 	c.JSON(http.StatusOK, project)
+func CheckIfNoneMatch(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+	header := c.GetHeader("If-None-Match")
+	if header == "" || !matchETags(header, etag, true) {
+		return true
+	}
+	c.Status(http.StatusNotModified)
+	c.Writer.WriteHeaderNow()
+	return false
+}
+
+// CheckIfMatch compares the entity tag of the current state of the resource
+// with the If-Match request header using strong comparison, as defined in
+// RFC 7232. Requests without the If-Match header are always allowed.
+//
+// If they do not match, it will write out a problem response using
+// WritePreconditionFailed with the status code 412 (Precondition Failed) and
+// return false. Meant to be used in PUT, PATCH, and DELETE handlers for
+// optimistic concurrency control.
+func CheckIfMatch(c *gin.Context, etag string) bool {
+	header := c.GetHeader("If-Match")
+	if header == "" || matchETags(header, etag, false) {
+		return true
+	}
+	WritePreconditionFailed(c, fmt.Sprintf(
+		"The resource has been modified, as its entity tag %s does not match the If-Match header %s.",
+		etag, header))
+	return false
+}
+
+// matchETags reports whether any of the comma-separated entity tags in the
+// header matches the entity tag. Weak comparison ignores the "W/" prefix,
+// while strong comparison never matches weak entity tags.
+func matchETags(header, etag string, weak bool) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	if weak {
+		etag = strings.TrimPrefix(etag, "W/")
+	} else if strings.HasPrefix(etag, "W/") {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		} else if strings.HasPrefix(candidate, "W/") {
+			continue
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
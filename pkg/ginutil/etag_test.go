@@ -0,0 +1,74 @@
+package ginutil
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewETagJSON(t *testing.T) {
+	a, err := NewETagJSON(map[string]int{"id": 1})
+	require.NoError(t, err)
+	b, err := NewETagJSON(map[string]int{"id": 2})
+	require.NoError(t, err)
+	assert.NotEqual(t, a, b)
+	assert.Equal(t, a, NewETag([]byte(`{"id":1}`)))
+	assert.Len(t, a, 34)
+}
+
+func TestCheckIfNoneMatch(t *testing.T) {
+	etag := `"abc"`
+	tests := []struct {
+		name       string
+		header     string
+		want       bool
+		wantStatus int
+	}{
+		{name: "no header", want: true, wantStatus: http.StatusOK},
+		{name: "match", header: `"abc"`, want: false, wantStatus: http.StatusNotModified},
+		{name: "weak match", header: `"xyz", W/"abc"`, want: false, wantStatus: http.StatusNotModified},
+		{name: "wildcard", header: `*`, want: false, wantStatus: http.StatusNotModified},
+		{name: "no match", header: `"xyz"`, want: true, wantStatus: http.StatusOK},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := newParamsTestContext("/")
+			if tc.header != "" {
+				c.Request.Header.Set("If-None-Match", tc.header)
+			}
+			assert.Equal(t, tc.want, CheckIfNoneMatch(c, etag))
+			assert.Equal(t, tc.wantStatus, w.Code)
+			assert.Equal(t, etag, w.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestCheckIfMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		etag   string
+		header string
+		want   bool
+	}{
+		{name: "no header", etag: `"abc"`, want: true},
+		{name: "match", etag: `"abc"`, header: `"xyz", "abc"`, want: true},
+		{name: "wildcard", etag: `"abc"`, header: `*`, want: true},
+		{name: "no match", etag: `"abc"`, header: `"xyz"`, want: false},
+		{name: "weak header", etag: `"abc"`, header: `W/"abc"`, want: false},
+		{name: "weak etag", etag: `W/"abc"`, header: `W/"abc"`, want: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := newParamsTestContext("/")
+			if tc.header != "" {
+				c.Request.Header.Set("If-Match", tc.header)
+			}
+			assert.Equal(t, tc.want, CheckIfMatch(c, tc.etag))
+			if !tc.want {
+				assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+			}
+		})
+	}
+}
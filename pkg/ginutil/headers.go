@@ -0,0 +1,87 @@
+package ginutil
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
+)
+
+// RequireHeader tries to read the named header from the request and checks
+// that it's not empty.
+//
+// If it fails, it will write out a problem response using
+// WriteProblem with the status code 400 (Bad Request).
+func RequireHeader(c *gin.Context, headerName string) (string, bool) {
+	value := c.GetHeader(headerName)
+	if value == "" {
+		WriteProblem(c, problem.Response{
+			Type:     "/prob/api/missing-header",
+			Title:    "Missing header.",
+			Status:   http.StatusBadRequest,
+			Detail:   fmt.Sprintf("The header %q was expected, but it was either omitted or empty.", http.CanonicalHeaderKey(headerName)),
+			Instance: headerInstance(c, headerName),
+		})
+		return "", false
+	}
+	return value, true
+}
+
+// ParseHeaderInt tries to read the named header from the request and parse it
+// to an int.
+//
+// If it fails, it will write out a problem response using
+// WriteProblemError with the status code 400 (Bad Request).
+func ParseHeaderInt(c *gin.Context, headerName string) (int, bool) {
+	value, ok := RequireHeader(c, headerName)
+	if !ok {
+		return 0, false
+	}
+	return parseInt(c, headerParamName(headerName), value)
+}
+
+// ParseHeaderUint tries to read the named header from the request and parse
+// it to an uint.
+//
+// If it fails, it will write out a problem response using
+// WriteProblemError with the status code 400 (Bad Request).
+func ParseHeaderUint(c *gin.Context, headerName string) (uint, bool) {
+	value, ok := RequireHeader(c, headerName)
+	if !ok {
+		return 0, false
+	}
+	return parseUint(c, headerParamName(headerName), value)
+}
+
+// ParseHeaderTime tries to read the named header from the request and parse
+// it to a time.Time, using the HTTP date formats supported by http.ParseTime,
+// such as "Mon, 02 Jan 2006 15:04:05 GMT". Meant to be used with headers such
+// as If-Modified-Since.
+//
+// If it fails, it will write out a problem response using
+// WriteProblemError with the status code 400 (Bad Request).
+func ParseHeaderTime(c *gin.Context, headerName string) (time.Time, bool) {
+	str, ok := RequireHeader(c, headerName)
+	if !ok {
+		return time.Time{}, false
+	}
+	value, err := http.ParseTime(str)
+	if err != nil {
+		writeParseError(c, err, "/prob/api/invalid-param-time", "Invalid timestamp value.", headerParamName(headerName),
+			fmt.Sprintf("Failed to interpret header %q with value %q as an HTTP date, such as %q.", http.CanonicalHeaderKey(headerName), str, http.TimeFormat))
+		return time.Time{}, false
+	}
+	return value, true
+}
+
+// headerParamName is the name used for headers in the problem instance URI,
+// to distinguish them from path and query parameters.
+func headerParamName(headerName string) string {
+	return "header-" + http.CanonicalHeaderKey(headerName)
+}
+
+func headerInstance(c *gin.Context, headerName string) string {
+	return fmt.Sprintf("%s#%s", c.Request.RequestURI, headerParamName(headerName))
+}
@@ -0,0 +1,63 @@
+package ginutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newHeadersTestContext(headers map[string]string) (*gin.Context, *httptest.ResponseRecorder) {
+	c, w := newParamsTestContext("/webhook")
+	for key, value := range headers {
+		c.Request.Header.Set(key, value)
+	}
+	return c, w
+}
+
+func TestRequireHeader(t *testing.T) {
+	c, _ := newHeadersTestContext(map[string]string{"X-Hub-Signature": "sha256=abc"})
+	got, ok := RequireHeader(c, "x-hub-signature")
+	assert.True(t, ok)
+	assert.Equal(t, "sha256=abc", got)
+
+	c, w := newHeadersTestContext(nil)
+	_, ok = RequireHeader(c, "x-hub-signature")
+	assert.False(t, ok)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	prob, err := problem.ParseHTTPResponse(w.Result())
+	require.NoError(t, err)
+	assert.Equal(t, "/webhook#header-X-Hub-Signature", prob.Instance)
+}
+
+func TestParseHeaderInt(t *testing.T) {
+	c, _ := newHeadersTestContext(map[string]string{"X-Retry-Count": "3"})
+	got, ok := ParseHeaderInt(c, "X-Retry-Count")
+	assert.True(t, ok)
+	assert.Equal(t, 3, got)
+
+	c, w := newHeadersTestContext(map[string]string{"X-Retry-Count": "three"})
+	_, ok = ParseHeaderInt(c, "X-Retry-Count")
+	assert.False(t, ok)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	prob, err := problem.ParseHTTPResponse(w.Result())
+	require.NoError(t, err)
+	assert.Equal(t, "/webhook#header-X-Retry-Count", prob.Instance)
+}
+
+func TestParseHeaderTime(t *testing.T) {
+	c, _ := newHeadersTestContext(map[string]string{"If-Modified-Since": "Fri, 20 May 2022 12:30:00 GMT"})
+	got, ok := ParseHeaderTime(c, "If-Modified-Since")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2022, 5, 20, 12, 30, 0, 0, time.UTC), got)
+
+	c, w := newHeadersTestContext(map[string]string{"If-Modified-Since": "yesterday"})
+	_, ok = ParseHeaderTime(c, "If-Modified-Since")
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -3,7 +3,10 @@ package ginutil
 import (
 	"errors"
 	"io"
+	"net/http"
+	"path"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
@@ -11,11 +14,23 @@ import (
 
 // LoggerConfig holds configuration for the Gin logging integration.
 type LoggerConfig struct {
-	// Level is the logging level that each log message uses. Defaults to the
-	// zero value of logger.Level, which is logger.LevelDebug.
+	// Level is the logging level that each log message uses, unless
+	// overridden by the LevelRules or LevelFunc. Defaults to the zero value of
+	// logger.Level, which is logger.LevelDebug.
 	Level logger.Level
+	// LevelRules overrides the Level based on the response status code, where
+	// the first matching rule is used. See DefaultLevelRules for a set of
+	// rules that logs client errors as warnings and server errors as errors.
+	LevelRules []LevelRule
+	// LevelFunc overrides both the Level and the LevelRules when set, and is
+	// called after the request has been processed to obtain the logging level
+	// of its log message.
+	LevelFunc func(c *gin.Context) logger.Level
 	// Logger is the logger implementation used when logging.
 	Logger logger.Logger
+	// Message is the message of each logged request. Defaults to an empty
+	// message.
+	Message string
 	// OmitClientIP leaves out the client IP address that issued the web request
 	// from the logs when set to true.
 	OmitClientIP bool
@@ -34,9 +49,92 @@ type LoggerConfig struct {
 	// OmitError leaves out any Go errors that were thrown when processing the
 	// web request from the logs when set to true.
 	OmitError bool
+	// OmitRequestID leaves out the request ID, as set by the
+	// RequestIDWithConfig middleware, from the logs when set to true.
+	OmitRequestID bool
+	// OmitTraceID leaves out the trace ID, as returned by GetTraceID, from the
+	// logs when set to true.
+	OmitTraceID bool
+	// IncludeRoute adds the matched route pattern, such as
+	// "/projects/:projectId", to the logs when set to true.
+	IncludeRoute bool
+	// IncludeHost adds the host that the web request was sent to, as given by
+	// the Host HTTP header, to the logs when set to true.
+	IncludeHost bool
+	// IncludeProto adds the HTTP protocol version, such as "HTTP/1.1", to the
+	// logs when set to true.
+	IncludeProto bool
+	// IncludeUserAgent adds the User-Agent HTTP header to the logs when set to
+	// true.
+	IncludeUserAgent bool
+	// IncludeReferer adds the Referer HTTP header to the logs when set to
+	// true.
+	IncludeReferer bool
+	// IncludeRequestSize adds the number of bytes read from the web request
+	// body to the logs when set to true.
+	IncludeRequestSize bool
+	// IncludeResponseSize adds the number of bytes written to the web response
+	// body to the logs when set to true.
+	IncludeResponseSize bool
+	// IncludeHeaders is a list of request HTTP headers, such as
+	// "X-Forwarded-For" or "Content-Type", that are added to the logs as
+	// fields prefixed with "headers.", if present in the request. Multiple
+	// values of the same header are joined with commas.
+	//
+	// The values of the headers in RedactHeaders are replaced with
+	// "[REDACTED]".
+	IncludeHeaders []string
+	// RedactHeaders is a list of HTTP headers whose values are replaced with
+	// "[REDACTED]" when included via IncludeHeaders. Defaults to
+	// DefaultRedactHeaders.
+	RedactHeaders []string
 	// SkipPaths is a url path array which logs are not written. Useful for
 	// disabling logs issued by health checks.
+	//
+	// The paths may contain glob patterns, using the syntax of path.Match,
+	// such as "/api/*/health". A pattern ending with "/**" also matches all
+	// nested paths, such as "/static/**" matching "/static/js/app.js".
+	// Malformed patterns never match.
 	SkipPaths []string
+	// SkipFunc skips writing the log of a request when it returns true. It is
+	// called after the request has been processed, so it may also look at the
+	// response, such as to only skip successful health checks.
+	SkipFunc func(c *gin.Context) bool
+}
+
+// DefaultRedactHeaders is the list of HTTP headers that are redacted in the
+// logs by default when included via LoggerConfig.IncludeHeaders, as they may
+// contain credentials.
+var DefaultRedactHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Proxy-Authorization",
+	"Set-Cookie",
+}
+
+// redactedValue is written in place of redacted header values.
+const redactedValue = "[REDACTED]"
+
+// LevelRule is used in LoggerConfig.LevelRules to log requests with a response
+// status code between MinStatus and MaxStatus, inclusive, using its Level.
+type LevelRule struct {
+	MinStatus int
+	MaxStatus int
+	Level     logger.Level
+}
+
+// DefaultLevelRules is a set of level rules that logs server errors (5xx)
+// using logger.LevelError, and client errors (4xx) using logger.LevelWarn.
+//
+// Example usage:
+//
+// 	r.Use(ginutil.LoggerWithConfig(ginutil.LoggerConfig{
+// 		Level:      logger.LevelDebug,
+// 		LevelRules: ginutil.DefaultLevelRules,
+// 	}))
+var DefaultLevelRules = []LevelRule{
+	{MinStatus: 500, MaxStatus: 599, Level: logger.LevelError},
+	{MinStatus: 400, MaxStatus: 499, Level: logger.LevelWarn},
 }
 
 // DefaultLoggerHandler is a Gin-compatible logger that uses wharf-core logging.
@@ -53,40 +151,216 @@ func LoggerWithConfig(config LoggerConfig) gin.HandlerFunc {
 	if config.Logger == nil {
 		config.Logger = logger.NewScoped("GIN")
 	}
-	return gin.LoggerWithConfig(gin.LoggerConfig{
-		SkipPaths: config.SkipPaths,
-		Formatter: func(param gin.LogFormatterParams) string {
-			ev := logger.NewEventFromLogger(config.Logger, config.Level)
-			if !config.OmitClientIP {
-				ev = ev.WithString("clientIp", param.ClientIP)
-			}
-			if !config.OmitMethod {
-				ev = ev.WithString("method", param.Method)
+	skip := newSkipPaths(config.SkipPaths)
+	if config.RedactHeaders == nil {
+		config.RedactHeaders = DefaultRedactHeaders
+	}
+	headers := newLoggedHeaders(config.IncludeHeaders, config.RedactHeaders)
+	return func(c *gin.Context) {
+		start := time.Now()
+		reqPath := c.Request.URL.Path
+		if skip.match(reqPath) {
+			c.Next()
+			return
+		}
+		if raw := c.Request.URL.RawQuery; raw != "" {
+			reqPath += "?" + raw
+		}
+		var body *countingReadCloser
+		if config.IncludeRequestSize && c.Request.Body != nil {
+			body = &countingReadCloser{ReadCloser: c.Request.Body}
+			c.Request.Body = body
+		}
+
+		c.Next()
+
+		if config.SkipFunc != nil && config.SkipFunc(c) {
+			return
+		}
+		ev := logger.NewEventFromLogger(config.Logger, config.level(c))
+		if !config.OmitClientIP {
+			ev = ev.WithString(httpKey("clientIp"), c.ClientIP())
+		}
+		if !config.OmitMethod {
+			ev = ev.WithString(httpKey("method"), c.Request.Method)
+		}
+		if !config.OmitPath {
+			ev = ev.WithString(httpKey("path"), reqPath)
+		}
+		if config.IncludeRoute {
+			ev = ev.WithString(httpKey("route"), c.FullPath())
+		}
+		if config.IncludeHost {
+			ev = ev.WithString(httpKey("host"), c.Request.Host)
+		}
+		if config.IncludeProto {
+			ev = ev.WithString(httpKey("proto"), c.Request.Proto)
+		}
+		if config.IncludeUserAgent {
+			ev = ev.WithString(httpKey("userAgent"), c.Request.UserAgent())
+		}
+		if config.IncludeReferer {
+			ev = ev.WithString(httpKey("referer"), c.Request.Referer())
+		}
+		for _, h := range headers {
+			values := c.Request.Header.Values(h.name)
+			if len(values) == 0 {
+				continue
 			}
-			if !config.OmitPath {
-				ev = ev.WithString("path", param.Path)
+			value := redactedValue
+			if !h.redact {
+				value = strings.Join(values, ",")
 			}
-			if !config.OmitStatus {
-				ev = ev.WithInt("status", param.StatusCode)
+			ev = ev.WithString(h.key, value)
+		}
+		if !config.OmitStatus {
+			ev = ev.WithInt(httpKey("status"), c.Writer.Status())
+		}
+		if id := GetRequestID(c); id != "" && !config.OmitRequestID {
+			ev = ev.WithString(httpKey("requestId"), id)
+		}
+		if id := GetTraceID(c); id != "" && !config.OmitTraceID {
+			ev = ev.WithString(httpKey("traceId"), id)
+		}
+		if config.IncludeRequestSize {
+			var n int64
+			if body != nil {
+				n = body.n
 			}
-			if !config.OmitLatency {
-				ev = ev.WithDuration("latency", param.Latency)
+			ev = ev.WithInt64(httpKey("requestSize"), n)
+		}
+		if config.IncludeResponseSize {
+			// gin.ResponseWriter.Size returns -1 if nothing has been written
+			size := c.Writer.Size()
+			if size < 0 {
+				size = 0
 			}
-			if param.ErrorMessage != "" && !config.OmitError {
-				ev = ev.WithError(errors.New(param.ErrorMessage))
+			ev = ev.WithInt(httpKey("responseSize"), size)
+		}
+		if !config.OmitLatency {
+			ev = ev.WithDuration(httpKey("latency"), time.Since(start))
+		}
+		if msg := c.Errors.ByType(gin.ErrorTypePrivate).String(); msg != "" && !config.OmitError {
+			ev = ev.WithError(errors.New(msg))
+		}
+		ev.Message(config.Message)
+	}
+}
+
+type loggedHeader struct {
+	name   string
+	key    string
+	redact bool
+}
+
+func newLoggedHeaders(include, redact []string) []loggedHeader {
+	headers := make([]loggedHeader, 0, len(include))
+	for _, name := range include {
+		name = http.CanonicalHeaderKey(name)
+		headers = append(headers, loggedHeader{
+			name:   name,
+			key:    httpKey("headers." + name),
+			redact: containsHeader(redact, name),
+		})
+	}
+	return headers
+}
+
+func containsHeader(headers []string, name string) bool {
+	for _, h := range headers {
+		if strings.EqualFold(h, name) {
+			return true
+		}
+	}
+	return false
+}
+
+type skipPaths struct {
+	exact    map[string]struct{}
+	patterns []string
+}
+
+func newSkipPaths(paths []string) skipPaths {
+	skip := skipPaths{exact: make(map[string]struct{}, len(paths))}
+	for _, p := range paths {
+		if strings.ContainsAny(p, `*?[\`) {
+			skip.patterns = append(skip.patterns, p)
+		} else {
+			skip.exact[p] = struct{}{}
+		}
+	}
+	return skip
+}
+
+func (skip skipPaths) match(p string) bool {
+	if _, ok := skip.exact[p]; ok {
+		return true
+	}
+	for _, pattern := range skip.patterns {
+		if matchSkipPattern(pattern, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSkipPattern(pattern, p string) bool {
+	if strings.HasSuffix(pattern, "/**") {
+		base := strings.TrimSuffix(pattern, "/**")
+		end := nthIndexByte(p, '/', strings.Count(base, "/")+1)
+		if end == -1 {
+			return false
+		}
+		p = p[:end]
+		pattern = base
+	}
+	ok, _ := path.Match(pattern, p)
+	return ok
+}
+
+// nthIndexByte returns the index of the n-th occurrence of the byte c in s, or
+// -1 if there are fewer occurrences.
+func nthIndexByte(s string, c byte, n int) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == c {
+			n--
+			if n == 0 {
+				return i
 			}
-			ev.Message("")
-			return ""
-		},
-		// if writer is not set then it defaults to os.Stdout
-		Output: nopWriter{},
-	})
+		}
+	}
+	return -1
 }
 
-type nopWriter struct{}
+func (config LoggerConfig) level(c *gin.Context) logger.Level {
+	if config.LevelFunc != nil {
+		return config.LevelFunc(c)
+	}
+	status := c.Writer.Status()
+	for _, rule := range config.LevelRules {
+		if status >= rule.MinStatus && status <= rule.MaxStatus {
+			return rule.Level
+		}
+	}
+	return config.Level
+}
+
+// httpKey prefixes the field name with the "http" namespace, if enabled via
+// logger.SetFieldNamespacing.
+func httpKey(key string) string {
+	return logger.NamespacedKey(logger.NamespaceHTTP, key)
+}
+
+// countingReadCloser counts the number of bytes read from the request body.
+type countingReadCloser struct {
+	io.ReadCloser
+	n int64
+}
 
-func (w nopWriter) Write(b []byte) (int, error) {
-	return len(b), nil
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
 }
 
 // DefaultLoggerWriter is an io.Writer that logs all written messages using
@@ -0,0 +1,180 @@
+package ginutil
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerWithConfig(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	mock := logger.NewMock()
+	r := gin.New()
+	r.Use(LoggerWithConfig(LoggerConfig{
+		Logger:              mock,
+		Message:             "Request.",
+		IncludeRoute:        true,
+		IncludeHost:         true,
+		IncludeProto:        true,
+		IncludeUserAgent:    true,
+		IncludeReferer:      true,
+		IncludeRequestSize:  true,
+		IncludeResponseSize: true,
+		SkipPaths:           []string{"/health"},
+	}))
+	r.POST("/projects/:projectId", func(c *gin.Context) {
+		io.ReadAll(c.Request.Body)
+		c.Error(errors.New("oh no"))
+		c.String(http.StatusCreated, "created")
+	})
+	r.GET("/health", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/projects/12?a=1", strings.NewReader("hello"))
+	req.Header.Set("User-Agent", "test-agent")
+	req.Header.Set("Referer", "http://example.com/")
+	req.RemoteAddr = "10.0.0.1:1234"
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	require.Len(t, mock.Logs, 1, "skipped path should not be logged")
+	log := mock.Logs[0]
+	assert.Equal(t, "Request.", log.Message)
+	assert.Equal(t, "10.0.0.1", log.Fields["clientIp"])
+	assert.Equal(t, http.MethodPost, log.Fields["method"])
+	assert.Equal(t, "/projects/12?a=1", log.Fields["path"])
+	assert.Equal(t, "/projects/:projectId", log.Fields["route"])
+	assert.Equal(t, "example.com", log.Fields["host"])
+	assert.Equal(t, "HTTP/1.1", log.Fields["proto"])
+	assert.Equal(t, "test-agent", log.Fields["userAgent"])
+	assert.Equal(t, "http://example.com/", log.Fields["referer"])
+	assert.Equal(t, http.StatusCreated, log.Fields["status"])
+	assert.Equal(t, int64(5), log.Fields["requestSize"])
+	assert.Equal(t, 7, log.Fields["responseSize"])
+	assert.Contains(t, log.Fields, "latency")
+	assert.Contains(t, log.Fields["error"].(error).Error(), "oh no")
+}
+
+func TestLoggerConfig_level(t *testing.T) {
+	tests := []struct {
+		name   string
+		config LoggerConfig
+		status int
+		want   logger.Level
+	}{
+		{
+			name:   "fixed level",
+			config: LoggerConfig{Level: logger.LevelInfo},
+			status: http.StatusInternalServerError,
+			want:   logger.LevelInfo,
+		},
+		{
+			name:   "rules server error",
+			config: LoggerConfig{LevelRules: DefaultLevelRules},
+			status: http.StatusBadGateway,
+			want:   logger.LevelError,
+		},
+		{
+			name:   "rules client error",
+			config: LoggerConfig{LevelRules: DefaultLevelRules},
+			status: http.StatusNotFound,
+			want:   logger.LevelWarn,
+		},
+		{
+			name:   "rules fallback",
+			config: LoggerConfig{Level: logger.LevelInfo, LevelRules: DefaultLevelRules},
+			status: http.StatusOK,
+			want:   logger.LevelInfo,
+		},
+		{
+			name: "func override",
+			config: LoggerConfig{
+				LevelRules: DefaultLevelRules,
+				LevelFunc:  func(*gin.Context) logger.Level { return logger.LevelDebug },
+			},
+			status: http.StatusBadGateway,
+			want:   logger.LevelDebug,
+		},
+	}
+	gin.SetMode(gin.ReleaseMode)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Status(tc.status)
+			assert.Equal(t, tc.want, tc.config.level(c))
+		})
+	}
+}
+
+func TestSkipPaths_match(t *testing.T) {
+	skip := newSkipPaths([]string{"/health", "/api/*/metrics", "/static/**", "/bad["})
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/health", true},
+		{"/healthz", false},
+		{"/api/v1/metrics", true},
+		{"/api/v1/v2/metrics", false},
+		{"/static/", true},
+		{"/static/js/app.js", true},
+		{"/static", false},
+		{"/staticfoo/app.js", false},
+		{"/bad[", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.want, skip.match(tc.path))
+		})
+	}
+}
+
+func TestLoggerWithConfig_skipFunc(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	mock := logger.NewMock()
+	r := gin.New()
+	r.Use(LoggerWithConfig(LoggerConfig{
+		Logger: mock,
+		SkipFunc: func(c *gin.Context) bool {
+			return c.Writer.Status() < http.StatusBadRequest
+		},
+	}))
+	r.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/fail", func(c *gin.Context) { c.Status(http.StatusServiceUnavailable) })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	require.Len(t, mock.Logs, 1)
+	assert.Equal(t, "/fail", mock.Logs[0].Fields["path"])
+}
+
+func TestLoggerWithConfig_includeHeaders(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	mock := logger.NewMock()
+	r := gin.New()
+	r.Use(LoggerWithConfig(LoggerConfig{
+		Logger:         mock,
+		IncludeHeaders: []string{"x-forwarded-for", "Authorization", "Content-Type"},
+	}))
+	r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Add("X-Forwarded-For", "10.0.0.1")
+	req.Header.Add("X-Forwarded-For", "10.0.0.2")
+	req.Header.Set("Authorization", "Bearer secret")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Len(t, mock.Logs, 1)
+	fields := mock.Logs[0].Fields
+	assert.Equal(t, "10.0.0.1,10.0.0.2", fields["headers.X-Forwarded-For"])
+	assert.Equal(t, "[REDACTED]", fields["headers.Authorization"])
+	assert.NotContains(t, fields, "headers.Content-Type")
+}
@@ -0,0 +1,175 @@
+package ginutil
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger/ringbuffer"
+)
+
+// LogsAdminConfig holds configuration for the logging admin endpoints.
+type LogsAdminConfig struct {
+	// Sink is the ring buffer logging sink that the recent logs are read
+	// from. The recent logs endpoint is not registered if left unset.
+	Sink *ringbuffer.Sink
+	// Authorize is called before each request to the admin endpoints, and
+	// responds with 401 (Unauthorized) if it returns false. Defaults to
+	// rejecting all requests, so it must be set for the endpoints to be
+	// usable.
+	Authorize func(c *gin.Context) bool
+}
+
+// LogLevelRequest is the request body of the log level admin endpoint.
+type LogLevelRequest struct {
+	// Level is the new minimum logging level, such as "debug" or "warn", as
+	// parsed by logger.ParseLevel.
+	Level string `json:"level" binding:"required" example:"warn"`
+	// Scope is the scope to change the logging level of. The global logging
+	// level is changed if left empty.
+	Scope string `json:"scope" example:"GORM"`
+}
+
+// LogLevelResponse is the response body of the log level admin endpoint.
+type LogLevelResponse struct {
+	Level string `json:"level" example:"Warning"`
+	Scope string `json:"scope,omitempty" example:"GORM"`
+}
+
+// RegisterLogsAdminHandlers registers admin endpoints for production triage
+// on the router, guarded by the LogsAdminConfig.Authorize function:
+//
+// 	GET /debug/logs        // see RecentLogsHandler
+// 	PUT /debug/logs/level  // see SetLogLevelHandler
+//
+// Example usage:
+//
+// 	logs := ringbuffer.New(ringbuffer.Config{})
+// 	logger.AddOutput(logger.LevelDebug, logs)
+// 	ginutil.RegisterLogsAdminHandlers(r, ginutil.LogsAdminConfig{
+// 		Sink: logs,
+// 		Authorize: func(c *gin.Context) bool {
+// 			return c.GetHeader("X-Admin-Token") == adminToken
+// 		},
+// 	})
+func RegisterLogsAdminHandlers(r gin.IRouter, config LogsAdminConfig) {
+	g := r.Group("/debug/logs", authorizeHandler(config.Authorize))
+	if config.Sink != nil {
+		g.GET("", RecentLogsHandler(config.Sink))
+	}
+	g.PUT("/level", SetLogLevelHandler)
+}
+
+func authorizeHandler(authorize func(c *gin.Context) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if authorize == nil || !authorize(c) {
+			WriteUnauthorized(c, "Access to the logging admin endpoints was denied.")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RecentLogsHandler creates a Gin handler that responds with the recent log
+// events stored in the ring buffer logging sink as a JSON array, ordered from
+// oldest to newest.
+//
+// The events can be filtered using the query parameters, which are all
+// optional:
+//
+// 	level  // minimum logging level, such as "warn"
+// 	scope  // logger scope, such as "GORM"
+// 	since  // RFC-3339 timestamp of the oldest event
+// 	until  // RFC-3339 timestamp of the newest event
+// 	limit  // maximum number of events, keeping the most recent ones
+func RecentLogsHandler(sink *ringbuffer.Sink) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter, ok := parseLogsFilter(c)
+		if !ok {
+			return
+		}
+		events := sink.Query(filter)
+		if events == nil {
+			events = []ringbuffer.Event{}
+		}
+		c.JSON(http.StatusOK, events)
+	}
+}
+
+func parseLogsFilter(c *gin.Context) (ringbuffer.Filter, bool) {
+	var filter ringbuffer.Filter
+	if s := c.Query("level"); s != "" {
+		level, err := logger.ParseLevel(s)
+		if err != nil {
+			WriteInvalidParamError(c, err, "level", fmt.Sprintf("Invalid logging level: %q.", s))
+			return filter, false
+		}
+		filter.MinLevel = level
+	}
+	filter.Scope = c.Query("scope")
+	for _, param := range []struct {
+		name  string
+		value *time.Time
+	}{
+		{"since", &filter.Since},
+		{"until", &filter.Until},
+	} {
+		s := c.Query(param.name)
+		if s == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			WriteInvalidParamError(c, err, param.name,
+				fmt.Sprintf("Invalid RFC-3339 timestamp: %q.", s))
+			return filter, false
+		}
+		*param.value = t
+	}
+	if s := c.Query("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err == nil && limit < 0 {
+			err = errors.New("negative limit")
+		}
+		if err != nil {
+			WriteInvalidParamError(c, err, "limit",
+				fmt.Sprintf("Invalid limit, expected a positive integer: %q.", s))
+			return filter, false
+		}
+		filter.Limit = limit
+	}
+	return filter, true
+}
+
+// SetLogLevelHandler is a Gin handler that changes the minimum logging level
+// at runtime, either globally via logger.SetLevel or for a single scope via
+// logger.SetLevelScoped, based on the LogLevelRequest JSON request body.
+func SetLogLevelHandler(c *gin.Context) {
+	req, ok := BindJSON[LogLevelRequest](c)
+	if !ok {
+		return
+	}
+	level, err := logger.ParseLevel(req.Level)
+	if err != nil {
+		WriteInvalidBindError(c, err, fmt.Sprintf("Invalid logging level: %q.", req.Level))
+		return
+	}
+	if req.Scope == "" {
+		logger.SetLevel(level)
+	} else {
+		logger.SetLevelScoped(level, req.Scope)
+	}
+	Logger(c).Info().
+		WithString("level", level.String()).
+		WithString("scope", req.Scope).
+		Message("Changed logging level.")
+	c.JSON(http.StatusOK, LogLevelResponse{
+		Level: level.String(),
+		Scope: req.Scope,
+	})
+}
@@ -0,0 +1,170 @@
+package ginutil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger/ringbuffer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLogsAdminRouter(sink *ringbuffer.Sink) *gin.Engine {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	RegisterLogsAdminHandlers(r, LogsAdminConfig{
+		Sink: sink,
+		Authorize: func(c *gin.Context) bool {
+			return c.GetHeader("X-Admin-Token") == "secret"
+		},
+	})
+	return r
+}
+
+func TestRecentLogsHandler(t *testing.T) {
+	sink := ringbuffer.New(ringbuffer.Config{})
+	for _, ctx := range []struct {
+		scope string
+		level logger.Level
+		msg   string
+	}{
+		{"GORM", logger.LevelDebug, "Query."},
+		{"GORM", logger.LevelWarn, "Slow query."},
+		{"GIN", logger.LevelWarn, "Not found."},
+	} {
+		sink.NewContext(ctx.scope).WriteOut(ctx.level, ctx.msg)
+	}
+	r := newLogsAdminRouter(sink)
+
+	tests := []struct {
+		name       string
+		query      string
+		token      string
+		wantStatus int
+		want       []string
+	}{
+		{
+			name:       "unauthorized",
+			token:      "wrong",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "all",
+			token:      "secret",
+			wantStatus: http.StatusOK,
+			want:       []string{"Query.", "Slow query.", "Not found."},
+		},
+		{
+			name:       "filtered",
+			query:      "?level=warn&scope=gorm",
+			token:      "secret",
+			wantStatus: http.StatusOK,
+			want:       []string{"Slow query."},
+		},
+		{
+			name:       "limit",
+			query:      "?limit=1",
+			token:      "secret",
+			wantStatus: http.StatusOK,
+			want:       []string{"Not found."},
+		},
+		{
+			name:       "invalid level",
+			query:      "?level=loud",
+			token:      "secret",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid limit",
+			query:      "?limit=-1",
+			token:      "secret",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/debug/logs"+tc.query, nil)
+			req.Header.Set("X-Admin-Token", tc.token)
+			r.ServeHTTP(w, req)
+			require.Equal(t, tc.wantStatus, w.Code, w.Body.String())
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			var events []struct {
+				Message string `json:"message"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &events))
+			var got []string
+			for _, ev := range events {
+				got = append(got, ev.Message)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestSetLogLevelHandler(t *testing.T) {
+	defer logger.SetLevel(logger.LevelDebug)
+	defer logger.SetLevelScoped(logger.LevelDebug, "GORM")
+	r := newLogsAdminRouter(nil)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		want       LogLevelResponse
+	}{
+		{
+			name:       "global",
+			body:       `{"level": "warn"}`,
+			wantStatus: http.StatusOK,
+			want:       LogLevelResponse{Level: "Warning"},
+		},
+		{
+			name:       "scoped",
+			body:       `{"level": "error", "scope": "GORM"}`,
+			wantStatus: http.StatusOK,
+			want:       LogLevelResponse{Level: "Error", Scope: "GORM"},
+		},
+		{
+			name:       "invalid level",
+			body:       `{"level": "loud"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "missing level",
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/debug/logs/level", strings.NewReader(tc.body))
+			req.Header.Set("X-Admin-Token", "secret")
+			r.ServeHTTP(w, req)
+			require.Equal(t, tc.wantStatus, w.Code, w.Body.String())
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			var got LogLevelResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRegisterLogsAdminHandlers_defaultDenies(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	RegisterLogsAdminHandlers(r, LogsAdminConfig{})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/debug/logs/level", strings.NewReader(`{"level": "warn"}`)))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,160 @@
+package ginutil
+
+import (
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
+)
+
+// errBodyTooLargeMessage is the message of the error returned by
+// http.MaxBytesReader when the limit is exceeded. Go 1.18 does not export any
+// error type or value for this, so the message is compared instead.
+const errBodyTooLargeMessage = "http: request body too large"
+
+// MultipartConfig holds the restrictions used by ReadMultipartForm and
+// ReadMultipartFile.
+type MultipartConfig struct {
+	// MaxMemory is the maximum number of bytes of the files that are kept in
+	// memory, while the rest is stored in temporary files on disk. Defaults to
+	// 32 MiB, same as gin.Engine.MaxMultipartMemory.
+	MaxMemory int64
+	// MaxRequestSize is the maximum size in bytes of the whole request body.
+	// No limit is used when zero.
+	MaxRequestSize int64
+	// MaxFileSize is the maximum size in bytes of each uploaded file. No limit
+	// is used when zero.
+	MaxFileSize int64
+	// AllowedContentTypes is the list of media types that the uploaded files
+	// may have, as sent by the client in the Content-Type header of each
+	// file. All media types are allowed when empty.
+	//
+	// When set to "image/*": all image media types are allowed, such as
+	// "image/png" and "image/jpeg".
+	AllowedContentTypes []string
+}
+
+// ReadMultipartForm parses the request body as a multipart form and validates
+// all uploaded files against the restrictions in the config.
+//
+// If it fails, it will write out a problem response using WriteProblemError
+// with the status code 413 (Request Entity Too Large) if a size limit was
+// exceeded, or with the status code 400 (Bad Request) otherwise, referencing
+// the offending file name in the problem detail.
+func ReadMultipartForm(c *gin.Context, config MultipartConfig) (*multipart.Form, bool) {
+	if config.MaxMemory <= 0 {
+		config.MaxMemory = 32 << 20
+	}
+	if config.MaxRequestSize > 0 {
+		if c.Request.ContentLength > config.MaxRequestSize {
+			writeRequestTooLarge(c, fmt.Errorf("content length %d exceeds limit %d", c.Request.ContentLength, config.MaxRequestSize), config.MaxRequestSize)
+			return nil, false
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.MaxRequestSize)
+	}
+	if err := c.Request.ParseMultipartForm(config.MaxMemory); err != nil {
+		if strings.Contains(err.Error(), errBodyTooLargeMessage) {
+			writeRequestTooLarge(c, err, config.MaxRequestSize)
+		} else {
+			WriteMultipartFormReadError(c, err, "Failed to read the multipart form data.")
+		}
+		return nil, false
+	}
+	form := c.Request.MultipartForm
+	fieldNames := make([]string, 0, len(form.File))
+	for fieldName := range form.File {
+		fieldNames = append(fieldNames, fieldName)
+	}
+	sort.Strings(fieldNames)
+	for _, fieldName := range fieldNames {
+		for _, file := range form.File[fieldName] {
+			if !config.validateFile(c, fieldName, file) {
+				return nil, false
+			}
+		}
+	}
+	return form, true
+}
+
+// ReadMultipartFile uses ReadMultipartForm to parse and validate the request
+// body, and returns the first file uploaded in the named form field.
+//
+// If the file is missing, it will write out a problem response using
+// WriteProblem with the status code 400 (Bad Request).
+func ReadMultipartFile(c *gin.Context, fieldName string, config MultipartConfig) (*multipart.FileHeader, bool) {
+	form, ok := ReadMultipartForm(c, config)
+	if !ok {
+		return nil, false
+	}
+	files := form.File[fieldName]
+	if len(files) == 0 {
+		WriteProblem(c, problem.Response{
+			Type:     "/prob/api/missing-file",
+			Title:    "Missing file.",
+			Status:   http.StatusBadRequest,
+			Detail:   fmt.Sprintf("A file was expected in the multipart form field %q, but it was omitted.", fieldName),
+			Instance: fmt.Sprintf("%s#%s", c.Request.RequestURI, fieldName),
+		})
+		return nil, false
+	}
+	return files[0], true
+}
+
+func (config MultipartConfig) validateFile(c *gin.Context, fieldName string, file *multipart.FileHeader) bool {
+	if config.MaxFileSize > 0 && file.Size > config.MaxFileSize {
+		WriteProblemError(c, fmt.Errorf("file %q size %d exceeds limit %d", file.Filename, file.Size, config.MaxFileSize), problem.Response{
+			Type:     "/prob/api/file-too-large",
+			Title:    "File too large.",
+			Status:   http.StatusRequestEntityTooLarge,
+			Detail:   fmt.Sprintf("The file %q in field %q is %d bytes, but the limit is %d bytes.", file.Filename, fieldName, file.Size, config.MaxFileSize),
+			Instance: fmt.Sprintf("%s#%s", c.Request.RequestURI, fieldName),
+		})
+		return false
+	}
+	if len(config.AllowedContentTypes) == 0 {
+		return true
+	}
+	contentType := file.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && isAllowedMediaType(config.AllowedContentTypes, mediaType) {
+		return true
+	}
+	if err == nil {
+		err = fmt.Errorf("file %q media type not allowed: %q", file.Filename, mediaType)
+	}
+	WriteProblemError(c, err, problem.Response{
+		Type:     "/prob/api/invalid-file-type",
+		Title:    "Invalid file type.",
+		Status:   http.StatusBadRequest,
+		Detail:   fmt.Sprintf("The file %q in field %q has the content type %q, but only %s are allowed.", file.Filename, fieldName, contentType, strings.Join(config.AllowedContentTypes, ", ")),
+		Instance: fmt.Sprintf("%s#%s", c.Request.RequestURI, fieldName),
+	})
+	return false
+}
+
+func isAllowedMediaType(allowed []string, mediaType string) bool {
+	for _, a := range allowed {
+		if strings.EqualFold(a, mediaType) {
+			return true
+		}
+		if strings.HasSuffix(a, "/*") &&
+			strings.HasPrefix(strings.ToLower(mediaType), strings.ToLower(a[:len(a)-1])) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeRequestTooLarge(c *gin.Context, err error, maxSize int64) {
+	WriteProblemError(c, err, problem.Response{
+		Type:   "/prob/api/request-too-large",
+		Title:  "Request too large.",
+		Status: http.StatusRequestEntityTooLarge,
+		Detail: fmt.Sprintf("The request body exceeds the limit of %d bytes.", maxSize),
+	})
+}
@@ -0,0 +1,113 @@
+package ginutil
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type multipartTestFile struct {
+	field       string
+	name        string
+	contentType string
+	content     string
+}
+
+func newMultipartTestContext(t *testing.T, files ...multipartTestFile) (*gin.Context, *httptest.ResponseRecorder) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, f := range files {
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", `form-data; name="`+f.field+`"; filename="`+f.name+`"`)
+		h.Set("Content-Type", f.contentType)
+		part, err := mw.CreatePart(h)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(f.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, mw.Close())
+	gin.SetMode(gin.ReleaseMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/upload", &body)
+	c.Request.Header.Set("Content-Type", mw.FormDataContentType())
+	return c, w
+}
+
+func TestReadMultipartForm(t *testing.T) {
+	logo := multipartTestFile{"logo", "logo.png", "image/png", "png data"}
+	readme := multipartTestFile{"readme", "README.md", "text/markdown", "# Hello"}
+	tests := []struct {
+		name       string
+		config     MultipartConfig
+		files      []multipartTestFile
+		wantStatus int
+		wantDetail string
+	}{
+		{
+			name:   "no restrictions",
+			files:  []multipartTestFile{logo, readme},
+			config: MultipartConfig{},
+		},
+		{
+			name:   "allowed content type wildcard",
+			files:  []multipartTestFile{logo},
+			config: MultipartConfig{AllowedContentTypes: []string{"image/*"}},
+		},
+		{
+			name:       "disallowed content type",
+			files:      []multipartTestFile{logo, readme},
+			config:     MultipartConfig{AllowedContentTypes: []string{"image/*"}},
+			wantStatus: http.StatusBadRequest,
+			wantDetail: "README.md",
+		},
+		{
+			name:       "file too large",
+			files:      []multipartTestFile{logo},
+			config:     MultipartConfig{MaxFileSize: 4},
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantDetail: "logo.png",
+		},
+		{
+			name:       "request too large",
+			files:      []multipartTestFile{{"logo", "logo.png", "image/png", strings.Repeat("x", 2048)}},
+			config:     MultipartConfig{MaxRequestSize: 1024},
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := newMultipartTestContext(t, tc.files...)
+			form, ok := ReadMultipartForm(c, tc.config)
+			if tc.wantStatus == 0 {
+				require.True(t, ok, w.Body.String())
+				assert.Len(t, form.File, len(tc.files))
+				return
+			}
+			assert.False(t, ok)
+			assert.Equal(t, tc.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.wantDetail)
+		})
+	}
+}
+
+func TestReadMultipartFile(t *testing.T) {
+	c, _ := newMultipartTestContext(t, multipartTestFile{"logo", "logo.png", "image/png", "png data"})
+	file, ok := ReadMultipartFile(c, "logo", MultipartConfig{})
+	require.True(t, ok)
+	assert.Equal(t, "logo.png", file.Filename)
+
+	c, w := newMultipartTestContext(t, multipartTestFile{"logo", "logo.png", "image/png", "png data"})
+	_, ok = ReadMultipartFile(c, "icon", MultipartConfig{})
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "/prob/api/missing-file")
+}
@@ -0,0 +1,92 @@
+package ginutil
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
+)
+
+// UseProblemRouteHandlers configures the Gin engine to respond with problem
+// responses instead of Gin's default plain-text bodies when no route matches
+// the request, by registering NoRoute and NoMethod.
+//
+// This also sets gin.Engine.HandleMethodNotAllowed to true.
+func UseProblemRouteHandlers(r *gin.Engine) {
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(NoRoute)
+	r.NoMethod(NoMethod(r))
+}
+
+// NoRoute uses WriteProblem to write a 404 "Not Found" response with the type
+// "/prob/api/route-not-found".
+//
+// Meant to be registered using gin.Engine.NoRoute.
+func NoRoute(c *gin.Context) {
+	WriteProblem(c, problem.Response{
+		Type:   "/prob/api/route-not-found",
+		Title:  "Route not found.",
+		Status: http.StatusNotFound,
+		Detail: fmt.Sprintf("No endpoint was found for the path %q.", c.Request.URL.Path),
+	})
+}
+
+// NoMethod creates a Gin handler that uses WriteProblem to write a
+// 405 "Method Not Allowed" response with the type
+// "/prob/api/method-not-allowed". The allowed methods are looked up from the
+// routes of the Gin engine and are written in the Allow HTTP header.
+//
+// Meant to be registered using gin.Engine.NoMethod, and requires
+// gin.Engine.HandleMethodNotAllowed to be set to true.
+func NoMethod(r *gin.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed := allowedMethods(r.Routes(), c.Request.URL.Path)
+		if len(allowed) > 0 {
+			c.Header("Allow", strings.Join(allowed, ", "))
+		}
+		WriteProblem(c, problem.Response{
+			Type:   "/prob/api/method-not-allowed",
+			Title:  "Method not allowed.",
+			Status: http.StatusMethodNotAllowed,
+			Detail: fmt.Sprintf("The method %s is not allowed for the path %q. Allowed methods: %s.",
+				c.Request.Method, c.Request.URL.Path, strings.Join(allowed, ", ")),
+		})
+	}
+}
+
+func allowedMethods(routes gin.RoutesInfo, path string) []string {
+	var methods []string
+	for _, route := range routes {
+		if routePathMatches(route.Path, path) {
+			methods = append(methods, route.Method)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+func routePathMatches(pattern, path string) bool {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "*") {
+			return true
+		}
+		if i >= len(pathSegments) {
+			return false
+		}
+		if strings.HasPrefix(segment, ":") {
+			if pathSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+	return len(patternSegments) == len(pathSegments)
+}
@@ -0,0 +1,39 @@
+package ginutil_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/ginutil"
+)
+
+func ExampleUseProblemRouteHandlers() {
+	r := gin.New()
+	ginutil.UseProblemRouteHandlers(r)
+	r.GET("/projects/:projectId", func(c *gin.Context) {})
+	r.PUT("/projects/:projectId", func(c *gin.Context) {})
+
+	// Faking a request here
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("DELETE", "/projects/123", nil))
+
+	resp := w.Result()
+	fmt.Println("HTTP/1.1", resp.Status)
+	fmt.Println("Allow:", resp.Header.Get("Allow"))
+	fmt.Println()
+	fmt.Println(indentedBodyFromResponse(resp))
+
+	// Output:
+	// HTTP/1.1 405 Method Not Allowed
+	// Allow: GET, PUT
+	//
+	// {
+	//   "type": "https://wharf.iver.com/#/prob/api/method-not-allowed",
+	//   "title": "Method not allowed.",
+	//   "status": 405,
+	//   "detail": "The method DELETE is not allowed for the path \"/projects/123\". Allowed methods: GET, PUT.",
+	//   "instance": "/projects/123",
+	//   "errors": null
+	// }
+}
@@ -0,0 +1,29 @@
+package ginutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutePathMatches(t *testing.T) {
+	var testCases = []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/projects", "/projects", true},
+		{"/projects", "/projects/", true},
+		{"/projects", "/builds", false},
+		{"/projects/:id", "/projects/123", true},
+		{"/projects/:id", "/projects", false},
+		{"/projects/:id", "/projects/123/builds", false},
+		{"/files/*path", "/files/a/b/c", true},
+		{"/", "/", true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pattern+" "+tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.want, routePathMatches(tc.pattern, tc.path))
+		})
+	}
+}
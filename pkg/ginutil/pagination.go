@@ -0,0 +1,172 @@
+package ginutil
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderTotalCount is the HTTP header written by WritePaginationHeaders with
+// the total number of items in a paginated list.
+const HeaderTotalCount = "X-Total-Count"
+
+// PaginationConfig holds the defaults and bounds used by
+// ParseQueryPagination.
+type PaginationConfig struct {
+	// DefaultLimit is the limit used when the client did not specify one.
+	// Defaults to 100.
+	DefaultLimit int
+	// MaxLimit is the largest limit that the client may request. Defaults
+	// to 1000.
+	MaxLimit int
+}
+
+// Pagination is a parsed limit and offset of a paginated list.
+type Pagination struct {
+	// Limit is the maximum number of items to return.
+	Limit int
+	// Offset is the number of items to skip.
+	Offset int
+}
+
+// Page returns the 1-based page number of the pagination, when using Limit as
+// the page size.
+func (p Pagination) Page() int {
+	if p.Limit <= 0 {
+		return 1
+	}
+	return p.Offset/p.Limit + 1
+}
+
+// ParseQueryPagination parses the pagination query parameters from the
+// request, either as "limit" and "offset", or as "page" and "pageSize" where
+// the page number starts at 1. Omitted parameters use the defaults from the
+// config.
+//
+// If it fails, such as when the limit exceeds PaginationConfig.MaxLimit, it
+// will write out a problem response using WriteInvalidParamError with the
+// status code 400 (Bad Request).
+//
+// Example usage:
+//
+// 	func listProjectsHandler(c *gin.Context) {
+// 		page, ok := ginutil.ParseQueryPagination(c, ginutil.PaginationConfig{})
+// 		if !ok {
+// 			return
+// 		}
+// 		var projects []Project
+// 		var total int64
+// 		db.Model(&Project{}).Count(&total)
+// 		db.Limit(page.Limit).Offset(page.Offset).Find(&projects)
+// 		ginutil.WritePaginationHeaders(c, page, total)
+// 		c.JSON(http.StatusOK, projects)
+// 	}
+func ParseQueryPagination(c *gin.Context, config PaginationConfig) (Pagination, bool) {
+	if config.DefaultLimit <= 0 {
+		config.DefaultLimit = 100
+	}
+	if config.MaxLimit <= 0 {
+		config.MaxLimit = 1000
+	}
+	if config.DefaultLimit > config.MaxLimit {
+		config.DefaultLimit = config.MaxLimit
+	}
+	limitName := "limit"
+	if _, usePages := c.GetQuery("page"); usePages {
+		limitName = "pageSize"
+	} else if _, usePages := c.GetQuery("pageSize"); usePages {
+		limitName = "pageSize"
+	}
+	limit, ok := parsePaginationQuery(c, limitName, config.DefaultLimit, 1, config.MaxLimit)
+	if !ok {
+		return Pagination{}, false
+	}
+	if limitName == "pageSize" {
+		// bounded so that the offset does not overflow
+		page, ok := parsePaginationQuery(c, "page", 1, 1, math.MaxInt/limit)
+		if !ok {
+			return Pagination{}, false
+		}
+		return Pagination{Limit: limit, Offset: (page - 1) * limit}, true
+	}
+	offset, ok := parsePaginationQuery(c, "offset", 0, 0, 0)
+	if !ok {
+		return Pagination{}, false
+	}
+	return Pagination{Limit: limit, Offset: offset}, true
+}
+
+// parsePaginationQuery parses an integer query parameter in the range of
+// [lower, upper], where an upper bound of 0 means no upper bound.
+func parsePaginationQuery(c *gin.Context, queryName string, defaultValue, lower, upper int) (int, bool) {
+	str, ok := c.GetQuery(queryName)
+	if !ok || str == "" {
+		return defaultValue, true
+	}
+	value, err := strconv.Atoi(str)
+	if err != nil {
+		WriteInvalidParamError(c, err, queryName, fmt.Sprintf(
+			"Failed to interpret parameter %q with value %q as an integer.", queryName, str))
+		return 0, false
+	}
+	if value < lower || (upper > 0 && value > upper) {
+		var detail string
+		if upper > 0 {
+			detail = fmt.Sprintf("Parameter %q must be between %d and %d, but was %d.", queryName, lower, upper, value)
+		} else {
+			detail = fmt.Sprintf("Parameter %q must be at least %d, but was %d.", queryName, lower, value)
+		}
+		WriteInvalidParamError(c, fmt.Errorf("pagination %s out of range: %d", queryName, value), queryName, detail)
+		return 0, false
+	}
+	return value, true
+}
+
+// WritePaginationHeaders writes the total number of items in the
+// X-Total-Count header, and links to the first, previous, next, and last
+// pages in the Link header, as defined in RFC 8288. The links keep all other
+// query parameters of the request, and use the "limit" and "offset" query
+// parameters.
+func WritePaginationHeaders(c *gin.Context, p Pagination, totalCount int64) {
+	c.Header(HeaderTotalCount, strconv.FormatInt(totalCount, 10))
+	if p.Limit <= 0 || c.Request == nil || c.Request.URL == nil {
+		return
+	}
+	var links []string
+	addLink := func(rel string, offset int64) {
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, paginationURL(c.Request.URL, p.Limit, offset), rel))
+	}
+	limit := int64(p.Limit)
+	offset := int64(p.Offset)
+	var lastOffset int64
+	if totalCount > 0 {
+		lastOffset = (totalCount - 1) / limit * limit
+	}
+	addLink("first", 0)
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		addLink("prev", prev)
+	}
+	if offset+limit < totalCount {
+		addLink("next", offset+limit)
+	}
+	addLink("last", lastOffset)
+	c.Header("Link", strings.Join(links, ", "))
+}
+
+func paginationURL(u *url.URL, limit int, offset int64) string {
+	query := u.Query()
+	query.Del("page")
+	query.Del("pageSize")
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.FormatInt(offset, 10))
+	link := url.URL{Path: u.Path, RawQuery: query.Encode()}
+	return link.String()
+}
@@ -0,0 +1,96 @@
+package ginutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQueryPagination(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	config := PaginationConfig{DefaultLimit: 20, MaxLimit: 50}
+	tests := []struct {
+		name   string
+		query  string
+		want   Pagination
+		wantOK bool
+	}{
+		{name: "defaults", want: Pagination{Limit: 20}, wantOK: true},
+		{name: "limit and offset", query: "?limit=10&offset=30", want: Pagination{Limit: 10, Offset: 30}, wantOK: true},
+		{name: "page and page size", query: "?page=3&pageSize=10", want: Pagination{Limit: 10, Offset: 20}, wantOK: true},
+		{name: "page only", query: "?page=2", want: Pagination{Limit: 20, Offset: 20}, wantOK: true},
+		{name: "limit too large", query: "?limit=51"},
+		{name: "limit zero", query: "?limit=0"},
+		{name: "negative offset", query: "?offset=-1"},
+		{name: "page zero", query: "?page=0"},
+		{name: "page offset overflow", query: "?page=9223372036854775807&pageSize=10"},
+		{name: "not a number", query: "?limit=ten"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/projects"+tc.query, nil)
+			got, ok := ParseQueryPagination(c, config)
+			assert.Equal(t, tc.wantOK, ok)
+			if !tc.wantOK {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				return
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestWritePaginationHeaders(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	tests := []struct {
+		name     string
+		page     Pagination
+		total    int64
+		wantLink string
+	}{
+		{
+			name:  "first page",
+			page:  Pagination{Limit: 10},
+			total: 25,
+			wantLink: `</projects?limit=10&name=foo&offset=0>; rel="first", ` +
+				`</projects?limit=10&name=foo&offset=10>; rel="next", ` +
+				`</projects?limit=10&name=foo&offset=20>; rel="last"`,
+		},
+		{
+			name:  "middle page",
+			page:  Pagination{Limit: 10, Offset: 15},
+			total: 25,
+			wantLink: `</projects?limit=10&name=foo&offset=0>; rel="first", ` +
+				`</projects?limit=10&name=foo&offset=5>; rel="prev", ` +
+				`</projects?limit=10&name=foo&offset=20>; rel="last"`,
+		},
+		{
+			name:  "empty list",
+			page:  Pagination{Limit: 10},
+			total: 0,
+			wantLink: `</projects?limit=10&name=foo&offset=0>; rel="first", ` +
+				`</projects?limit=10&name=foo&offset=0>; rel="last"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/projects?name=foo&page=2", nil)
+			WritePaginationHeaders(c, tc.page, tc.total)
+			assert.Equal(t, tc.wantLink, w.Header().Get("Link"))
+			assert.NotEmpty(t, w.Header().Get(HeaderTotalCount))
+		})
+	}
+}
+
+func TestPagination_Page(t *testing.T) {
+	assert.Equal(t, 1, Pagination{Limit: 10}.Page())
+	assert.Equal(t, 3, Pagination{Limit: 10, Offset: 20}.Page())
+	assert.Equal(t, 1, Pagination{}.Page())
+}
@@ -5,6 +5,8 @@ import (
 	"math/bits"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
@@ -79,31 +81,242 @@ func ParseQueryInt(c *gin.Context, queryName string) (int, bool) {
 }
 
 func parseUint(c *gin.Context, paramName, paramValue string) (uint, bool) {
-	value, err := strconv.ParseUint(paramValue, 10, bits.UintSize)
+	value, ok := parseUintSize(c, paramName, paramValue, bits.UintSize)
+	return uint(value), ok
+}
+
+func parseUintSize(c *gin.Context, paramName, paramValue string, bitSize int) (uint64, bool) {
+	value, err := strconv.ParseUint(paramValue, 10, bitSize)
 	if err != nil {
-		WriteProblemError(c, err, problem.Response{
-			Type:     "/prob/api/invalid-param-uint",
-			Title:    "Invalid positive integer value.",
-			Status:   http.StatusBadRequest,
-			Detail:   fmt.Sprintf("Failed to interpret parameter %q with value %q as an unsigned (positive) integer.", paramName, paramValue),
-			Instance: fmt.Sprintf("%s#%s", c.Request.RequestURI, paramName),
-		})
+		writeParseError(c, err, "/prob/api/invalid-param-uint", "Invalid positive integer value.", paramName,
+			fmt.Sprintf("Failed to interpret parameter %q with value %q as an unsigned (positive) integer.", paramName, paramValue))
 		return 0, false
 	}
-	return uint(value), true
+	return value, true
 }
 
 func parseInt(c *gin.Context, paramName, paramValue string) (int, bool) {
-	value, err := strconv.ParseInt(paramValue, 10, bits.UintSize)
+	value, ok := parseIntSize(c, paramName, paramValue, bits.UintSize)
+	return int(value), ok
+}
+
+func parseIntSize(c *gin.Context, paramName, paramValue string, bitSize int) (int64, bool) {
+	value, err := strconv.ParseInt(paramValue, 10, bitSize)
 	if err != nil {
-		WriteProblemError(c, err, problem.Response{
-			Type:     "/prob/api/invalid-param-int",
-			Title:    "Invalid integer value.",
-			Status:   http.StatusBadRequest,
-			Detail:   fmt.Sprintf("Failed to interpret parameter %q with value %q as a signed (positive or negative) integer.", paramName, paramValue),
-			Instance: fmt.Sprintf("%s#%s", c.Request.RequestURI, paramValue),
-		})
+		writeParseError(c, err, "/prob/api/invalid-param-int", "Invalid integer value.", paramName,
+			fmt.Sprintf("Failed to interpret parameter %q with value %q as a signed (positive or negative) integer.", paramName, paramValue))
 		return 0, false
 	}
-	return int(value), true
+	return value, true
+}
+
+// ParseQueryBool tries to read the named query parameter from the request and
+// parse it to a bool, using strconv.ParseBool.
+//
+// If it fails, it will write out a problem response using
+// WriteProblemError with the status code 400 (Bad Request).
+func ParseQueryBool(c *gin.Context, queryName string) (bool, bool) {
+	return parseBool(c, queryName, c.Query(queryName))
+}
+
+// ParseQueryFloat64 tries to read the named query parameter from the request
+// and parse it to a float64.
+//
+// If it fails, it will write out a problem response using
+// WriteProblemError with the status code 400 (Bad Request).
+func ParseQueryFloat64(c *gin.Context, queryName string) (float64, bool) {
+	return parseFloat64(c, queryName, c.Query(queryName))
+}
+
+// ParseQueryTime tries to read the named query parameter from the request and
+// parse it to a time.Time, using the RFC3339 format.
+//
+// If it fails, it will write out a problem response using
+// WriteProblemError with the status code 400 (Bad Request).
+func ParseQueryTime(c *gin.Context, queryName string) (time.Time, bool) {
+	return parseTime(c, queryName, c.Query(queryName))
+}
+
+// ParseParamUUID tries to read the named path parameter from the request and
+// validate it as a UUID, such as "123e4567-e89b-12d3-a456-426614174000". The
+// UUID is returned in lowercase.
+//
+// If it fails, it will write out a problem response using
+// WriteProblemError with the status code 400 (Bad Request).
+func ParseParamUUID(c *gin.Context, paramName string) (string, bool) {
+	return parseUUID(c, paramName, c.Param(paramName))
+}
+
+// ParseQueryUUID tries to read the named query parameter from the request and
+// validate it as a UUID, such as "123e4567-e89b-12d3-a456-426614174000". The
+// UUID is returned in lowercase.
+//
+// If it fails, it will write out a problem response using
+// WriteProblemError with the status code 400 (Bad Request).
+func ParseQueryUUID(c *gin.Context, queryName string) (string, bool) {
+	return parseUUID(c, queryName, c.Query(queryName))
+}
+
+// ParseQueryStringSlice reads all values of the named query parameter from
+// the request, supporting both repeated query keys and comma-separated
+// values, such as "?status=failed&status=running" and
+// "?status=failed,running". Whitespace around each value is trimmed, and
+// empty values are skipped.
+//
+// Returns nil if the query parameter is omitted.
+func ParseQueryStringSlice(c *gin.Context, queryName string) []string {
+	var values []string
+	for _, query := range c.QueryArray(queryName) {
+		for _, value := range strings.Split(query, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// ParseQueryUintSlice reads all values of the named query parameter from the
+// request, the same way as ParseQueryStringSlice, and parses each value to an
+// uint.
+//
+// If it fails, it will write out a problem response using
+// WriteProblemError with the status code 400 (Bad Request).
+func ParseQueryUintSlice(c *gin.Context, queryName string) ([]uint, bool) {
+	return ParseQuerySlice[uint](c, queryName)
+}
+
+// ParseQueryIntSlice reads all values of the named query parameter from the
+// request, the same way as ParseQueryStringSlice, and parses each value to an
+// int.
+//
+// If it fails, it will write out a problem response using
+// WriteProblemError with the status code 400 (Bad Request).
+func ParseQueryIntSlice(c *gin.Context, queryName string) ([]int, bool) {
+	return ParseQuerySlice[int](c, queryName)
+}
+
+// ParseQueryStringDefault reads the named query parameter from the request,
+// or returns the default value if it is omitted or empty.
+func ParseQueryStringDefault(c *gin.Context, queryName, defaultValue string) string {
+	if value := c.Query(queryName); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// ParseQueryIntDefault tries to read the named query parameter from the
+// request and parse it to an int, or returns the default value if it is
+// omitted or empty.
+//
+// If it is set but fails to parse, it will write out a problem response using
+// WriteProblemError with the status code 400 (Bad Request).
+func ParseQueryIntDefault(c *gin.Context, queryName string, defaultValue int) (int, bool) {
+	return ParseQueryDefault(c, queryName, defaultValue)
+}
+
+// ParseQueryUintDefault tries to read the named query parameter from the
+// request and parse it to an uint, or returns the default value if it is
+// omitted or empty.
+//
+// If it is set but fails to parse, it will write out a problem response using
+// WriteProblemError with the status code 400 (Bad Request).
+func ParseQueryUintDefault(c *gin.Context, queryName string, defaultValue uint) (uint, bool) {
+	return ParseQueryDefault(c, queryName, defaultValue)
+}
+
+// ParseQueryBoolDefault tries to read the named query parameter from the
+// request and parse it to a bool, or returns the default value if it is
+// omitted or empty.
+//
+// If it is set but fails to parse, it will write out a problem response using
+// WriteProblemError with the status code 400 (Bad Request).
+func ParseQueryBoolDefault(c *gin.Context, queryName string, defaultValue bool) (bool, bool) {
+	return ParseQueryDefault(c, queryName, defaultValue)
+}
+
+func parseBool(c *gin.Context, paramName, paramValue string) (bool, bool) {
+	value, err := strconv.ParseBool(paramValue)
+	if err != nil {
+		writeParseError(c, err, "/prob/api/invalid-param-bool", "Invalid boolean value.", paramName,
+			fmt.Sprintf("Failed to interpret parameter %q with value %q as a boolean, such as true or false.", paramName, paramValue))
+		return false, false
+	}
+	return value, true
+}
+
+func parseFloat64(c *gin.Context, paramName, paramValue string) (float64, bool) {
+	return parseFloatSize(c, paramName, paramValue, 64)
+}
+
+func parseFloatSize(c *gin.Context, paramName, paramValue string, bitSize int) (float64, bool) {
+	value, err := strconv.ParseFloat(paramValue, bitSize)
+	if err != nil {
+		writeParseError(c, err, "/prob/api/invalid-param-float", "Invalid decimal number value.", paramName,
+			fmt.Sprintf("Failed to interpret parameter %q with value %q as a decimal number.", paramName, paramValue))
+		return 0, false
+	}
+	return value, true
+}
+
+func parseTime(c *gin.Context, paramName, paramValue string) (time.Time, bool) {
+	value, err := time.Parse(time.RFC3339, paramValue)
+	if err != nil {
+		writeParseError(c, err, "/prob/api/invalid-param-time", "Invalid timestamp value.", paramName,
+			fmt.Sprintf("Failed to interpret parameter %q with value %q as an RFC3339 timestamp, such as %q.", paramName, paramValue, time.RFC3339))
+		return time.Time{}, false
+	}
+	return value, true
+}
+
+func parseDuration(c *gin.Context, paramName, paramValue string) (time.Duration, bool) {
+	value, err := time.ParseDuration(paramValue)
+	if err != nil {
+		writeParseError(c, err, "/prob/api/invalid-param-duration", "Invalid duration value.", paramName,
+			fmt.Sprintf("Failed to interpret parameter %q with value %q as a duration, such as %q.", paramName, paramValue, "1h30m"))
+		return 0, false
+	}
+	return value, true
+}
+
+func parseUUID(c *gin.Context, paramName, paramValue string) (string, bool) {
+	if !isUUID(paramValue) {
+		writeParseError(c, fmt.Errorf("invalid UUID: %q", paramValue), "/prob/api/invalid-param-uuid", "Invalid UUID value.", paramName,
+			fmt.Sprintf("Failed to interpret parameter %q with value %q as a UUID, such as %q.", paramName, paramValue, "123e4567-e89b-12d3-a456-426614174000"))
+		return "", false
+	}
+	return strings.ToLower(paramValue), true
+}
+
+func writeParseError(c *gin.Context, err error, probType, title, paramName, detail string) {
+	WriteProblemError(c, err, problem.Response{
+		Type:     probType,
+		Title:    title,
+		Status:   http.StatusBadRequest,
+		Detail:   detail,
+		Instance: fmt.Sprintf("%s#%s", c.Request.RequestURI, paramName),
+	})
+}
+
+// isUUID reports whether the string is a UUID in its canonical textual
+// representation of 32 hexadecimal digits separated by hyphens into groups of
+// 8-4-4-4-12.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if ch != '-' {
+				return false
+			}
+		default:
+			if !('0' <= ch && ch <= '9' || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
 }
@@ -0,0 +1,149 @@
+package ginutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newParamsTestContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.ReleaseMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	return c, w
+}
+
+func TestParseQueryBool(t *testing.T) {
+	c, _ := newParamsTestContext("/?a=true&b=0")
+	got, ok := ParseQueryBool(c, "a")
+	assert.True(t, ok)
+	assert.True(t, got)
+	got, ok = ParseQueryBool(c, "b")
+	assert.True(t, ok)
+	assert.False(t, got)
+
+	c, w := newParamsTestContext("/?a=yes")
+	_, ok = ParseQueryBool(c, "a")
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestParseQueryFloat64(t *testing.T) {
+	c, _ := newParamsTestContext("/?a=1.5")
+	got, ok := ParseQueryFloat64(c, "a")
+	assert.True(t, ok)
+	assert.Equal(t, 1.5, got)
+
+	c, w := newParamsTestContext("/?a=one")
+	_, ok = ParseQueryFloat64(c, "a")
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestParseQueryTime(t *testing.T) {
+	c, _ := newParamsTestContext("/?since=2022-05-20T12:30:00Z")
+	got, ok := ParseQueryTime(c, "since")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2022, 5, 20, 12, 30, 0, 0, time.UTC), got)
+
+	c, w := newParamsTestContext("/?since=2022-05-20")
+	_, ok = ParseQueryTime(c, "since")
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestParseParamUUID(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   string
+		wantOK bool
+	}{
+		{name: "lowercase", value: "123e4567-e89b-12d3-a456-426614174000", want: "123e4567-e89b-12d3-a456-426614174000", wantOK: true},
+		{name: "uppercase", value: "123E4567-E89B-12D3-A456-426614174000", want: "123e4567-e89b-12d3-a456-426614174000", wantOK: true},
+		{name: "missing hyphens", value: "123e4567e89b12d3a456426614174000"},
+		{name: "invalid digit", value: "123e4567-e89b-12d3-a456-42661417400g"},
+		{name: "empty"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := newParamsTestContext("/")
+			c.Params = gin.Params{{Key: "id", Value: tc.value}}
+			got, ok := ParseParamUUID(c, "id")
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.want, got)
+			if !tc.wantOK {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
+
+func TestParseQueryStringSlice(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "omitted", query: "", want: nil},
+		{name: "single", query: "?status=failed", want: []string{"failed"}},
+		{name: "repeated", query: "?status=failed&status=running", want: []string{"failed", "running"}},
+		{name: "comma-separated", query: "?status=failed,%20running", want: []string{"failed", "running"}},
+		{name: "mixed with empty", query: "?status=failed,&status=,running", want: []string{"failed", "running"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _ := newParamsTestContext("/" + tc.query)
+			assert.Equal(t, tc.want, ParseQueryStringSlice(c, "status"))
+		})
+	}
+}
+
+func TestParseQueryUintSlice(t *testing.T) {
+	c, _ := newParamsTestContext("/?id=1,2&id=3")
+	got, ok := ParseQueryUintSlice(c, "id")
+	assert.True(t, ok)
+	assert.Equal(t, []uint{1, 2, 3}, got)
+
+	c, w := newParamsTestContext("/?id=1,-2")
+	got, ok = ParseQueryUintSlice(c, "id")
+	assert.False(t, ok)
+	assert.Nil(t, got)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestParseQueryIntDefault(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		want   int
+		wantOK bool
+	}{
+		{name: "omitted", query: "", want: 10, wantOK: true},
+		{name: "empty", query: "?n=", want: 10, wantOK: true},
+		{name: "set", query: "?n=3", want: 3, wantOK: true},
+		{name: "malformed", query: "?n=three", want: 0, wantOK: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := newParamsTestContext("/" + tc.query)
+			got, ok := ParseQueryIntDefault(c, "n", 10)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.want, got)
+			if !tc.wantOK {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
+
+func TestParseQueryStringDefault(t *testing.T) {
+	c, _ := newParamsTestContext("/?sort=name")
+	assert.Equal(t, "name", ParseQueryStringDefault(c, "sort", "id"))
+	assert.Equal(t, "asc", ParseQueryStringDefault(c, "order", "asc"))
+}
@@ -0,0 +1,137 @@
+package ginutil
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ParseConstraint is a generic type constraint of all the types that the
+// ParseParam and ParseQuery functions support. It mirrors the types supported
+// by env.BindConstraint.
+type ParseConstraint interface {
+	string | bool | int | int32 | int64 | uint | uint32 | uint64 |
+		float32 | float64 | time.Time | time.Duration
+}
+
+// ParseParam tries to read the named path parameter from the request and
+// parse it to the type T, using the same parsing and problem responses as the
+// non-generic functions such as ParseParamInt and RequireParamString.
+//
+// Time values use the RFC3339 format, and durations use the format of
+// time.ParseDuration.
+//
+// If it fails, it will write out a problem response using
+// WriteProblemError with the status code 400 (Bad Request).
+//
+// Example usage:
+//
+// 	buildID, ok := ginutil.ParseParam[uint64](c, "buildId")
+// 	if !ok {
+// 		return
+// 	}
+func ParseParam[T ParseConstraint](c *gin.Context, paramName string) (T, bool) {
+	return parseValue[T](c, paramName, c.Param(paramName))
+}
+
+// ParseQuery tries to read the named query parameter from the request and
+// parse it to the type T, using the same parsing and problem responses as the
+// non-generic functions such as ParseQueryInt and RequireQueryString.
+//
+// Time values use the RFC3339 format, and durations use the format of
+// time.ParseDuration.
+//
+// If it fails, it will write out a problem response using
+// WriteProblemError with the status code 400 (Bad Request).
+func ParseQuery[T ParseConstraint](c *gin.Context, queryName string) (T, bool) {
+	return parseValue[T](c, queryName, c.Query(queryName))
+}
+
+// ParseQueryDefault tries to read the named query parameter from the request
+// and parse it to the type T the same way as ParseQuery, or returns the
+// default value if it is omitted or empty.
+//
+// If it is set but fails to parse, it will write out a problem response using
+// WriteProblemError with the status code 400 (Bad Request).
+//
+// Example usage:
+//
+// 	includeLogs, ok := ginutil.ParseQueryDefault(c, "includeLogs", false)
+// 	if !ok {
+// 		return
+// 	}
+func ParseQueryDefault[T ParseConstraint](c *gin.Context, queryName string, defaultValue T) (T, bool) {
+	str := c.Query(queryName)
+	if str == "" {
+		return defaultValue, true
+	}
+	return parseValue[T](c, queryName, str)
+}
+
+// ParseQuerySlice reads all values of the named query parameter from the
+// request, the same way as ParseQueryStringSlice, and parses each value to the
+// type T the same way as ParseQuery.
+//
+// If it fails, it will write out a problem response using
+// WriteProblemError with the status code 400 (Bad Request).
+//
+// Example usage:
+//
+// 	// GET /builds?projectId=1,2&projectId=3
+// 	projectIDs, ok := ginutil.ParseQuerySlice[uint](c, "projectId")
+// 	if !ok {
+// 		return
+// 	}
+func ParseQuerySlice[T ParseConstraint](c *gin.Context, queryName string) ([]T, bool) {
+	strs := ParseQueryStringSlice(c, queryName)
+	if strs == nil {
+		return nil, true
+	}
+	values := make([]T, len(strs))
+	for i, str := range strs {
+		value, ok := parseValue[T](c, queryName, str)
+		if !ok {
+			return nil, false
+		}
+		values[i] = value
+	}
+	return values, true
+}
+
+func parseValue[T ParseConstraint](c *gin.Context, paramName, paramValue string) (T, bool) {
+	var value T
+	var ok bool
+	switch ptr := any(&value).(type) {
+	case *string:
+		*ptr, ok = requireString(c, paramName, paramValue)
+	case *bool:
+		*ptr, ok = parseBool(c, paramName, paramValue)
+	case *int:
+		*ptr, ok = parseInt(c, paramName, paramValue)
+	case *int32:
+		var v int64
+		v, ok = parseIntSize(c, paramName, paramValue, 32)
+		*ptr = int32(v)
+	case *int64:
+		*ptr, ok = parseIntSize(c, paramName, paramValue, 64)
+	case *uint:
+		*ptr, ok = parseUint(c, paramName, paramValue)
+	case *uint32:
+		var v uint64
+		v, ok = parseUintSize(c, paramName, paramValue, 32)
+		*ptr = uint32(v)
+	case *uint64:
+		*ptr, ok = parseUintSize(c, paramName, paramValue, 64)
+	case *float32:
+		var v float64
+		v, ok = parseFloatSize(c, paramName, paramValue, 32)
+		*ptr = float32(v)
+	case *float64:
+		*ptr, ok = parseFloat64(c, paramName, paramValue)
+	case *time.Time:
+		*ptr, ok = parseTime(c, paramName, paramValue)
+	case *time.Duration:
+		*ptr, ok = parseDuration(c, paramName, paramValue)
+	}
+	return value, ok
+}
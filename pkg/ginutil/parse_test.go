@@ -0,0 +1,98 @@
+package ginutil
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	c, _ := newParamsTestContext("/?s=foo&b=true&i=-5&i32=32&i64=64&u=5&u32=32&u64=64&f32=1.5&f64=2.5&t=2022-05-20T12:30:00Z&d=1h30m")
+	assertParsed := func(t *testing.T, want, got any, ok bool) {
+		t.Helper()
+		assert.True(t, ok)
+		assert.Equal(t, want, got)
+	}
+	s, ok := ParseQuery[string](c, "s")
+	assertParsed(t, "foo", s, ok)
+	b, ok := ParseQuery[bool](c, "b")
+	assertParsed(t, true, b, ok)
+	i, ok := ParseQuery[int](c, "i")
+	assertParsed(t, -5, i, ok)
+	i32, ok := ParseQuery[int32](c, "i32")
+	assertParsed(t, int32(32), i32, ok)
+	i64, ok := ParseQuery[int64](c, "i64")
+	assertParsed(t, int64(64), i64, ok)
+	u, ok := ParseQuery[uint](c, "u")
+	assertParsed(t, uint(5), u, ok)
+	u32, ok := ParseQuery[uint32](c, "u32")
+	assertParsed(t, uint32(32), u32, ok)
+	u64, ok := ParseQuery[uint64](c, "u64")
+	assertParsed(t, uint64(64), u64, ok)
+	f32, ok := ParseQuery[float32](c, "f32")
+	assertParsed(t, float32(1.5), f32, ok)
+	f64, ok := ParseQuery[float64](c, "f64")
+	assertParsed(t, 2.5, f64, ok)
+	tm, ok := ParseQuery[time.Time](c, "t")
+	assertParsed(t, time.Date(2022, 5, 20, 12, 30, 0, 0, time.UTC), tm, ok)
+	d, ok := ParseQuery[time.Duration](c, "d")
+	assertParsed(t, 90*time.Minute, d, ok)
+}
+
+func TestParseParam_invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		parse    func(c *gin.Context) bool
+		wantType string
+	}{
+		{
+			name:  "int32 overflow",
+			value: "2147483648",
+			parse: func(c *gin.Context) bool {
+				_, ok := ParseParam[int32](c, "id")
+				return ok
+			},
+			wantType: "/prob/api/invalid-param-int",
+		},
+		{
+			name:  "negative uint",
+			value: "-1",
+			parse: func(c *gin.Context) bool {
+				_, ok := ParseParam[uint64](c, "id")
+				return ok
+			},
+			wantType: "/prob/api/invalid-param-uint",
+		},
+		{
+			name:  "empty string",
+			value: "",
+			parse: func(c *gin.Context) bool {
+				_, ok := ParseParam[string](c, "id")
+				return ok
+			},
+			wantType: "/prob/api/missing-param-string",
+		},
+		{
+			name:  "invalid duration",
+			value: "soon",
+			parse: func(c *gin.Context) bool {
+				_, ok := ParseParam[time.Duration](c, "id")
+				return ok
+			},
+			wantType: "/prob/api/invalid-param-duration",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := newParamsTestContext("/")
+			c.Params = gin.Params{{Key: "id", Value: tc.value}}
+			assert.False(t, tc.parse(c))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tc.wantType)
+		})
+	}
+}
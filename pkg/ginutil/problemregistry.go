@@ -0,0 +1,125 @@
+package ginutil
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
+)
+
+// ProblemRegistry maps errors to problem response templates, used by the
+// ProblemErrorHandler middleware to respond with the appropriate problem for
+// errors added via gin.Context.Error.
+//
+// The registry is not safe to modify concurrently with its usage, and should
+// be populated while initializing the application.
+type ProblemRegistry struct {
+	mappings []problemMapping
+}
+
+type problemMapping struct {
+	match func(err error) bool
+	prob  problem.Response
+}
+
+// NewProblemRegistry creates a new empty ProblemRegistry.
+func NewProblemRegistry() *ProblemRegistry {
+	return &ProblemRegistry{}
+}
+
+// RegisterError maps all errors that match the target error, as checked via
+// errors.Is, such as sentinel errors, to the problem response template.
+func (r *ProblemRegistry) RegisterError(target error, prob problem.Response) {
+	r.RegisterErrorFunc(func(err error) bool {
+		return errors.Is(err, target)
+	}, prob)
+}
+
+// RegisterErrorFunc maps all errors that the match function returns true for
+// to the problem response template.
+func (r *ProblemRegistry) RegisterErrorFunc(match func(err error) bool, prob problem.Response) {
+	r.mappings = append(r.mappings, problemMapping{match, prob})
+}
+
+// RegisterErrorType maps all errors of the type T, as checked via errors.As,
+// to the problem response template.
+//
+// Example usage:
+//
+// 	ginutil.RegisterErrorType[*strconv.NumError](registry, problem.Response{
+// 		Type:   "/prob/api/invalid-number",
+// 		Title:  "Invalid number.",
+// 		Status: http.StatusBadRequest,
+// 	})
+func RegisterErrorType[T error](r *ProblemRegistry, prob problem.Response) {
+	r.RegisterErrorFunc(func(err error) bool {
+		var target T
+		return errors.As(err, &target)
+	}, prob)
+}
+
+// Lookup returns the problem response template of the first registered
+// mapping that matches the error. The problem detail is set to the error
+// message if left unset in the template.
+func (r *ProblemRegistry) Lookup(err error) (problem.Response, bool) {
+	for _, m := range r.mappings {
+		if m.match(err) {
+			prob := m.prob
+			if prob.Detail == "" {
+				prob.Detail = err.Error()
+			}
+			return prob, true
+		}
+	}
+	return problem.Response{}, false
+}
+
+// ProblemErrorHandler creates a Gin middleware that, after the request has
+// been processed, writes a problem response using WriteProblem for the last
+// error added via gin.Context.Error, unless a response has already been
+// written.
+//
+// The problem response is looked up from the registry, and falls back to a
+// 500 "Internal Server Error" response with the type
+// "/prob/api/internal-server-error" for unmapped errors.
+//
+// This removes the need to check for and write every type of error in each
+// endpoint handler:
+//
+// 	registry := ginutil.NewProblemRegistry()
+// 	registry.RegisterError(gorm.ErrRecordNotFound, problem.Response{
+// 		Type:   "/prob/api/record-not-found",
+// 		Title:  "Record not found.",
+// 		Status: http.StatusNotFound,
+// 	})
+// 	r.Use(ginutil.ProblemErrorHandler(registry))
+//
+// 	r.GET("/projects/:projectId", func(c *gin.Context) {
+// 		project, err := getProject(c.Param("projectId"))
+// 		if err != nil {
+// 			c.Error(err)
+// 			return
+// 		}
+// 		c.JSON(http.StatusOK, project)
+// 	})
+func ProblemErrorHandler(registry *ProblemRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		last := c.Errors.Last()
+		if last == nil || c.Writer.Written() {
+			return
+		}
+		prob, ok := registry.Lookup(last.Err)
+		if !ok {
+			prob = problem.Response{
+				Type:   "/prob/api/internal-server-error",
+				Title:  "Internal server error.",
+				Status: http.StatusInternalServerError,
+				Detail: fmt.Sprintf("Unhandled error: %s", last.Err),
+			}
+		}
+		WriteProblem(c, prob)
+	}
+}
@@ -0,0 +1,95 @@
+package ginutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblemErrorHandler(t *testing.T) {
+	errNotFound := errors.New("not found")
+	registry := NewProblemRegistry()
+	registry.RegisterError(errNotFound, problem.Response{
+		Type:   "/prob/api/record-not-found",
+		Title:  "Record not found.",
+		Status: http.StatusNotFound,
+	})
+	RegisterErrorType[*strconv.NumError](registry, problem.Response{
+		Type:   "/prob/api/invalid-number",
+		Title:  "Invalid number.",
+		Status: http.StatusBadRequest,
+		Detail: "Expected a number.",
+	})
+
+	_, numErr := strconv.Atoi("abc")
+	tests := []struct {
+		name       string
+		err        error
+		written    bool
+		wantStatus int
+		wantType   string
+		wantDetail string
+	}{
+		{
+			name:       "sentinel",
+			err:        fmt.Errorf("get project: %w", errNotFound),
+			wantStatus: http.StatusNotFound,
+			wantType:   "https://wharf.iver.com/#/prob/api/record-not-found",
+			wantDetail: "get project: not found",
+		},
+		{
+			name:       "type",
+			err:        numErr,
+			wantStatus: http.StatusBadRequest,
+			wantType:   "https://wharf.iver.com/#/prob/api/invalid-number",
+			wantDetail: "Expected a number.",
+		},
+		{
+			name:       "fallback",
+			err:        errors.New("oh no"),
+			wantStatus: http.StatusInternalServerError,
+			wantType:   "https://wharf.iver.com/#/prob/api/internal-server-error",
+			wantDetail: "Unhandled error: oh no",
+		},
+		{
+			name:       "already written",
+			err:        errNotFound,
+			written:    true,
+			wantStatus: http.StatusAccepted,
+		},
+	}
+	gin.SetMode(gin.ReleaseMode)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(ProblemErrorHandler(registry))
+			r.GET("/", func(c *gin.Context) {
+				c.Error(tc.err)
+				if tc.written {
+					c.String(http.StatusAccepted, "ok")
+				}
+			})
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.Equal(t, tc.wantStatus, w.Code)
+			if tc.written {
+				assert.Equal(t, "ok", w.Body.String())
+				return
+			}
+			var prob problem.Response
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &prob))
+			assert.Equal(t, tc.wantType, prob.Type)
+			assert.Equal(t, tc.wantDetail, prob.Detail)
+			assert.Equal(t, []string{tc.err.Error()}, prob.Errors)
+		})
+	}
+}
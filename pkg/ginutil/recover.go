@@ -1,23 +1,132 @@
 package ginutil
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"strings"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
 	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
 )
 
-// RecoverProblem is a Gin middleware that uses RecoverProblemHandle.
-var RecoverProblem = gin.CustomRecovery(RecoverProblemHandle)
+// RecoverConfig holds configuration for the panic recover middleware.
+type RecoverConfig struct {
+	// Logger is the logger implementation used when logging the recovered
+	// panics, together with the request fields added by Logger. Defaults to
+	// the logger set via SetLogger, or else a logger with the scope "GIN".
+	Logger logger.Logger
+	// IncludeStack adds the stack trace of the panic to the problem detail
+	// when set to true. Should only be enabled while debugging, as it exposes
+	// the internals of the application to the clients.
+	IncludeStack bool
+	// MaxStackFrames is the maximum number of stack frames added to the
+	// problem detail when IncludeStack is enabled. Defaults to 10.
+	MaxStackFrames int
+}
+
+// RecoverProblem is a Gin middleware that uses RecoverWithConfig with the
+// default configuration.
+var RecoverProblem = RecoverWithConfig(RecoverConfig{})
+
+// RecoverWithConfig creates a Gin middleware handler function that recovers
+// from panics in the subsequent handlers, logs the panic with its stack trace
+// using the error logging level, and writes a HTTP "Internal Server Error"
+// problem response using RecoverProblemHandle.
+//
+// Panics caused by the client closing the connection, such as broken pipes,
+// are logged using the warning logging level, and no response is written.
+func RecoverWithConfig(config RecoverConfig) gin.HandlerFunc {
+	if config.MaxStackFrames <= 0 {
+		config.MaxStackFrames = 10
+	}
+	return func(c *gin.Context) {
+		defer func() {
+			err := recover()
+			if err == nil {
+				return
+			}
+			stack := panicStack(logger.CaptureStack(1))
+			log := Logger(c)
+			if config.Logger != nil {
+				log = newRequestLogger(c, config.Logger)
+			}
+			if isBrokenPipe(err) {
+				log.Warn().WithString("panic", fmt.Sprint(err)).
+					Message("Connection closed by client.")
+				c.Error(fmt.Errorf("%v", err))
+				c.Abort()
+				return
+			}
+			log.Error().WithString("panic", fmt.Sprint(err)).
+				WithStackFrames(stack).
+				Message("Recovered from panic.")
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			prob := recoverProblem(err)
+			if config.IncludeStack {
+				prob.Detail += "\n\n" + formatStack(stack, config.MaxStackFrames)
+			}
+			WriteProblem(c, prob)
+			c.Abort()
+		}()
+		c.Next()
+	}
+}
 
 // RecoverProblemHandle writes a HTTP "Internal Server Error" problem response.
 // Meant to be used with the gin-gonic panic recover middleware.
 func RecoverProblemHandle(c *gin.Context, err any) {
-	WriteProblem(c, problem.Response{
+	WriteProblem(c, recoverProblem(err))
+}
+
+func recoverProblem(err any) problem.Response {
+	return problem.Response{
 		Type:   "/prob/api/internal-server-error",
 		Title:  "Internal server error.",
 		Status: http.StatusInternalServerError,
 		Detail: fmt.Sprintf("Unhandled error: %s", err),
-	})
+	}
+}
+
+// panicStack trims away the frames of the Go runtime's panic handling from the
+// start of the stack trace, so that it starts at the panicking function.
+func panicStack(stack []logger.StackFrame) []logger.StackFrame {
+	for len(stack) > 0 && strings.HasPrefix(stack[0].Function, "runtime.") {
+		stack = stack[1:]
+	}
+	return stack
+}
+
+func formatStack(stack []logger.StackFrame, maxFrames int) string {
+	var sb strings.Builder
+	for i, frame := range stack {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		if i == maxFrames {
+			fmt.Fprintf(&sb, "... %d more frames", len(stack)-maxFrames)
+			break
+		}
+		sb.WriteString(frame.String())
+	}
+	return sb.String()
+}
+
+func isBrokenPipe(err any) bool {
+	opErr, ok := err.(*net.OpError)
+	if !ok {
+		return false
+	}
+	var syscallErr *os.SyscallError
+	if !errors.As(opErr, &syscallErr) {
+		return false
+	}
+	return errors.Is(syscallErr, syscall.EPIPE) || errors.Is(syscallErr, syscall.ECONNRESET)
 }
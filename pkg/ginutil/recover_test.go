@@ -0,0 +1,67 @@
+package ginutil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func panickingHandler(c *gin.Context) {
+	panic("oh no")
+}
+
+func TestRecoverWithConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    RecoverConfig
+		wantStack bool
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:      "include stack",
+			config:    RecoverConfig{IncludeStack: true, MaxStackFrames: 2},
+			wantStack: true,
+		},
+	}
+	gin.SetMode(gin.ReleaseMode)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := logger.NewMock()
+			tc.config.Logger = mock
+			r := gin.New()
+			r.Use(RecoverWithConfig(tc.config))
+			r.GET("/", panickingHandler)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, http.StatusInternalServerError, w.Code)
+			var prob problem.Response
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &prob))
+			assert.True(t, strings.HasPrefix(prob.Detail, "Unhandled error: oh no"), prob.Detail)
+			assert.Equal(t, tc.wantStack, strings.Contains(prob.Detail, "panickingHandler"), prob.Detail)
+			if tc.wantStack {
+				assert.Contains(t, prob.Detail, "more frames")
+			}
+
+			require.Len(t, mock.Logs, 1)
+			log := mock.Logs[0]
+			assert.Equal(t, logger.LevelError, log.Level)
+			assert.Equal(t, "oh no", log.Fields["panic"])
+			assert.Equal(t, "/", log.Fields["path"])
+			stack, ok := log.Fields["stack"].([]logger.StackFrame)
+			require.True(t, ok, "stack field")
+			require.NotEmpty(t, stack)
+			assert.True(t, strings.HasSuffix(stack[0].Function, "ginutil.panickingHandler"), stack[0].Function)
+		})
+	}
+}
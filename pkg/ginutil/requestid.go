@@ -0,0 +1,113 @@
+package ginutil
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/iver-wharf/wharf-core/v2/pkg/requestid"
+)
+
+// HeaderRequestID is the HTTP header used by RequestIDWithConfig by default.
+const HeaderRequestID = "X-Request-Id"
+
+// maxRequestIDLength is the maximum length of a request ID received via the
+// HTTP header for it to be honored.
+const maxRequestIDLength = 128
+
+const contextKeyRequestID = "wharf-core/ginutil/request-id"
+
+// RequestIDConfig holds configuration for the request ID middleware.
+type RequestIDConfig struct {
+	// Header is the HTTP header that the request ID is read from and written
+	// to. Defaults to HeaderRequestID.
+	Header string
+	// Generator is used to create new request IDs for requests that do not
+	// already have one. Defaults to 16 random bytes in hexadecimal encoding.
+	Generator func() string
+}
+
+// DefaultRequestIDHandler is a Gin middleware that assigns a request ID to
+// each request using the default configuration.
+var DefaultRequestIDHandler = RequestIDWithConfig(RequestIDConfig{})
+
+// RequestIDWithConfig creates a Gin middleware handler function that assigns a
+// request ID to each request, so that a single request can be traced across
+// all logs.
+//
+// The request ID is taken from the request header if the client or a reverse
+// proxy has already set it, as long as it is at most 128 characters of
+// printable ASCII, or else a new request ID is generated.
+//
+// The request ID is written to the response header, and is stored in both the
+// gin.Context and the request's context.Context, where it can be retrieved
+// using GetRequestID and RequestIDFromContext. It is also added to the logs of
+// LoggerWithConfig and to the problem responses of WriteProblem, and can be
+// added to any other logs by registering RequestIDFieldsProvider via
+// logger.RegisterFieldsProvider.
+func RequestIDWithConfig(config RequestIDConfig) gin.HandlerFunc {
+	if config.Header == "" {
+		config.Header = HeaderRequestID
+	}
+	if config.Generator == nil {
+		config.Generator = newRequestID
+	}
+	return func(c *gin.Context) {
+		id := c.GetHeader(config.Header)
+		if !isValidRequestID(id) {
+			id = config.Generator()
+		}
+		c.Set(contextKeyRequestID, id)
+		c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+		c.Header(config.Header, id)
+		c.Next()
+	}
+}
+
+// GetRequestID returns the request ID set by the RequestIDWithConfig
+// middleware, or an empty string if none has been set.
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(contextKeyRequestID)
+}
+
+// RequestIDFromContext returns the request ID set by the RequestIDWithConfig
+// middleware on the request's context.Context, or an empty string if none has
+// been set. It is the same as requestid.FromContext.
+func RequestIDFromContext(ctx context.Context) string {
+	return requestid.FromContext(ctx)
+}
+
+// RequestIDFieldsProvider is a logger.FieldsProvider that adds the request ID
+// from the context.Context, if any, as the "requestId" field. It is the same
+// as requestid.FieldsProvider.
+//
+// Meant to be registered using logger.RegisterFieldsProvider, so that the
+// request ID is added to all log events that use
+// logger.Event.WithProvidedFields:
+//
+// 	logger.RegisterFieldsProvider(ginutil.RequestIDFieldsProvider)
+func RequestIDFieldsProvider(ctx context.Context) []logger.Field {
+	return requestid.FieldsProvider(ctx)
+}
+
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b[:])
+}
+
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
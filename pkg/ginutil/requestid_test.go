@@ -0,0 +1,105 @@
+package ginutil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
+	"github.com/iver-wharf/wharf-core/v2/pkg/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDWithConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{
+			name:   "generated",
+			header: "",
+			want:   "generated-id",
+		},
+		{
+			name:   "honored",
+			header: "abc-123",
+			want:   "abc-123",
+		},
+		{
+			name:   "too long",
+			header: strings.Repeat("a", maxRequestIDLength+1),
+			want:   "generated-id",
+		},
+		{
+			name:   "non-printable",
+			header: "abc\x1b[31m",
+			want:   "generated-id",
+		},
+	}
+	gin.SetMode(gin.ReleaseMode)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(RequestIDWithConfig(RequestIDConfig{
+				Generator: func() string { return "generated-id" },
+			}))
+			var fromGin, fromCtx string
+			r.GET("/", func(c *gin.Context) {
+				fromGin = GetRequestID(c)
+				fromCtx = RequestIDFromContext(c.Request.Context())
+			})
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				req.Header.Set(HeaderRequestID, tc.header)
+			}
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tc.want, fromGin, "GetRequestID")
+			assert.Equal(t, tc.want, fromCtx, "RequestIDFromContext")
+			assert.Equal(t, tc.want, w.Header().Get(HeaderRequestID), "response header")
+		})
+	}
+}
+
+func TestRequestIDWithConfig_propagation(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	mock := logger.NewMock()
+	r := gin.New()
+	r.Use(DefaultRequestIDHandler)
+	r.Use(LoggerWithConfig(LoggerConfig{Logger: mock}))
+	r.GET("/", func(c *gin.Context) {
+		WriteProblem(c, problem.Response{})
+	})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderRequestID, "abc-123")
+	r.ServeHTTP(w, req)
+
+	require.Len(t, mock.Logs, 1)
+	assert.Equal(t, "abc-123", mock.Logs[0].Fields["requestId"])
+
+	var prob problem.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &prob))
+	assert.Equal(t, "abc-123", prob.RequestID)
+}
+
+func TestRequestIDFieldsProvider(t *testing.T) {
+	ctx := httptest.NewRequest(http.MethodGet, "/", nil).Context()
+	assert.Empty(t, RequestIDFieldsProvider(ctx))
+
+	ctx = requestid.NewContext(ctx, "abc-123")
+	want := []logger.Field{{Key: "requestId", Value: "abc-123"}}
+	assert.Equal(t, want, RequestIDFieldsProvider(ctx))
+}
+
+func TestNewRequestID(t *testing.T) {
+	a, b := newRequestID(), newRequestID()
+	assert.Len(t, a, 32)
+	assert.NotEqual(t, a, b)
+}
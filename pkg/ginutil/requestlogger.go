@@ -0,0 +1,69 @@
+package ginutil
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+)
+
+const contextKeyLogger = "wharf-core/ginutil/logger"
+
+var defaultRequestLogger = logger.NewScoped("GIN")
+
+// SetLogger sets the logger that Logger uses as its base for the rest of the
+// request. Meant to be called by middleware, such as to use a logger with a
+// different scope for a route group.
+func SetLogger(c *gin.Context, log logger.Logger) {
+	c.Set(contextKeyLogger, log)
+}
+
+// Logger returns a logger that adds the request fields to each logged event,
+// so that the handlers do not have to add them on each log call. The fields
+// are the request ID, as set by the RequestIDWithConfig middleware, the HTTP
+// method, the path, and the client IP address.
+//
+// The logger set via SetLogger is used as the base logger, or else a logger
+// with the scope "GIN". The request fields are read when calling this
+// function, so the returned logger can safely be used after the handler has
+// returned, such as from a goroutine started by the handler.
+//
+// Example usage:
+//
+// 	func getProjectHandler(c *gin.Context) {
+// 		log := ginutil.Logger(c)
+// 		log.Debug().WithString("projectId", c.Param("projectId")).
+// 			Message("Fetching project.")
+// 	}
+func Logger(c *gin.Context) logger.Logger {
+	base := defaultRequestLogger
+	if value, ok := c.Get(contextKeyLogger); ok {
+		if log, ok := value.(logger.Logger); ok {
+			base = log
+		}
+	}
+	return newRequestLogger(c, base)
+}
+
+func newRequestLogger(c *gin.Context, base logger.Logger) logger.Logger {
+	fields := make([]logger.Field, 0, 4)
+	if id := GetRequestID(c); id != "" {
+		fields = append(fields, logger.Field{Key: httpKey("requestId"), Value: id})
+	}
+	if c.Request != nil {
+		fields = append(fields,
+			logger.Field{Key: httpKey("method"), Value: c.Request.Method},
+			logger.Field{Key: httpKey("path"), Value: c.Request.URL.Path},
+			logger.Field{Key: httpKey("clientIp"), Value: c.ClientIP()})
+	}
+	return requestLogger{base, fields}
+}
+
+type requestLogger struct {
+	log    logger.Logger
+	fields []logger.Field
+}
+
+func (l requestLogger) Debug() logger.Event { return l.log.Debug().WithFields(l.fields...) }
+func (l requestLogger) Info() logger.Event  { return l.log.Info().WithFields(l.fields...) }
+func (l requestLogger) Warn() logger.Event  { return l.log.Warn().WithFields(l.fields...) }
+func (l requestLogger) Error() logger.Event { return l.log.Error().WithFields(l.fields...) }
+func (l requestLogger) Panic() logger.Event { return l.log.Panic().WithFields(l.fields...) }
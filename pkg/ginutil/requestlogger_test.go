@@ -0,0 +1,39 @@
+package ginutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	mock := logger.NewMock()
+	r := gin.New()
+	r.Use(DefaultRequestIDHandler)
+	r.Use(func(c *gin.Context) {
+		SetLogger(c, mock)
+	})
+	r.GET("/projects/:projectId", func(c *gin.Context) {
+		Logger(c).Info().WithString("projectId", c.Param("projectId")).
+			Message("Fetching project.")
+	})
+	req := httptest.NewRequest(http.MethodGet, "/projects/12", nil)
+	req.Header.Set(HeaderRequestID, "abc-123")
+	req.RemoteAddr = "10.0.0.1:1234"
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Len(t, mock.Logs, 1)
+	log := mock.Logs[0]
+	assert.Equal(t, "Fetching project.", log.Message)
+	assert.Equal(t, "abc-123", log.Fields["requestId"])
+	assert.Equal(t, http.MethodGet, log.Fields["method"])
+	assert.Equal(t, "/projects/12", log.Fields["path"])
+	assert.Equal(t, "10.0.0.1", log.Fields["clientIp"])
+	assert.Equal(t, "12", log.Fields["projectId"])
+}
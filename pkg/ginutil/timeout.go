@@ -0,0 +1,73 @@
+package ginutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
+)
+
+// TimeoutConfig holds configuration for the request timeout middleware.
+type TimeoutConfig struct {
+	// Timeout is the maximum duration of the subsequent handlers, after which
+	// the request context is canceled. Defaults to 30 seconds.
+	Timeout time.Duration
+	// Status is the HTTP status code of the problem response written when the
+	// timeout is exceeded. Defaults to 503 "Service Unavailable".
+	//
+	// When set to http.StatusGatewayTimeout: the problem response instead
+	// uses 504 "Gateway Timeout", which may be preferred by services that
+	// mostly proxy requests to other services.
+	Status int
+}
+
+// Timeout creates a Gin middleware handler function that uses
+// TimeoutWithConfig with the given timeout and the default status code.
+//
+// Example usage:
+//
+// 	r.GET("/reports", ginutil.Timeout(5*time.Second), getReportsHandler)
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return TimeoutWithConfig(TimeoutConfig{Timeout: timeout})
+}
+
+// TimeoutWithConfig creates a Gin middleware handler function that sets a
+// deadline on the request context, so that downstream calls using the
+// context, such as database queries via gorm.DB.WithContext, are canceled
+// once the timeout is exceeded.
+//
+// The subsequent handlers are still run on the same goroutine, as the
+// gin.Context is not safe for concurrent use, and must therefore respect the
+// cancellation of the request context for the timeout to have any effect.
+// If the deadline was exceeded and no response has been written, then a
+// problem response with the type "/prob/api/request-timeout" is written.
+//
+// Can be used both globally via gin.Engine.Use and per route.
+func TimeoutWithConfig(config TimeoutConfig) gin.HandlerFunc {
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	if config.Status == 0 {
+		config.Status = http.StatusServiceUnavailable
+	}
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), config.Timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) || c.Writer.Written() {
+			return
+		}
+		WriteProblemError(c, ctx.Err(), problem.Response{
+			Type:   "/prob/api/request-timeout",
+			Title:  "Request timeout.",
+			Status: config.Status,
+			Detail: fmt.Sprintf("The request did not complete within %s.", config.Timeout),
+		})
+		c.Abort()
+	}
+}
@@ -0,0 +1,75 @@
+package ginutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutWithConfig(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	waitForCancel := func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	}
+	tests := []struct {
+		name       string
+		config     TimeoutConfig
+		handler    gin.HandlerFunc
+		wantStatus int
+		wantType   string
+	}{
+		{
+			name:   "completes in time",
+			config: TimeoutConfig{Timeout: time.Second},
+			handler: func(c *gin.Context) {
+				c.Status(http.StatusNoContent)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "exceeded",
+			config:     TimeoutConfig{Timeout: time.Millisecond},
+			handler:    waitForCancel,
+			wantStatus: http.StatusServiceUnavailable,
+			wantType:   "https://wharf.iver.com/#/prob/api/request-timeout",
+		},
+		{
+			name:       "exceeded with custom status",
+			config:     TimeoutConfig{Timeout: time.Millisecond, Status: http.StatusGatewayTimeout},
+			handler:    waitForCancel,
+			wantStatus: http.StatusGatewayTimeout,
+			wantType:   "https://wharf.iver.com/#/prob/api/request-timeout",
+		},
+		{
+			name:   "exceeded after writing",
+			config: TimeoutConfig{Timeout: time.Millisecond},
+			handler: func(c *gin.Context) {
+				c.Status(http.StatusAccepted)
+				c.Writer.WriteHeaderNow()
+				<-c.Request.Context().Done()
+			},
+			wantStatus: http.StatusAccepted,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/", TimeoutWithConfig(tc.config), tc.handler)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			require.Equal(t, tc.wantStatus, w.Code)
+			if tc.wantType == "" {
+				return
+			}
+			prob, err := problem.ParseHTTPResponse(w.Result())
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantType, prob.Type)
+		})
+	}
+}
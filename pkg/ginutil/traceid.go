@@ -0,0 +1,62 @@
+package ginutil
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderTraceParent is the W3C Trace Context HTTP header that carries the
+// trace ID of distributed traces.
+const HeaderTraceParent = "traceparent"
+
+const contextKeyTraceID = "wharf-core/ginutil/trace-id"
+
+// SetTraceID sets the trace ID, such as from an OpenTelemetry span, returned
+// by GetTraceID. Meant to be called by tracing middleware.
+func SetTraceID(c *gin.Context, traceID string) {
+	c.Set(contextKeyTraceID, traceID)
+}
+
+// GetTraceID returns the trace ID set via SetTraceID, or else the trace ID
+// from the W3C Trace Context "traceparent" request header, or an empty string
+// if there is none.
+//
+// The trace ID is added to the problem responses of WriteProblem, and to the
+// logs of LoggerWithConfig.
+func GetTraceID(c *gin.Context) string {
+	if id := c.GetString(contextKeyTraceID); id != "" {
+		return id
+	}
+	if c.Request == nil {
+		return ""
+	}
+	return parseTraceParent(c.Request.Header.Get(HeaderTraceParent))
+}
+
+// parseTraceParent returns the trace ID from a W3C Trace Context
+// "traceparent" header value, formatted as
+// "{version}-{trace-id}-{parent-id}-{trace-flags}", or an empty string if the
+// value is invalid. See https://www.w3.org/TR/trace-context/#traceparent-header
+func parseTraceParent(value string) string {
+	parts := strings.Split(value, "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return ""
+	}
+	traceID := parts[1]
+	if len(traceID) != 32 || !isLowerHex(traceID) ||
+		traceID == "00000000000000000000000000000000" {
+		return ""
+	}
+	return traceID
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
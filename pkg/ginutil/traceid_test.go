@@ -0,0 +1,76 @@
+package ginutil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"valid", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"empty", "", ""},
+		{"invalid version", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", ""},
+		{"all zeroes", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", ""},
+		{"uppercase", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", ""},
+		{"too short", "00-4bf92f35-00f067aa0ba902b7-01", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, parseTraceParent(tc.value))
+		})
+	}
+}
+
+func TestWriteProblem_traceID(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceParent string
+		setTraceID  string
+		want        string
+	}{
+		{
+			name:        "from header",
+			traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			want:        "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:        "set overrides header",
+			traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			setTraceID:  "custom-trace",
+			want:        "custom-trace",
+		},
+		{
+			name: "none",
+			want: "",
+		},
+	}
+	gin.SetMode(gin.ReleaseMode)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.traceParent != "" {
+				c.Request.Header.Set(HeaderTraceParent, tc.traceParent)
+			}
+			if tc.setTraceID != "" {
+				SetTraceID(c, tc.setTraceID)
+			}
+			WriteProblem(c, problem.Response{})
+			var prob problem.Response
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &prob))
+			assert.Equal(t, tc.want, prob.TraceID)
+		})
+	}
+}
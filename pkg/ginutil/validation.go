@@ -0,0 +1,70 @@
+package ginutil
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
+)
+
+// WriteValidationError uses WriteProblemError to write a 400 "Bad Request"
+// response with the type "/prob/api/invalid-param", where each field that
+// failed validation is listed in both Problem.Errors and
+// Problem.InvalidParams.
+//
+// Meant to be used on errors from gin.Context.ShouldBind and its siblings,
+// which validate the bound structs using the "binding" struct tags. Errors
+// that are not validation errors, such as JSON syntax errors, are written
+// using WriteInvalidBindError instead.
+func WriteValidationError(c *gin.Context, err error) {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		WriteInvalidBindError(c, err, "Failed to parse the request.")
+		return
+	}
+	params := make([]problem.InvalidParam, 0, len(validationErrs))
+	messages := make([]string, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		param := newInvalidParam(fieldErr)
+		params = append(params, param)
+		messages = append(messages, fmt.Sprintf("%s: %s", param.Name, param.Reason))
+	}
+	c.Error(err)
+	WriteProblem(c, problem.Response{
+		Type:          "/prob/api/invalid-param",
+		Title:         "Invalid API parameter.",
+		Status:        http.StatusBadRequest,
+		Detail:        fmt.Sprintf("Validation failed for %d field(s).", len(params)),
+		Instance:      c.Request.RequestURI,
+		Errors:        messages,
+		InvalidParams: params,
+	})
+}
+
+func newInvalidParam(fieldErr validator.FieldError) problem.InvalidParam {
+	reason := fmt.Sprintf("Value failed the %q validation rule.", fieldErr.Tag())
+	if fieldErr.Param() != "" {
+		reason = fmt.Sprintf("Value failed the %q validation rule with parameter %q.",
+			fieldErr.Tag(), fieldErr.Param())
+	}
+	return problem.InvalidParam{
+		Name:   fieldErrorName(fieldErr),
+		Reason: reason,
+		Rule:   fieldErr.Tag(),
+		Value:  fieldErr.Value(),
+	}
+}
+
+// fieldErrorName returns the namespace of the field without the name of the
+// top-level struct, such as "Owner.Name" instead of "Project.Owner.Name".
+func fieldErrorName(fieldErr validator.FieldError) string {
+	ns := fieldErr.Namespace()
+	if i := strings.IndexByte(ns, '.'); i != -1 {
+		return ns[i+1:]
+	}
+	return ns
+}
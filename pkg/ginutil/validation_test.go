@@ -0,0 +1,69 @@
+package ginutil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteValidationError(t *testing.T) {
+	type owner struct {
+		Name string `json:"name" binding:"required"`
+	}
+	type project struct {
+		Name  string `json:"name" binding:"required"`
+		Stars int    `json:"stars" binding:"max=5"`
+		Owner owner  `json:"owner"`
+	}
+	tests := []struct {
+		name       string
+		body       string
+		wantParams []problem.InvalidParam
+		wantErrors []string
+	}{
+		{
+			name: "validation errors",
+			body: `{"stars": 10}`,
+			wantParams: []problem.InvalidParam{
+				{Name: "Name", Reason: `Value failed the "required" validation rule.`, Rule: "required", Value: ""},
+				{Name: "Stars", Reason: `Value failed the "max" validation rule with parameter "5".`, Rule: "max", Value: float64(10)},
+				{Name: "Owner.Name", Reason: `Value failed the "required" validation rule.`, Rule: "required", Value: ""},
+			},
+			wantErrors: []string{
+				`Name: Value failed the "required" validation rule.`,
+				`Stars: Value failed the "max" validation rule with parameter "5".`,
+				`Owner.Name: Value failed the "required" validation rule.`,
+			},
+		},
+		{
+			name:       "syntax error",
+			body:       `{`,
+			wantErrors: []string{"unexpected EOF"},
+		},
+	}
+	gin.SetMode(gin.ReleaseMode)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/projects", strings.NewReader(tc.body))
+			var p project
+			err := c.ShouldBindJSON(&p)
+			require.Error(t, err)
+			WriteValidationError(c, err)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var prob problem.Response
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &prob))
+			assert.Equal(t, tc.wantParams, prob.InvalidParams)
+			assert.Equal(t, tc.wantErrors, prob.Errors)
+		})
+	}
+}
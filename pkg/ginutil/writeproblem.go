@@ -4,13 +4,17 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
 )
 
 // WriteProblem writes the Problem as JSON into the output response body
-// together with appropriate Content-Type header.
+// together with appropriate Content-Type header. The Problem is instead
+// written as XML, using the "application/problem+xml" Content-Type, if the
+// request's Accept header prefers XML over JSON.
 //
 // Problem.Type is set to "about:blank" (as recommended by the IETF RFC-7808)
 // if left unset, or converts scheme-less URIs to start with
@@ -26,6 +30,15 @@ import (
 // Problem.Detail is unaltered.
 //
 // Problem.Errors is set to the errors set to gin.Context.Errors if left empty.
+//
+// Problem.RequestID is set to the request ID from the RequestIDWithConfig
+// middleware if left unset.
+//
+// Problem.TraceID is set to the trace ID from GetTraceID if left unset.
+//
+// Problem.Deprecation is set to the deprecation notice added via
+// WriteDeprecation or the Deprecated middleware if left unset. If set, the
+// deprecation HTTP headers are also written.
 func WriteProblem(c *gin.Context, prob problem.Response) {
 	if prob.Type == "" {
 		prob.Type = "about:blank"
@@ -44,10 +57,50 @@ func WriteProblem(c *gin.Context, prob problem.Response) {
 	if len(prob.Errors) == 0 && len(c.Errors) > 0 {
 		prob.Errors = c.Errors.Errors()
 	}
+	if prob.RequestID == "" {
+		prob.RequestID = GetRequestID(c)
+	}
+	if prob.TraceID == "" {
+		prob.TraceID = GetTraceID(c)
+	}
+	if prob.Deprecation == nil {
+		if d, ok := getDeprecation(c); ok {
+			prob.Deprecation = &d
+		}
+	} else {
+		prob.Deprecation.WriteHeaders(c.Writer.Header())
+	}
+	if prefersXML(c) {
+		c.Header("Content-Type", problem.HTTPContentTypeXML)
+		c.XML(prob.Status, prob)
+		return
+	}
 	c.Header("Content-Type", problem.HTTPContentType)
 	c.JSON(prob.Status, prob)
 }
 
+func prefersXML(c *gin.Context) bool {
+	if c.Request == nil {
+		return false
+	}
+	switch c.NegotiateFormat(problemFormats...) {
+	case problem.HTTPContentTypeXML, gin.MIMEXML, gin.MIMEXML2:
+		return true
+	default:
+		return false
+	}
+}
+
+// problemFormats is the content types that WriteProblem can respond with, in
+// order of preference when the client accepts any of them.
+var problemFormats = []string{
+	problem.HTTPContentType,
+	gin.MIMEJSON,
+	problem.HTTPContentTypeXML,
+	gin.MIMEXML,
+	gin.MIMEXML2,
+}
+
 // WriteProblemError is a shorthand for adding an error via gin.Context.Error
 // and writing the problem using WriteProblem.
 func WriteProblemError(c *gin.Context, err error, prob problem.Response) {
@@ -120,7 +173,70 @@ func WriteDBNotFound(c *gin.Context, detail string) {
 	WriteProblem(c, problem.Response{
 		Type:   "/prob/api/record-not-found",
 		Title:  "Record not found.",
-		Status: http.StatusBadGateway,
+		Status: http.StatusNotFound,
+		Detail: detail,
+	})
+}
+
+// WriteDBTimeoutError uses WriteProblemError to write a 504 "Gateway Timeout"
+// response with the type "/prob/api/db-timeout".
+//
+// Meant to be used when a database operation was canceled due to exceeding
+// its deadline, such as when errors.Is(err, context.DeadlineExceeded).
+func WriteDBTimeoutError(c *gin.Context, err error, detail string) {
+	WriteProblemError(c, err, problem.Response{
+		Type:   "/prob/api/db-timeout",
+		Title:  "Database operation timed out.",
+		Status: http.StatusGatewayTimeout,
+		Detail: detail,
+	})
+}
+
+// WriteConflictError uses WriteProblemError to write a 409 "Conflict"
+// response with the type "/prob/api/conflict".
+//
+// Meant to be used when the request conflicts with the current state of the
+// resource, such as when creating a record that already exists.
+func WriteConflictError(c *gin.Context, err error, detail string) {
+	WriteProblemError(c, err, problem.Response{
+		Type:   "/prob/api/conflict",
+		Title:  "Conflict.",
+		Status: http.StatusConflict,
+		Detail: detail,
+	})
+}
+
+// WritePreconditionFailed uses WriteProblem to write a 412
+// "Precondition Failed" response with the type
+// "/prob/api/precondition-failed".
+//
+// Meant to be used when a conditional request header, such as If-Match, did
+// not match the current state of the resource.
+func WritePreconditionFailed(c *gin.Context, detail string) {
+	WriteProblem(c, problem.Response{
+		Type:   "/prob/api/precondition-failed",
+		Title:  "Precondition failed.",
+		Status: http.StatusPreconditionFailed,
+		Detail: detail,
+	})
+}
+
+// WriteTooManyRequests uses WriteProblem to write a 429 "Too Many Requests"
+// response with the type "/prob/api/too-many-requests".
+//
+// The Retry-After header is set to the number of seconds to wait, rounded up,
+// if retryAfter is above zero.
+//
+// Meant to be used when the client has exceeded a rate limit.
+func WriteTooManyRequests(c *gin.Context, retryAfter time.Duration, detail string) {
+	if retryAfter > 0 {
+		seconds := int64((retryAfter + time.Second - 1) / time.Second)
+		c.Header("Retry-After", strconv.FormatInt(seconds, 10))
+	}
+	WriteProblem(c, problem.Response{
+		Type:   "/prob/api/too-many-requests",
+		Title:  "Too many requests.",
+		Status: http.StatusTooManyRequests,
 		Detail: detail,
 	})
 }
@@ -0,0 +1,131 @@
+package ginutil
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteProblem_contentNegotiation(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{"no accept", "", problem.HTTPContentType},
+		{"any", "*/*", problem.HTTPContentType},
+		{"json", "application/json", problem.HTTPContentType},
+		{"problem xml", "application/problem+xml", problem.HTTPContentTypeXML},
+		{"xml", "application/xml", problem.HTTPContentTypeXML},
+		{"prefers xml", "application/xml, application/json;q=0.5", problem.HTTPContentTypeXML},
+	}
+	gin.SetMode(gin.ReleaseMode)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.accept != "" {
+				c.Request.Header.Set("Accept", tc.accept)
+			}
+			WriteProblem(c, problem.Response{Title: "Oh no."})
+			assert.Equal(t, tc.want, w.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestWriteProblem_xml(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/projects", nil)
+	c.Request.Header.Set("Accept", problem.HTTPContentTypeXML)
+	sunset := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	WriteProblem(c, problem.Response{
+		Type:        "/prob/api/record-not-found",
+		Title:       "Record not found.",
+		Status:      http.StatusNotFound,
+		Errors:      []string{"not found"},
+		Deprecation: &problem.Deprecation{Sunset: sunset},
+	})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `<problem xmlns="urn:ietf:rfc:7807">`)
+	assert.Contains(t, body, `<errors><error>not found</error></errors>`)
+	assert.NotContains(t, body, `<date>`)
+
+	resp := w.Result()
+	require.True(t, problem.IsHTTPResponse(resp))
+	prob, err := problem.ParseHTTPResponse(resp)
+	require.NoError(t, err)
+	assert.Equal(t, "https://wharf.iver.com/#/prob/api/record-not-found", prob.Type)
+	assert.Equal(t, "Record not found.", prob.Title)
+	assert.Equal(t, http.StatusNotFound, prob.Status)
+	assert.Equal(t, "/projects", prob.Instance)
+	assert.Equal(t, []string{"not found"}, prob.Errors)
+	require.NotNil(t, prob.Deprecation)
+	assert.Equal(t, sunset, prob.Deprecation.Sunset)
+	assert.Equal(t, xml.Name{Space: "urn:ietf:rfc:7807", Local: "problem"}, prob.XMLName)
+}
+
+func TestWriteProblem_xmlInvalidParamValues(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/projects", nil)
+	c.Request.Header.Set("Accept", problem.HTTPContentTypeXML)
+	WriteProblem(c, problem.Response{
+		Title:  "Invalid request body.",
+		Status: http.StatusBadRequest,
+		InvalidParams: []problem.InvalidParam{
+			{Name: "Labels", Reason: "Too many labels.", Value: map[string]string{"foo": "bar"}},
+			{Name: "Tags", Reason: "Too many tags.", Value: []string{"a", "b"}},
+			{Name: "Owner", Reason: "Unknown owner.", Value: struct{ Name string }{"alice"}},
+			{Name: "Name", Reason: "Name is required.", Rule: "required"},
+		},
+	})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, c.Errors)
+	body := w.Body.String()
+	assert.Contains(t, body, `<param><name>Labels</name><reason>Too many labels.</reason><value>map[foo:bar]</value></param>`)
+	assert.Contains(t, body, `<param><name>Tags</name><reason>Too many tags.</reason><value>[a b]</value></param>`)
+	assert.Contains(t, body, `<param><name>Owner</name><reason>Unknown owner.</reason><value>{alice}</value></param>`)
+	assert.Contains(t, body, `<param><name>Name</name><reason>Name is required.</reason><rule>required</rule></param>`)
+
+	prob, err := problem.ParseHTTPResponse(w.Result())
+	require.NoError(t, err)
+	require.Len(t, prob.InvalidParams, 4)
+	assert.Equal(t, "Labels", prob.InvalidParams[0].Name)
+}
+
+func TestWriteTooManyRequests(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	tests := []struct {
+		name           string
+		retryAfter     time.Duration
+		wantRetryAfter string
+	}{
+		{"no retry after", 0, ""},
+		{"whole seconds", 30 * time.Second, "30"},
+		{"rounds up", 1500 * time.Millisecond, "2"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			WriteTooManyRequests(c, tc.retryAfter, "Slow down.")
+			assert.Equal(t, http.StatusTooManyRequests, w.Code)
+			assert.Equal(t, tc.wantRetryAfter, w.Header().Get("Retry-After"))
+		})
+	}
+}
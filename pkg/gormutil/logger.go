@@ -3,9 +3,13 @@ package gormutil
 import (
 	"context"
 	"errors"
+	"reflect"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/iver-wharf/wharf-core/v2/pkg/requestid"
 	"gorm.io/gorm"
 	gormlogger "gorm.io/gorm/logger"
 )
@@ -32,8 +36,123 @@ type LoggerConfig struct {
 	//
 	// Set to 0 to disable.
 	SlowThreshold time.Duration
+	// SlowStack adds the stack trace of the application code that issued the
+	// SQL operation to the slow SQL warnings when set to true, such as to see
+	// which repository method issued the slow query. The frames of GORM and
+	// this logger are trimmed away from the start of the stack trace.
+	SlowStack bool
+	// FieldsFromContext provides fields from the context.Context given by
+	// GORM, such as when using gorm.DB.WithContext, that are added to all
+	// logs. Defaults to requestid.FieldsProvider, so that the SQL logs carry
+	// the request ID of the HTTP request that triggered them, as set by the
+	// ginutil.RequestIDWithConfig middleware.
+	//
+	// To not add any context fields, set this to a function that returns nil.
+	FieldsFromContext logger.FieldsProvider
+	// ParameterizedQueries logs the SQL with placeholders instead of the
+	// interpolated values, so that secrets and personal information in the
+	// parameters never reach the logs. Requires the GORM dialector to be
+	// wrapped using WithParamsFilter.
+	ParameterizedQueries bool
+	// ParameterizedQueriesExceptTables is a list of table names whose SQL
+	// is still logged with interpolated values when ParameterizedQueries is
+	// enabled. SQL that references multiple tables is only logged with values
+	// if all of them are in this list.
+	ParameterizedQueriesExceptTables []string
+	// FieldKeys sets the keys of the fields added to the SQL logs, such as to
+	// match existing dashboards. Unset keys use their defaults.
+	FieldKeys LoggerFieldKeys
+	// Messages sets the messages of the SQL logs, such as to localize them.
+	// Unset messages use their defaults.
+	Messages LoggerMessages
+	// OnQuery is called after every SQL operation, regardless of the logging
+	// levels, such as to record query counts, error counts, and durations in
+	// a metrics collector. Note that the SQL is then interpolated for every
+	// operation, even when it is not logged.
+	OnQuery func(ctx context.Context, metric QueryMetric)
+	// StatementRules overrides the logging level of the slow SQL warnings and
+	// the debug SQL logs based on the SQL statement, where the first matching
+	// rule is used, such as to silence noisy health check queries. Failed SQL
+	// operations are not affected by these rules.
+	StatementRules []StatementRule
+}
+
+// StatementRule is used in LoggerConfig.StatementRules to log SQL statements
+// that start with the Prefix, ignoring case and leading whitespace, or that
+// match the Pattern, using its Level. Use logger.LevelSilence to not log the
+// matching statements at all.
+//
+// Example usage:
+//
+// 	gormutil.NewLogger(gormutil.LoggerConfig{
+// 		StatementRules: []gormutil.StatementRule{
+// 			{Prefix: "SELECT 1", Level: logger.LevelSilence},
+// 			{Pattern: regexp.MustCompile(`\bschema_migrations\b`), Level: logger.LevelSilence},
+// 		},
+// 	})
+type StatementRule struct {
+	Prefix  string
+	Pattern *regexp.Regexp
+	Level   logger.Level
+}
+
+func (rule StatementRule) match(sql string) bool {
+	if rule.Prefix != "" {
+		trimmed := strings.TrimLeft(sql, " \t\r\n")
+		if len(trimmed) >= len(rule.Prefix) && strings.EqualFold(trimmed[:len(rule.Prefix)], rule.Prefix) {
+			return true
+		}
+	}
+	return rule.Pattern != nil && rule.Pattern.MatchString(sql)
+}
+
+// LoggerFieldKeys holds the keys of the fields added to the SQL logs. The keys
+// are prefixed with the "db" namespace if enabled via
+// logger.SetFieldNamespacing.
+type LoggerFieldKeys struct {
+	// SQL is the key of the executed SQL. Defaults to "sql".
+	SQL string
+	// Rows is the key of the number of affected rows. Defaults to "rows".
+	Rows string
+	// Elapsed is the key of the duration of the SQL operation. Defaults to
+	// "elapsed".
+	Elapsed string
+	// Threshold is the key of LoggerConfig.SlowThreshold in slow SQL logs.
+	// Defaults to "threshold".
+	Threshold string
+	// Remaining is the key of the remaining duration until the deadline of
+	// the context, which is negative when the deadline has been exceeded, in
+	// logs of canceled SQL operations. Defaults to "remaining".
+	Remaining string
+}
+
+// LoggerMessages holds the messages of the SQL logs.
+type LoggerMessages struct {
+	// Error is the message of failed SQL operations. Defaults to
+	// "Error in SQL.".
+	Error string
+	// Slow is the message of SQL operations exceeding
+	// LoggerConfig.SlowThreshold. Defaults to "Slow SQL.".
+	Slow string
+	// Trace is the message of all other SQL operations, logged using the
+	// debug logging level. Defaults to an empty message.
+	Trace string
+	// Canceled is the message of SQL operations that failed due to their
+	// context being canceled. These are logged using the warning logging
+	// level, together with the remaining duration until the deadline of the
+	// context, if any, so that they stand out from other errors in SQL.
+	// Defaults to "SQL canceled.".
+	Canceled string
+	// DeadlineExceeded is the message of SQL operations that failed due to
+	// exceeding the deadline of their context. These are logged the same way
+	// as the Canceled message. Defaults to "SQL deadline exceeded.".
+	DeadlineExceeded string
 }
 
+// gormutilPackage is the import path of this package, used to trim its frames
+// from stack traces.
+var gormutilPackage = reflect.TypeOf(gormLog{}).PkgPath()
+
 type gormLog struct {
 	LoggerConfig
 	level gormlogger.LogLevel
@@ -50,6 +169,18 @@ func NewLogger(config LoggerConfig) gormlogger.Interface {
 	if config.Logger == nil {
 		config.Logger = logger.NewScoped("GORM")
 	}
+	if config.FieldsFromContext == nil {
+		config.FieldsFromContext = requestid.FieldsProvider
+	}
+	setDefault(&config.FieldKeys.SQL, "sql")
+	setDefault(&config.FieldKeys.Rows, "rows")
+	setDefault(&config.FieldKeys.Elapsed, "elapsed")
+	setDefault(&config.FieldKeys.Threshold, "threshold")
+	setDefault(&config.FieldKeys.Remaining, "remaining")
+	setDefault(&config.Messages.Error, "Error in SQL.")
+	setDefault(&config.Messages.Slow, "Slow SQL.")
+	setDefault(&config.Messages.Canceled, "SQL canceled.")
+	setDefault(&config.Messages.DeadlineExceeded, "SQL deadline exceeded.")
 	return gormLog{
 		LoggerConfig: config,
 		level:        gormlogger.Info,
@@ -61,61 +192,150 @@ func (log gormLog) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
 	return log
 }
 
-func (log gormLog) Info(_ context.Context, message string, args ...any) {
+func (log gormLog) Info(ctx context.Context, message string, args ...any) {
 	if log.level >= gormlogger.Info || !log.AlsoUseGORMLogLevel {
-		log.Logger.Info().Messagef(message, args...)
+		log.withContext(ctx, log.Logger.Info()).Messagef(message, args...)
 	}
 }
 
-func (log gormLog) Warn(_ context.Context, message string, args ...any) {
+func (log gormLog) Warn(ctx context.Context, message string, args ...any) {
 	if log.level >= gormlogger.Warn || !log.AlsoUseGORMLogLevel {
-		log.Logger.Warn().Messagef(message, args...)
+		log.withContext(ctx, log.Logger.Warn()).Messagef(message, args...)
 	}
 }
 
-func (log gormLog) Error(_ context.Context, message string, args ...any) {
+func (log gormLog) Error(ctx context.Context, message string, args ...any) {
 	if log.level >= gormlogger.Error || !log.AlsoUseGORMLogLevel {
-		log.Logger.Error().Messagef(message, args...)
+		log.withContext(ctx, log.Logger.Error()).Messagef(message, args...)
 	}
 }
 
-func (log gormLog) Trace(_ context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+func (log gormLog) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+	if log.OnQuery != nil {
+		sql, rowsAffected := fc()
+		fc = func() (string, int64) { return sql, rowsAffected }
+		log.OnQuery(ctx, QueryMetric{
+			Operation:    SQLOperation(sql),
+			Elapsed:      elapsed,
+			RowsAffected: rowsAffected,
+			Err:          err,
+			Slow:         log.SlowThreshold != 0 && elapsed > log.SlowThreshold,
+		})
+	}
 	if log.level <= gormlogger.Silent && log.AlsoUseGORMLogLevel {
 		return
 	}
-	elapsed := time.Since(begin)
 	switch {
+	case log.shouldLogError(err) && isContextError(err):
+		sql, rowsAffected := fc()
+		ev := log.withContext(ctx, log.Logger.Warn())
+		ev = log.withRowsAffected(ev, rowsAffected)
+		ev = ev.WithDuration(dbKey(log.FieldKeys.Elapsed), elapsed)
+		if ctx != nil {
+			if deadline, ok := ctx.Deadline(); ok {
+				ev = ev.WithDuration(dbKey(log.FieldKeys.Remaining), time.Until(deadline))
+			}
+		}
+		ev = ev.WithError(err).
+			WithString(dbKey(log.FieldKeys.SQL), sql)
+		if errors.Is(err, context.DeadlineExceeded) {
+			ev.Message(log.Messages.DeadlineExceeded)
+		} else {
+			ev.Message(log.Messages.Canceled)
+		}
 	case log.shouldLogError(err):
 		sql, rowsAffected := fc()
-		ev := log.Logger.Error()
-		ev = withRowsAffected(ev, rowsAffected)
-		ev.WithDuration("elapsed", elapsed).
+		ev := log.withContext(ctx, log.Logger.Error())
+		ev = log.withRowsAffected(ev, rowsAffected)
+		ev.WithDuration(dbKey(log.FieldKeys.Elapsed), elapsed).
 			WithError(err).
-			WithString("sql", sql).
-			Message("Error in SQL.")
+			WithString(dbKey(log.FieldKeys.SQL), sql).
+			Message(log.Messages.Error)
 	case log.shouldLogWarnSlow(elapsed):
 		sql, rowsAffected := fc()
-		ev := log.Logger.Warn()
-		ev = withRowsAffected(ev, rowsAffected)
-		ev.WithDuration("elapsed", elapsed).
-			WithDuration("threshold", log.SlowThreshold).
-			WithString("sql", sql).
-			Message("Slow SQL.")
+		level, ok := log.statementLevel(sql, logger.LevelWarn)
+		if !ok {
+			return
+		}
+		ev := log.withContext(ctx, logger.NewEventFromLogger(log.Logger, level))
+		ev = log.withRowsAffected(ev, rowsAffected)
+		ev = ev.WithDuration(dbKey(log.FieldKeys.Elapsed), elapsed).
+			WithDuration(dbKey(log.FieldKeys.Threshold), log.SlowThreshold).
+			WithString(dbKey(log.FieldKeys.SQL), sql)
+		if log.SlowStack {
+			ev = ev.WithStackFrames(applicationStack(logger.CaptureStack(1)))
+		}
+		ev.Message(log.Messages.Slow)
 	case log.shouldLogDebug():
 		sql, rowsAffected := fc()
-		ev := log.Logger.Debug()
-		ev = withRowsAffected(ev, rowsAffected)
-		ev.WithDuration("elapsed", elapsed).
-			WithString("sql", sql).
-			Message("")
+		level, ok := log.statementLevel(sql, logger.LevelDebug)
+		if !ok {
+			return
+		}
+		ev := log.withContext(ctx, logger.NewEventFromLogger(log.Logger, level))
+		ev = log.withRowsAffected(ev, rowsAffected)
+		ev.WithDuration(dbKey(log.FieldKeys.Elapsed), elapsed).
+			WithString(dbKey(log.FieldKeys.SQL), sql).
+			Message(log.Messages.Trace)
 	}
 }
 
-func withRowsAffected(ev logger.Event, rows int64) logger.Event {
+// statementLevel returns the logging level of the SQL statement based on the
+// StatementRules, or false if it should not be logged.
+func (log gormLog) statementLevel(sql string, defaultLevel logger.Level) (logger.Level, bool) {
+	level := defaultLevel
+	for _, rule := range log.StatementRules {
+		if rule.match(sql) {
+			level = rule.Level
+			break
+		}
+	}
+	return level, level < logger.LevelSilence
+}
+
+func (log gormLog) withContext(ctx context.Context, ev logger.Event) logger.Event {
+	if ctx == nil {
+		return ev
+	}
+	return ev.WithFields(log.FieldsFromContext(ctx)...)
+}
+
+func (log gormLog) withRowsAffected(ev logger.Event, rows int64) logger.Event {
 	if rows == -1 {
-		return ev.WithRune("rows", '-')
+		return ev.WithRune(dbKey(log.FieldKeys.Rows), '-')
 	}
-	return ev.WithInt64("rows", rows)
+	return ev.WithInt64(dbKey(log.FieldKeys.Rows), rows)
+}
+
+// applicationStack trims away the frames of GORM and this logger from the
+// start of the stack trace, so that it starts at the application code.
+func applicationStack(stack []logger.StackFrame) []logger.StackFrame {
+	for len(stack) > 0 && isGORMFrame(stack[0]) {
+		stack = stack[1:]
+	}
+	return stack
+}
+
+func isGORMFrame(frame logger.StackFrame) bool {
+	return strings.HasPrefix(frame.Function, "gorm.io/") ||
+		strings.HasPrefix(frame.Function, gormutilPackage+".gormLog.")
+}
+
+func setDefault(value *string, defaultValue string) {
+	if *value == "" {
+		*value = defaultValue
+	}
+}
+
+// dbKey prefixes the field name with the "db" namespace, if enabled via
+// logger.SetFieldNamespacing.
+func dbKey(key string) string {
+	return logger.NamespacedKey(logger.NamespaceDB, key)
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
 }
 
 func (log gormLog) shouldLogError(err error) bool {
@@ -4,9 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
 	"testing"
 	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/ginutil"
 	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -239,3 +244,162 @@ func logLevelStr(lvl gormlogger.LogLevel) string {
 		return fmt.Sprintf("LogLevel(%d)", int(lvl))
 	}
 }
+
+func TestLoggerTraceNamespacedFields(t *testing.T) {
+	logger.SetFieldNamespacing(true)
+	defer logger.SetFieldNamespacing(false)
+
+	logMock := logger.NewMock()
+	log := NewLogger(LoggerConfig{Logger: logMock})
+	log.Trace(context.Background(), time.Now(), func() (string, int64) {
+		return "SELECT 1", 1
+	}, nil)
+
+	require.NotEmpty(t, logMock.Logs)
+	assert.ElementsMatch(t, []string{"caller", "line", "db.rows", "db.elapsed", "db.sql"}, logMock.Logs[0].FieldsAdded)
+}
+
+func TestLoggerTraceFieldsFromContext(t *testing.T) {
+	type ctxKey struct{}
+	logMock := logger.NewMock()
+	log := NewLogger(LoggerConfig{
+		Logger: logMock,
+		FieldsFromContext: func(ctx context.Context) []logger.Field {
+			return []logger.Field{{Key: "tenant", Value: ctx.Value(ctxKey{})}}
+		},
+	})
+	ctx := context.WithValue(context.Background(), ctxKey{}, "acme")
+	log.Trace(ctx, time.Now(), func() (string, int64) {
+		return "SELECT 1", 1
+	}, nil)
+	log.Warn(ctx, "some message")
+
+	require.Len(t, logMock.Logs, 2)
+	assert.Equal(t, "acme", logMock.Logs[0].Fields["tenant"])
+	assert.Equal(t, "acme", logMock.Logs[1].Fields["tenant"])
+}
+
+func TestLoggerTraceRequestIDFromContext(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	logMock := logger.NewMock()
+	log := NewLogger(LoggerConfig{Logger: logMock})
+	r := gin.New()
+	r.Use(ginutil.DefaultRequestIDHandler)
+	r.GET("/", func(c *gin.Context) {
+		log.Trace(c.Request.Context(), time.Now(), func() (string, int64) {
+			return "SELECT 1", 1
+		}, nil)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(ginutil.HeaderRequestID, "abc123")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Len(t, logMock.Logs, 1)
+	assert.Equal(t, "abc123", logMock.Logs[0].Fields["requestId"])
+}
+
+func TestLoggerTraceCustomFieldKeysAndMessages(t *testing.T) {
+	logMock := logger.NewMock()
+	log := NewLogger(LoggerConfig{
+		Logger:        logMock,
+		SlowThreshold: time.Millisecond,
+		FieldKeys: LoggerFieldKeys{
+			SQL:       "query",
+			Elapsed:   "duration",
+			Threshold: "limit",
+		},
+		Messages: LoggerMessages{
+			Slow:  "Långsam SQL.",
+			Trace: "SQL.",
+		},
+	})
+	fc := func() (string, int64) {
+		return "SELECT 1", 1
+	}
+	log.Trace(context.Background(), time.Now().Add(-time.Second), fc, nil)
+	log.Trace(context.Background(), time.Now(), fc, nil)
+	log.Trace(context.Background(), time.Now(), fc, errors.New("oh no"))
+
+	require.Len(t, logMock.Logs, 3)
+	assert.Equal(t, "Långsam SQL.", logMock.Logs[0].Message)
+	assert.ElementsMatch(t, []string{"caller", "line", "rows", "duration", "limit", "query"}, logMock.Logs[0].FieldsAdded)
+	assert.Equal(t, "SQL.", logMock.Logs[1].Message)
+	assert.Equal(t, "Error in SQL.", logMock.Logs[2].Message)
+}
+
+func TestLoggerTraceSlowStack(t *testing.T) {
+	logMock := logger.NewMock()
+	db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger: NewLogger(LoggerConfig{
+			Logger:        logMock,
+			SlowThreshold: time.Nanosecond,
+			SlowStack:     true,
+		}),
+	})
+	require.NoError(t, err)
+
+	type User struct {
+		gorm.Model
+	}
+	db.Find(&User{}, 1)
+
+	require.Len(t, logMock.Logs, 1)
+	stack, ok := logMock.Logs[0].Fields["stack"].([]logger.StackFrame)
+	require.True(t, ok, "logged 'stack' field")
+	require.NotEmpty(t, stack)
+	assert.Equal(t, "github.com/iver-wharf/wharf-core/v2/pkg/gormutil.TestLoggerTraceSlowStack", stack[0].Function)
+}
+
+func TestLoggerTraceStatementRules(t *testing.T) {
+	logMock := logger.NewMock()
+	log := NewLogger(LoggerConfig{
+		Logger: logMock,
+		StatementRules: []StatementRule{
+			{Prefix: "select 1", Level: logger.LevelSilence},
+			{Pattern: regexp.MustCompile(`\bschema_migrations\b`), Level: logger.LevelInfo},
+		},
+	})
+	trace := func(sql string, err error) {
+		log.Trace(context.Background(), time.Now(), func() (string, int64) {
+			return sql, 1
+		}, err)
+	}
+	trace("  SELECT 1", nil)
+	trace("SELECT * FROM schema_migrations", nil)
+	trace("SELECT * FROM users", nil)
+	trace("SELECT 1", errors.New("oh no"))
+
+	require.Len(t, logMock.Logs, 3)
+	assert.Equal(t, logger.LevelInfo, logMock.Logs[0].Level)
+	assert.Equal(t, logger.LevelDebug, logMock.Logs[1].Level)
+	assert.Equal(t, logger.LevelError, logMock.Logs[2].Level)
+}
+
+func TestLoggerTraceContextErrors(t *testing.T) {
+	logMock := logger.NewMock()
+	log := NewLogger(LoggerConfig{Logger: logMock})
+	fc := func() (string, int64) {
+		return "SELECT pg_sleep(10)", -1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	log.Trace(ctx, time.Now(), fc, fmt.Errorf("query: %w", ctx.Err()))
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	log.Trace(ctx, time.Now(), fc, ctx.Err())
+
+	require.Len(t, logMock.Logs, 2)
+	assert.Equal(t, logger.LevelWarn, logMock.Logs[0].Level)
+	assert.Equal(t, "SQL deadline exceeded.", logMock.Logs[0].Message)
+	remaining, ok := logMock.Logs[0].Fields["remaining"].(time.Duration)
+	require.True(t, ok, "logged 'remaining' field")
+	assert.Negative(t, remaining)
+	assert.Equal(t, logger.LevelWarn, logMock.Logs[1].Level)
+	assert.Equal(t, "SQL canceled.", logMock.Logs[1].Message)
+	assert.NotContains(t, logMock.Logs[1].Fields, "remaining")
+}
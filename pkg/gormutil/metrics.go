@@ -0,0 +1,43 @@
+package gormutil
+
+import (
+	"strings"
+	"time"
+)
+
+// QueryMetric holds the measurements of a single SQL operation, as given to
+// LoggerConfig.OnQuery.
+type QueryMetric struct {
+	// Operation is the SQL operation, as returned by SQLOperation, such as
+	// "SELECT" or "INSERT".
+	Operation string
+	// Elapsed is the duration of the SQL operation.
+	Elapsed time.Duration
+	// RowsAffected is the number of affected rows, or -1 if unknown.
+	RowsAffected int64
+	// Err is the error of the SQL operation, if any.
+	Err error
+	// Slow is true if the SQL operation exceeded LoggerConfig.SlowThreshold.
+	Slow bool
+}
+
+// SQLOperation returns the SQL operation of the SQL statement as the first
+// keyword in uppercase, such as "SELECT", "INSERT", "UPDATE", or "DELETE".
+// Returns "UNKNOWN" if the statement does not start with a keyword.
+//
+// The result has a low cardinality, making it suitable as a metric label.
+func SQLOperation(sql string) string {
+	sql = strings.TrimLeft(sql, " \t\r\n(")
+	end := 0
+	for end < len(sql) && isASCIILetter(sql[end]) {
+		end++
+	}
+	if end == 0 {
+		return "UNKNOWN"
+	}
+	return strings.ToUpper(sql[:end])
+}
+
+func isASCIILetter(ch byte) bool {
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z'
+}
@@ -0,0 +1,60 @@
+package gormutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestSQLOperation(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{"SELECT * FROM users", "SELECT"},
+		{"insert into users", "INSERT"},
+		{"\n\t  UPDATE users SET name = 'x'", "UPDATE"},
+		{"(SELECT 1) UNION (SELECT 2)", "SELECT"},
+		{"", "UNKNOWN"},
+		{"-- comment", "UNKNOWN"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.sql, func(t *testing.T) {
+			assert.Equal(t, tc.want, SQLOperation(tc.sql))
+		})
+	}
+}
+
+func TestLoggerOnQuery(t *testing.T) {
+	var metrics []QueryMetric
+	log := NewLogger(LoggerConfig{
+		Logger:              logger.NewMock(),
+		AlsoUseGORMLogLevel: true,
+		SlowThreshold:       time.Second,
+		OnQuery: func(_ context.Context, metric QueryMetric) {
+			metrics = append(metrics, metric)
+		},
+	}).LogMode(gormlogger.Silent)
+	errSQL := errors.New("oh no")
+	log.Trace(context.Background(), time.Now(), func() (string, int64) {
+		return "SELECT 1", 1
+	}, nil)
+	log.Trace(context.Background(), time.Now().Add(-2*time.Second), func() (string, int64) {
+		return "DELETE FROM users", 3
+	}, errSQL)
+
+	require.Len(t, metrics, 2)
+	assert.Equal(t, "SELECT", metrics[0].Operation)
+	assert.Equal(t, int64(1), metrics[0].RowsAffected)
+	assert.False(t, metrics[0].Slow)
+	assert.Nil(t, metrics[0].Err)
+	assert.Equal(t, "DELETE", metrics[1].Operation)
+	assert.True(t, metrics[1].Slow)
+	assert.Equal(t, errSQL, metrics[1].Err)
+}
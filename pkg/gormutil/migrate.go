@@ -0,0 +1,157 @@
+package gormutil
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// MigrationStep is a single named step of a database migration.
+type MigrationStep struct {
+	// Name is a human-readable name of the step, used in the logs.
+	Name string
+	// Migrate is the function that performs the migration.
+	Migrate func(db *gorm.DB) error
+}
+
+// MigrateConfig holds configuration for logging database migrations.
+type MigrateConfig struct {
+	// Logger is the logger implementation used when logging. This defaults to
+	// a new scoped logger with the scope "GORM-migrate".
+	Logger logger.Logger
+	// SchemaVersion is the version of the database schema after all steps
+	// have been applied successfully. Defaults to the name of the last step.
+	SchemaVersion string
+}
+
+// MigrationStatus holds the result of the latest migration. Meant to be
+// included in the response of a health endpoint.
+type MigrationStatus struct {
+	// SchemaVersion is the version of the database schema after the latest
+	// successful migration.
+	SchemaVersion string `json:"schemaVersion"`
+	// MigratedAt is when the latest migration finished.
+	MigratedAt time.Time `json:"migratedAt" format:"date-time"`
+	// ElapsedMs is how long the latest migration took, in milliseconds.
+	ElapsedMs int64 `json:"elapsedMs"`
+	// Error is the error message of the latest migration, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+var (
+	migrationStatusMu sync.RWMutex
+	migrationStatus   MigrationStatus
+)
+
+// GetMigrationStatus returns the status of the latest migration performed
+// via Migrate or AutoMigrate.
+func GetMigrationStatus() MigrationStatus {
+	migrationStatusMu.RLock()
+	defer migrationStatusMu.RUnlock()
+	return migrationStatus
+}
+
+// AutoMigrate uses Migrate to run gorm.DB.AutoMigrate on each model as a
+// separate step named after the model's type.
+func AutoMigrate(db *gorm.DB, conf MigrateConfig, models ...any) error {
+	steps := make([]MigrationStep, len(models))
+	for i, model := range models {
+		model := model
+		steps[i] = MigrationStep{
+			Name: modelName(model),
+			Migrate: func(db *gorm.DB) error {
+				return db.AutoMigrate(model)
+			},
+		}
+	}
+	return Migrate(db, conf, steps...)
+}
+
+// Migrate runs all the migration steps in order, and logs each step with its
+// duration and any error. The migration stops at the first failing step.
+//
+// On success, the resulting schema version is stored and can be obtained via
+// GetMigrationStatus, and is added to logs by SchemaVersionFieldsProvider.
+func Migrate(db *gorm.DB, conf MigrateConfig, steps ...MigrationStep) error {
+	if conf.Logger == nil {
+		conf.Logger = logger.NewScoped("GORM-migrate")
+	}
+	if conf.SchemaVersion == "" && len(steps) > 0 {
+		conf.SchemaVersion = steps[len(steps)-1].Name
+	}
+	start := time.Now()
+	for i, step := range steps {
+		stepStart := time.Now()
+		if err := step.Migrate(db); err != nil {
+			conf.Logger.Error().
+				WithString("step", step.Name).
+				WithInt("stepIndex", i).
+				WithDuration("elapsed", time.Since(stepStart)).
+				WithError(err).
+				Message("Failed to migrate.")
+			setMigrationStatus(MigrationStatus{
+				SchemaVersion: GetMigrationStatus().SchemaVersion,
+				MigratedAt:    time.Now(),
+				ElapsedMs:     time.Since(start).Milliseconds(),
+				Error:         err.Error(),
+			})
+			return fmt.Errorf("migration step %q: %w", step.Name, err)
+		}
+		conf.Logger.Debug().
+			WithString("step", step.Name).
+			WithInt("stepIndex", i).
+			WithDuration("elapsed", time.Since(stepStart)).
+			Message("Migrated step.")
+	}
+	elapsed := time.Since(start)
+	setMigrationStatus(MigrationStatus{
+		SchemaVersion: conf.SchemaVersion,
+		MigratedAt:    time.Now(),
+		ElapsedMs:     elapsed.Milliseconds(),
+	})
+	conf.Logger.Info().
+		WithString("schemaVersion", conf.SchemaVersion).
+		WithInt("steps", len(steps)).
+		WithDuration("elapsed", elapsed).
+		Message("Migration complete.")
+	return nil
+}
+
+func setMigrationStatus(status MigrationStatus) {
+	migrationStatusMu.Lock()
+	migrationStatus = status
+	migrationStatusMu.Unlock()
+}
+
+// SchemaVersionFieldsProvider is a logger.FieldsProvider that adds the schema
+// version of the latest successful migration, if any, as the "schemaVersion"
+// field.
+//
+// Meant to be registered using logger.RegisterFieldsProvider, so that the
+// schema version is added to all log events that use
+// logger.Event.WithProvidedFields:
+//
+// 	logger.RegisterFieldsProvider(gormutil.SchemaVersionFieldsProvider)
+func SchemaVersionFieldsProvider(context.Context) []logger.Field {
+	version := GetMigrationStatus().SchemaVersion
+	if version == "" {
+		return nil
+	}
+	return []logger.Field{{Key: "schemaVersion", Value: version}}
+}
+
+func modelName(model any) string {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return "<nil>"
+	}
+	return t.Name()
+}
@@ -0,0 +1,19 @@
+package gormutil_test
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/gormutil"
+)
+
+func ExampleGetMigrationStatus() {
+	r := gin.New()
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"message":   "API is healthy.",
+			"isHealthy": true,
+			"migration": gormutil.GetMigrationStatus(),
+		})
+	})
+}
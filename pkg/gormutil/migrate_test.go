@@ -0,0 +1,68 @@
+package gormutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func nopMigration(*gorm.DB) error { return nil }
+
+func TestMigrate(t *testing.T) {
+	logMock := logger.NewMock()
+	err := Migrate(nil, MigrateConfig{Logger: logMock, SchemaVersion: "v2"},
+		MigrationStep{Name: "create users", Migrate: nopMigration},
+		MigrationStep{Name: "create projects", Migrate: nopMigration},
+	)
+	require.NoError(t, err)
+
+	require.Len(t, logMock.Logs, 3)
+	assert.Equal(t, "create users", logMock.Logs[0].Fields["step"])
+	assert.Equal(t, "create projects", logMock.Logs[1].Fields["step"])
+	assert.Contains(t, logMock.Logs[0].FieldsAdded, "elapsed")
+	assert.Equal(t, "v2", logMock.Logs[2].Fields["schemaVersion"])
+	assert.Equal(t, "v2", GetMigrationStatus().SchemaVersion)
+	assert.Empty(t, GetMigrationStatus().Error)
+}
+
+func TestSchemaVersionFieldsProvider(t *testing.T) {
+	setMigrationStatus(MigrationStatus{})
+	assert.Empty(t, SchemaVersionFieldsProvider(context.Background()))
+
+	err := Migrate(nil, MigrateConfig{Logger: logger.NewMock(), SchemaVersion: "v2"},
+		MigrationStep{Name: "create users", Migrate: nopMigration},
+	)
+	require.NoError(t, err)
+	want := []logger.Field{{Key: "schemaVersion", Value: "v2"}}
+	assert.Equal(t, want, SchemaVersionFieldsProvider(context.Background()))
+}
+
+func TestMigrate_stopsOnError(t *testing.T) {
+	logMock := logger.NewMock()
+	testErr := errors.New("test error")
+	var ranLast bool
+	err := Migrate(nil, MigrateConfig{Logger: logMock},
+		MigrationStep{Name: "fails", Migrate: func(*gorm.DB) error { return testErr }},
+		MigrationStep{Name: "last", Migrate: func(*gorm.DB) error {
+			ranLast = true
+			return nil
+		}},
+	)
+	assert.ErrorIs(t, err, testErr)
+	assert.False(t, ranLast, "ran step after failing step")
+	require.Len(t, logMock.Logs, 1)
+	assert.Equal(t, logger.LevelError, logMock.Logs[0].Level)
+	assert.Equal(t, testErr, logMock.Logs[0].Fields["error"])
+	assert.Equal(t, testErr.Error(), GetMigrationStatus().Error)
+}
+
+func TestModelName(t *testing.T) {
+	type User struct{}
+	assert.Equal(t, "User", modelName(&User{}))
+	assert.Equal(t, "User", modelName(User{}))
+}
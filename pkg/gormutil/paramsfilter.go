@@ -0,0 +1,105 @@
+package gormutil
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// ParamsFilter is implemented by GORM loggers that can filter the SQL
+// parameters before the SQL is logged. It has the same signature as the
+// gorm.io/gorm/logger.ParamsFilter interface of newer versions of GORM.
+type ParamsFilter interface {
+	ParamsFilter(ctx context.Context, sql string, params ...any) (string, []any)
+}
+
+var sqlTablePattern = regexp.MustCompile(`(?i)\b(?:FROM|INTO|UPDATE|JOIN)\s+["` + "`" + `]?([\w.]+)`)
+
+// ParamsFilter removes the parameters from the SQL when
+// LoggerConfig.ParameterizedQueries is enabled, so that the SQL is logged
+// with placeholders instead of interpolated values. The parameters are kept
+// if all tables referenced by the SQL are listed in
+// LoggerConfig.ParameterizedQueriesExceptTables.
+func (log gormLog) ParamsFilter(_ context.Context, sql string, params ...any) (string, []any) {
+	if !log.ParameterizedQueries || log.isParamsAllowed(sql) {
+		return sql, params
+	}
+	return sql, nil
+}
+
+func (log gormLog) isParamsAllowed(sql string) bool {
+	if len(log.ParameterizedQueriesExceptTables) == 0 {
+		return false
+	}
+	matches := sqlTablePattern.FindAllStringSubmatch(sql, -1)
+	if len(matches) == 0 {
+		return false
+	}
+	for _, m := range matches {
+		if !containsFold(log.ParameterizedQueriesExceptTables, m[1]) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithParamsFilter wraps the GORM dialector so that the ParamsFilter of the
+// logger, such as when using LoggerConfig.ParameterizedQueries, is applied
+// before the SQL is interpolated for logging. The dialector is returned as-is
+// if the logger does not implement ParamsFilter.
+//
+// This is needed as the version of GORM used by this module does not yet use
+// the ParamsFilter interface by itself. Note that the wrapped dialector also
+// affects gorm.DB.ToSQL and dry runs.
+//
+// Example usage:
+//
+// 	log := gormutil.NewLogger(gormutil.LoggerConfig{
+// 		ParameterizedQueries: true,
+// 	})
+// 	db, err := gorm.Open(gormutil.WithParamsFilter(postgres.Open(dsn), log), &gorm.Config{
+// 		Logger: log,
+// 	})
+func WithParamsFilter(dialector gorm.Dialector, log gormlogger.Interface) gorm.Dialector {
+	filter, ok := log.(ParamsFilter)
+	if !ok {
+		return dialector
+	}
+	return paramsFilterDialector{dialector, filter}
+}
+
+type paramsFilterDialector struct {
+	gorm.Dialector
+	filter ParamsFilter
+}
+
+func (d paramsFilterDialector) Explain(sql string, vars ...any) string {
+	sql, vars = d.filter.ParamsFilter(context.Background(), sql, vars...)
+	return d.Dialector.Explain(sql, vars...)
+}
+
+func (d paramsFilterDialector) SavePoint(tx *gorm.DB, name string) error {
+	if savePointer, ok := d.Dialector.(gorm.SavePointerDialectorInterface); ok {
+		return savePointer.SavePoint(tx, name)
+	}
+	return gorm.ErrUnsupportedDriver
+}
+
+func (d paramsFilterDialector) RollbackTo(tx *gorm.DB, name string) error {
+	if savePointer, ok := d.Dialector.(gorm.SavePointerDialectorInterface); ok {
+		return savePointer.RollbackTo(tx, name)
+	}
+	return gorm.ErrUnsupportedDriver
+}
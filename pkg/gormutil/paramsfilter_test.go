@@ -0,0 +1,85 @@
+package gormutil
+
+import (
+	"testing"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestWithParamsFilter(t *testing.T) {
+	type User struct {
+		gorm.Model
+		Password string
+	}
+	type Project struct {
+		gorm.Model
+		Name string
+	}
+	tests := []struct {
+		name       string
+		config     LoggerConfig
+		query      func(db *gorm.DB)
+		wantSQL    string
+		wantNotSQL string
+	}{
+		{
+			name:    "interpolated by default",
+			config:  LoggerConfig{},
+			query:   func(db *gorm.DB) { db.Create(&User{Password: "hunter2"}) },
+			wantSQL: "hunter2",
+		},
+		{
+			name:       "parameterized",
+			config:     LoggerConfig{ParameterizedQueries: true},
+			query:      func(db *gorm.DB) { db.Create(&User{Password: "hunter2"}) },
+			wantSQL:    "$1",
+			wantNotSQL: "hunter2",
+		},
+		{
+			name: "allowlisted table",
+			config: LoggerConfig{
+				ParameterizedQueries:             true,
+				ParameterizedQueriesExceptTables: []string{"projects"},
+			},
+			query:   func(db *gorm.DB) { db.Where("name = ?", "wharf").Find(&Project{}) },
+			wantSQL: "'wharf'",
+		},
+		{
+			name: "table not allowlisted",
+			config: LoggerConfig{
+				ParameterizedQueries:             true,
+				ParameterizedQueriesExceptTables: []string{"projects"},
+			},
+			query:      func(db *gorm.DB) { db.Create(&User{Password: "hunter2"}) },
+			wantNotSQL: "hunter2",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logMock := logger.NewMock()
+			tc.config.Logger = logMock
+			log := NewLogger(tc.config)
+			db, err := gorm.Open(WithParamsFilter(postgres.Open("host=localhost"), log), &gorm.Config{
+				DryRun:                 true,
+				DisableAutomaticPing:   true,
+				SkipDefaultTransaction: true,
+				Logger:                 log,
+			})
+			require.NoError(t, err)
+			tc.query(db)
+
+			require.NotEmpty(t, logMock.Logs)
+			sql, _ := logMock.Logs[0].Fields["sql"].(string)
+			if tc.wantSQL != "" {
+				assert.Contains(t, sql, tc.wantSQL)
+			}
+			if tc.wantNotSQL != "" {
+				assert.NotContains(t, sql, tc.wantNotSQL)
+			}
+		})
+	}
+}
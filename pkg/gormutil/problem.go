@@ -0,0 +1,81 @@
+package gormutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iver-wharf/wharf-core/v2/pkg/ginutil"
+	"github.com/iver-wharf/wharf-core/v2/pkg/problem"
+	"gorm.io/gorm"
+)
+
+// sqlStateUniqueViolation is the SQLSTATE error code of unique constraint
+// violations, as used by PostgreSQL.
+const sqlStateUniqueViolation = "23505"
+
+// statusClientClosedRequest is the non-standard HTTP status code used, such as
+// by nginx, when the client closed the connection before the response was
+// written.
+const statusClientClosedRequest = 499
+
+// sqlStateError is implemented by database driver errors that carry a
+// SQLSTATE error code, such as the *pgconn.PgError of the PostgreSQL driver.
+type sqlStateError interface {
+	SQLState() string
+}
+
+// IsDuplicateKeyError returns true if the error is caused by a violated
+// unique constraint, such as when inserting a record with a primary key that
+// already exists.
+func IsDuplicateKeyError(err error) bool {
+	var stateErr sqlStateError
+	if errors.As(err, &stateErr) {
+		return stateErr.SQLState() == sqlStateUniqueViolation
+	}
+	return err != nil && (strings.Contains(err.Error(), "duplicate key") ||
+		strings.Contains(err.Error(), "UNIQUE constraint failed"))
+}
+
+// WriteProblemFromError writes a problem response for the error returned by
+// GORM, using the ginutil problem writers and the resource name in the
+// problem detail:
+//
+// 	gorm.ErrRecordNotFound        => ginutil.WriteDBNotFound (404)
+// 	IsDuplicateKeyError           => ginutil.WriteConflictError (409)
+// 	context.DeadlineExceeded      => ginutil.WriteDBTimeoutError (504)
+// 	context.Canceled              => "/prob/api/client-closed-request" (499)
+// 	any other error               => ginutil.WriteDBReadError (502)
+//
+// A canceled context means that the client went away, such as when the HTTP
+// request's context is passed via gorm.DB.WithContext, and is therefore not
+// reported as a database failure.
+//
+// Example usage:
+//
+// 	var project Project
+// 	if err := db.First(&project, projectID).Error; err != nil {
+// 		gormutil.WriteProblemFromError(c, err, fmt.Sprintf("Project with ID %d", projectID))
+// 		return
+// 	}
+func WriteProblemFromError(c *gin.Context, err error, resourceName string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		ginutil.WriteDBNotFound(c, fmt.Sprintf("%s was not found.", resourceName))
+	case IsDuplicateKeyError(err):
+		ginutil.WriteConflictError(c, err, fmt.Sprintf("%s already exists.", resourceName))
+	case errors.Is(err, context.DeadlineExceeded):
+		ginutil.WriteDBTimeoutError(c, err, fmt.Sprintf("Timed out while accessing %s in the database.", resourceName))
+	case errors.Is(err, context.Canceled):
+		ginutil.WriteProblemError(c, err, problem.Response{
+			Type:   "/prob/api/client-closed-request",
+			Title:  "Client closed request.",
+			Status: statusClientClosedRequest,
+			Detail: fmt.Sprintf("Request was canceled while accessing %s in the database.", resourceName),
+		})
+	default:
+		ginutil.WriteDBReadError(c, err, fmt.Sprintf("Failed to access %s in the database.", resourceName))
+	}
+}
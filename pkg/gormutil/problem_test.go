@@ -0,0 +1,56 @@
+package gormutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type fakeSQLStateError struct {
+	code string
+}
+
+func (err fakeSQLStateError) Error() string    { return "sql error " + err.code }
+func (err fakeSQLStateError) SQLState() string { return err.code }
+
+func TestIsDuplicateKeyError(t *testing.T) {
+	assert.True(t, IsDuplicateKeyError(fakeSQLStateError{"23505"}))
+	assert.True(t, IsDuplicateKeyError(fmt.Errorf("create: %w", fakeSQLStateError{"23505"})))
+	assert.True(t, IsDuplicateKeyError(errors.New("UNIQUE constraint failed: users.name")))
+	assert.False(t, IsDuplicateKeyError(fakeSQLStateError{"23503"}))
+	assert.False(t, IsDuplicateKeyError(errors.New("oh no")))
+	assert.False(t, IsDuplicateKeyError(nil))
+}
+
+func TestWriteProblemFromError(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantDetail string
+	}{
+		{"not found", fmt.Errorf("find: %w", gorm.ErrRecordNotFound), http.StatusNotFound, "Project 1 was not found."},
+		{"duplicate key", fakeSQLStateError{"23505"}, http.StatusConflict, "Project 1 already exists."},
+		{"deadline", context.DeadlineExceeded, http.StatusGatewayTimeout, "Timed out while accessing Project 1 in the database."},
+		{"canceled", fmt.Errorf("find: %w", context.Canceled), 499, "Request was canceled while accessing Project 1 in the database."},
+		{"other", errors.New("oh no"), http.StatusBadGateway, "Failed to access Project 1 in the database."},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/projects/1", nil)
+			WriteProblemFromError(c, tc.err, "Project 1")
+			assert.Equal(t, tc.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.wantDetail)
+		})
+	}
+}
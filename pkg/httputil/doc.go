@@ -0,0 +1,3 @@
+// Package httputil contains utility functions for issuing HTTP requests, such
+// as downloading files from other Wharf components or remote providers.
+package httputil
@@ -0,0 +1,318 @@
+package httputil
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/cacertutil"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+)
+
+var log = logger.NewScoped("HTTP-UTIL")
+
+// ErrChecksumMismatch is returned by Download when the SHA-256 checksum of the
+// downloaded file does not match the expected checksum.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ErrUnexpectedStatus is returned by Download when the server responds with a
+// non-successful HTTP status code.
+var ErrUnexpectedStatus = errors.New("unexpected HTTP status")
+
+// partialFileSuffix is appended to the destination path while the file is
+// still being downloaded.
+const partialFileSuffix = ".part"
+
+// DownloadOptions lets you configure the behavior of Download. The zero value
+// is valid and will download the file using http.DefaultClient without any
+// checksum verification nor resuming.
+type DownloadOptions struct {
+	// Client is the HTTP client used when downloading. Defaults to
+	// http.DefaultClient, or to a client created using
+	// cacertutil.NewHTTPClientWithCerts if CertsFile is set.
+	Client *http.Client
+	// CertsFile is an optional path to a file of PEM formatted certificates to
+	// trust in addition to the system's cert pool. Ignored if Client is set.
+	CertsFile string
+	// SHA256 is the expected hex-encoded SHA-256 checksum of the downloaded
+	// file. Verification is skipped if left empty.
+	SHA256 string
+	// Resume enables continuing a previously interrupted download by
+	// requesting only the remaining bytes via the HTTP Range header.
+	//
+	// If the server does not support range requests, or responds with a
+	// range that does not continue from the end of the partial file, then the
+	// download is restarted from the beginning.
+	Resume bool
+	// ProgressInterval is how often a progress log message is emitted while
+	// downloading. Defaults to 5 seconds. Set to a negative value to disable.
+	ProgressInterval time.Duration
+	// Logger is the logger implementation used when logging. Defaults to a
+	// scoped logger with the scope "HTTP-UTIL".
+	Logger logger.Logger
+}
+
+// Download fetches the file at the given URL and writes it to the dst file
+// path.
+//
+// The file is first downloaded into a temporary file with the ".part" suffix
+// next to the destination, and is only moved into place after it has been
+// fully downloaded and its checksum (if any) has been verified. This temporary
+// file is what is used when resuming a download.
+//
+// Returns a wrapped ErrChecksumMismatch if the checksum does not match, in
+// which case the partially downloaded file is removed.
+//
+// Returns a wrapped ErrUnexpectedStatus if the server responds with a
+// non-2xx status code.
+//
+// The URL is redacted in logs and errors, by leaving out any user info and
+// query parameters, as they may contain credentials.
+func Download(ctx context.Context, url, dst string, opts DownloadOptions) error {
+	logURL := redactURL(url)
+	if opts.Logger == nil {
+		opts.Logger = log
+	}
+	if opts.ProgressInterval == 0 {
+		opts.ProgressInterval = 5 * time.Second
+	}
+	client, err := opts.httpClient()
+	if err != nil {
+		return err
+	}
+
+	partPath := dst + partialFileSuffix
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	var offset int64
+	if opts.Resume {
+		if stat, err := os.Stat(partPath); err == nil {
+			offset = stat.Size()
+			flags = os.O_CREATE | os.O_WRONLY
+		}
+	}
+
+	resp, err := downloadRequest(ctx, client, url, offset)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		start, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		switch {
+		case resp.StatusCode == http.StatusPartialContent && ok && start == offset:
+			// resuming from the end of the partial file
+		case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && ok && size == offset:
+			// the partial file is already complete
+			resp.Body.Close()
+			return opts.finish(partPath, dst)
+		case resp.StatusCode == http.StatusPartialContent,
+			resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+			resp, err = opts.restartDownload(ctx, client, url, logURL, resp)
+			if err != nil {
+				return err
+			}
+			offset = 0
+		}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case offset > 0 && resp.StatusCode == http.StatusPartialContent:
+		opts.Logger.Debug().
+			WithString("url", logURL).
+			WithInt64("offset", offset).
+			Message("Resuming download.")
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		offset = 0
+		flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	default:
+		return fmt.Errorf("download %q: %w: %s", logURL, ErrUnexpectedStatus, resp.Status)
+	}
+
+	file, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("open download file: %w", err)
+	}
+	if offset > 0 {
+		if _, err := file.Seek(0, io.SeekEnd); err != nil {
+			file.Close()
+			return fmt.Errorf("seek download file: %w", err)
+		}
+	}
+
+	var total int64 = -1
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	}
+	progress := &progressWriter{
+		log:      opts.Logger,
+		url:      logURL,
+		interval: opts.ProgressInterval,
+		written:  offset,
+		total:    total,
+		start:    time.Now(),
+		lastLog:  time.Now(),
+	}
+	_, copyErr := io.Copy(file, io.TeeReader(resp.Body, progress))
+	closeErr := file.Close()
+	if copyErr != nil {
+		return fmt.Errorf("download %q: %w", logURL, copyErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("close download file: %w", closeErr)
+	}
+	opts.Logger.Debug().
+		WithString("url", logURL).
+		WithInt64("bytes", progress.written).
+		WithDuration("elapsed", time.Since(progress.start)).
+		Message("Download complete.")
+	return opts.finish(partPath, dst)
+}
+
+func downloadRequest(ctx context.Context, client *http.Client, url string, offset int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create download request: %w", err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download %q: %w", redactURL(url), err)
+	}
+	return resp, nil
+}
+
+// restartDownload closes the response of a range request that could not be
+// resumed, and requests the whole file instead.
+func (opts DownloadOptions) restartDownload(ctx context.Context, client *http.Client, url, logURL string, resp *http.Response) (*http.Response, error) {
+	resp.Body.Close()
+	opts.Logger.Debug().
+		WithString("url", logURL).
+		WithString("status", resp.Status).
+		WithString("contentRange", resp.Header.Get("Content-Range")).
+		Message("Unable to resume download, restarting.")
+	return downloadRequest(ctx, client, url, 0)
+}
+
+// parseContentRange parses the first byte position and the complete length
+// from a Content-Range header value, such as "bytes 100-4999/5000" or
+// "bytes */5000", where -1 is returned for any unknown value.
+func parseContentRange(s string) (start, size int64, ok bool) {
+	if !strings.HasPrefix(s, "bytes ") {
+		return 0, 0, false
+	}
+	rng, sizeStr, ok := strings.Cut(strings.TrimPrefix(s, "bytes "), "/")
+	if !ok {
+		return 0, 0, false
+	}
+	start, size = -1, -1
+	var err error
+	if sizeStr != "*" {
+		if size, err = strconv.ParseInt(sizeStr, 10, 64); err != nil {
+			return 0, 0, false
+		}
+	}
+	if rng != "*" {
+		startStr, _, ok := strings.Cut(rng, "-")
+		if !ok {
+			return 0, 0, false
+		}
+		if start, err = strconv.ParseInt(startStr, 10, 64); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, size, true
+}
+
+// redactURL returns the URL without user info, query, nor fragment, as they
+// may contain credentials.
+func redactURL(rawURL string) string {
+	u, err := neturl.Parse(rawURL)
+	if err != nil {
+		return "<invalid URL>"
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.ForceQuery = false
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String()
+}
+
+func (opts DownloadOptions) httpClient() (*http.Client, error) {
+	if opts.Client != nil {
+		return opts.Client, nil
+	}
+	if opts.CertsFile != "" {
+		return cacertutil.NewHTTPClientWithCerts(opts.CertsFile)
+	}
+	return http.DefaultClient, nil
+}
+
+func (opts DownloadOptions) finish(partPath, dst string) error {
+	if opts.SHA256 != "" {
+		sum, err := fileSHA256(partPath)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(sum, opts.SHA256) {
+			os.Remove(partPath)
+			return fmt.Errorf("download %q: %w: want %s, got %s",
+				dst, ErrChecksumMismatch, opts.SHA256, sum)
+		}
+	}
+	if err := os.Rename(partPath, dst); err != nil {
+		return fmt.Errorf("move downloaded file into place: %w", err)
+	}
+	return nil
+}
+
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open file for checksum: %w", err)
+	}
+	defer file.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("read file for checksum: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+type progressWriter struct {
+	log      logger.Logger
+	url      string
+	interval time.Duration
+	written  int64
+	total    int64
+	start    time.Time
+	lastLog  time.Time
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.written += int64(len(p))
+	if w.interval > 0 && time.Since(w.lastLog) >= w.interval {
+		w.lastLog = time.Now()
+		ev := w.log.Info().
+			WithString("url", w.url).
+			WithInt64("bytes", w.written)
+		if w.total > 0 {
+			ev = ev.WithInt64("total", w.total).
+				WithFloat64("percent", float64(w.written)*100/float64(w.total))
+		}
+		ev.WithDuration("elapsed", time.Since(w.start)).
+			Message("Downloading.")
+	}
+	return len(p), nil
+}
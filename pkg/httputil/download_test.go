@@ -0,0 +1,224 @@
+package httputil
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testContent = bytes.Repeat([]byte("wharf"), 1000)
+
+func newTestServer(t *testing.T) (*httptest.Server, *[]string) {
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "file", time.Time{}, bytes.NewReader(testContent))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &ranges
+}
+
+func testContentSHA256() string {
+	sum := sha256.Sum256(testContent)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDownload(t *testing.T) {
+	srv, _ := newTestServer(t)
+	dst := filepath.Join(t.TempDir(), "file")
+
+	err := Download(context.Background(), srv.URL, dst, DownloadOptions{
+		SHA256: testContentSHA256(),
+		Logger: logger.NewMock(),
+	})
+	require.NoError(t, err)
+
+	got, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, testContent, got)
+	assert.NoFileExists(t, dst+partialFileSuffix)
+}
+
+func TestDownload_resume(t *testing.T) {
+	srv, ranges := newTestServer(t)
+	dst := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(dst+partialFileSuffix, testContent[:100], 0644))
+
+	err := Download(context.Background(), srv.URL, dst, DownloadOptions{
+		SHA256: testContentSHA256(),
+		Resume: true,
+		Logger: logger.NewMock(),
+	})
+	require.NoError(t, err)
+
+	got, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, testContent, got)
+	assert.Equal(t, []string{"bytes=100-"}, *ranges)
+}
+
+func TestDownload_resumeComplete(t *testing.T) {
+	srv, ranges := newTestServer(t)
+	dst := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(dst+partialFileSuffix, testContent, 0644))
+
+	err := Download(context.Background(), srv.URL, dst, DownloadOptions{
+		SHA256: testContentSHA256(),
+		Resume: true,
+		Logger: logger.NewMock(),
+	})
+	require.NoError(t, err)
+
+	got, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, testContent, got)
+	assert.Equal(t, []string{fmt.Sprintf("bytes=%d-", len(testContent))}, *ranges)
+}
+
+func TestDownload_resumeRestart(t *testing.T) {
+	testCases := []struct {
+		name    string
+		handler http.HandlerFunc
+		partial []byte
+	}{
+		{
+			name: "partial content from wrong offset",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Range") == "" {
+					w.Write(testContent)
+					return
+				}
+				w.Header().Set("Content-Range",
+					fmt.Sprintf("bytes 50-%d/%d", len(testContent)-1, len(testContent)))
+				w.WriteHeader(http.StatusPartialContent)
+				w.Write(testContent[50:])
+			},
+			partial: testContent[:100],
+		},
+		{
+			name: "range not satisfiable with other size",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.ServeContent(w, r, "file", time.Time{}, bytes.NewReader(testContent))
+			},
+			partial: append(append([]byte{}, testContent...), "extra"...),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var ranges []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ranges = append(ranges, r.Header.Get("Range"))
+				tc.handler(w, r)
+			}))
+			defer srv.Close()
+			dst := filepath.Join(t.TempDir(), "file")
+			require.NoError(t, os.WriteFile(dst+partialFileSuffix, tc.partial, 0644))
+
+			err := Download(context.Background(), srv.URL, dst, DownloadOptions{
+				SHA256: testContentSHA256(),
+				Resume: true,
+				Logger: logger.NewMock(),
+			})
+			require.NoError(t, err)
+
+			got, err := os.ReadFile(dst)
+			require.NoError(t, err)
+			assert.Equal(t, testContent, got)
+			assert.Equal(t, []string{fmt.Sprintf("bytes=%d-", len(tc.partial)), ""}, ranges)
+		})
+	}
+}
+
+func TestDownload_redactsURL(t *testing.T) {
+	srv, _ := newTestServer(t)
+	mock := logger.NewMock()
+	url := strings.Replace(srv.URL, "://", "://user:secret@", 1) + "/file?token=secret"
+
+	err := Download(context.Background(), url, filepath.Join(t.TempDir(), "file"), DownloadOptions{
+		ProgressInterval: time.Nanosecond,
+		Logger:           mock,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, mock.Logs)
+	for _, log := range mock.Logs {
+		assert.Equal(t, srv.URL+"/file", log.Fields["url"])
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	testCases := []struct {
+		input     string
+		wantStart int64
+		wantSize  int64
+		wantOK    bool
+	}{
+		{input: "bytes 100-4999/5000", wantStart: 100, wantSize: 5000, wantOK: true},
+		{input: "bytes 100-4999/*", wantStart: 100, wantSize: -1, wantOK: true},
+		{input: "bytes */5000", wantStart: -1, wantSize: 5000, wantOK: true},
+		{input: ""},
+		{input: "bytes 100-4999"},
+		{input: "items 100-4999/5000"},
+		{input: "bytes x-4999/5000"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			start, size, ok := parseContentRange(tc.input)
+			assert.Equal(t, tc.wantOK, ok)
+			if tc.wantOK {
+				assert.Equal(t, tc.wantStart, start)
+				assert.Equal(t, tc.wantSize, size)
+			}
+		})
+	}
+}
+
+func TestDownload_checksumMismatch(t *testing.T) {
+	srv, _ := newTestServer(t)
+	dst := filepath.Join(t.TempDir(), "file")
+
+	err := Download(context.Background(), srv.URL, dst, DownloadOptions{
+		SHA256: "0000",
+		Logger: logger.NewMock(),
+	})
+	assert.True(t, errors.Is(err, ErrChecksumMismatch), "errors.Is(err, ErrChecksumMismatch): %s", err)
+	assert.NoFileExists(t, dst)
+	assert.NoFileExists(t, dst+partialFileSuffix)
+}
+
+func TestDownload_progressLogs(t *testing.T) {
+	srv, _ := newTestServer(t)
+	mock := logger.NewMock()
+
+	err := Download(context.Background(), srv.URL, filepath.Join(t.TempDir(), "file"), DownloadOptions{
+		ProgressInterval: time.Nanosecond,
+		Logger:           mock,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, mock.Logs)
+	assert.Contains(t, mock.LogMessages, "Downloading.")
+	assert.Equal(t, "Download complete.", mock.LogMessages[len(mock.LogMessages)-1])
+}
+
+func TestDownload_unexpectedStatus(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	err := Download(context.Background(), srv.URL, filepath.Join(t.TempDir(), "file"), DownloadOptions{
+		Logger: logger.NewMock(),
+	})
+	assert.True(t, errors.Is(err, ErrUnexpectedStatus), "errors.Is(err, ErrUnexpectedStatus): %s", err)
+}
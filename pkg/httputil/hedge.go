@@ -0,0 +1,164 @@
+package httputil
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+)
+
+// HedgeConfig lets you configure the behavior of the http.RoundTripper created
+// by NewHedgedTransport.
+type HedgeConfig struct {
+	// Transport is the underlying transport used for each attempt. Defaults to
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+	// Delay is how long to wait for a response to the original request before
+	// issuing a second hedged attempt. Defaults to 200 milliseconds.
+	Delay time.Duration
+	// Logger is the logger implementation used when logging. Defaults to a
+	// scoped logger with the scope "HTTP-UTIL".
+	Logger logger.Logger
+}
+
+// NewHedgedTransport creates a http.RoundTripper that masks slow backends by
+// issuing a second hedged attempt of a request if no response has been
+// received after the configured delay. Whichever attempt responds first is
+// returned, and the other attempt is cancelled.
+//
+// Only idempotent GET and HEAD requests without a body are hedged. All other
+// requests are sent as-is using the underlying transport.
+//
+// Example usage:
+//
+// 	client := &http.Client{
+// 		Transport: httputil.NewHedgedTransport(httputil.HedgeConfig{
+// 			Delay: 500 * time.Millisecond,
+// 		}),
+// 	}
+func NewHedgedTransport(conf HedgeConfig) http.RoundTripper {
+	if conf.Transport == nil {
+		conf.Transport = http.DefaultTransport
+	}
+	if conf.Delay <= 0 {
+		conf.Delay = 200 * time.Millisecond
+	}
+	if conf.Logger == nil {
+		conf.Logger = log
+	}
+	return hedgedTransport{conf}
+}
+
+type hedgedTransport struct {
+	HedgeConfig
+}
+
+type hedgeResult struct {
+	attempt int
+	resp    *http.Response
+	err     error
+	cancel  context.CancelFunc
+}
+
+func (t hedgedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isHedgeable(req) {
+		return t.Transport.RoundTrip(req)
+	}
+	start := time.Now()
+	results := make(chan hedgeResult, 2)
+	cancels := make([]context.CancelFunc, 0, 2)
+	send := func(attempt int) {
+		ctx, cancel := context.WithCancel(req.Context())
+		cancels = append(cancels, cancel)
+		attemptReq := req.Clone(ctx)
+		go func() {
+			resp, err := t.Transport.RoundTrip(attemptReq)
+			results <- hedgeResult{attempt, resp, err, cancel}
+		}()
+	}
+
+	send(1)
+	timer := time.NewTimer(t.Delay)
+	defer timer.Stop()
+	inFlight := 1
+	for {
+		select {
+		case <-timer.C:
+			if len(cancels) < 2 {
+				t.Logger.Debug().
+					WithString("method", req.Method).
+					WithString("url", req.URL.String()).
+					WithDuration("delay", t.Delay).
+					Message("Sending hedged request.")
+				send(2)
+				inFlight++
+			}
+		case res := <-results:
+			inFlight--
+			if res.err != nil {
+				res.cancel()
+				if inFlight == 0 {
+					return nil, res.err
+				}
+				continue
+			}
+			t.cancelLosers(cancels, res.attempt, inFlight, results)
+			if len(cancels) == 2 {
+				t.logHedgeResult(req, res.attempt, start)
+			}
+			res.resp.Body = cancelOnCloseBody{res.resp.Body, res.cancel}
+			return res.resp, nil
+		}
+	}
+}
+
+func (t hedgedTransport) cancelLosers(cancels []context.CancelFunc, winner, inFlight int, results <-chan hedgeResult) {
+	// the winner is instead cancelled when its response body is closed
+	for i, cancel := range cancels {
+		if i+1 != winner {
+			cancel()
+		}
+	}
+	if inFlight == 0 {
+		return
+	}
+	go func() {
+		for i := 0; i < inFlight; i++ {
+			if res := <-results; res.resp != nil {
+				res.resp.Body.Close()
+			}
+		}
+	}()
+}
+
+func (t hedgedTransport) logHedgeResult(req *http.Request, winner int, start time.Time) {
+	ev := t.Logger.Debug().
+		WithString("method", req.Method).
+		WithString("url", req.URL.String()).
+		WithDuration("elapsed", time.Since(start))
+	if winner == 2 {
+		ev.Message("Hedged request won.")
+	} else {
+		ev.Message("Hedged request lost.")
+	}
+}
+
+func isHedgeable(req *http.Request) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody
+}
+
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,83 @@
+package httputil
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSlowFirstServer returns a test server where the first request is slow
+// and all later requests respond immediately.
+func newSlowFirstServer(t *testing.T, slow time.Duration) (*httptest.Server, *int32) {
+	var count int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&count, 1)
+		if n == 1 {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(slow):
+			}
+		}
+		io.WriteString(w, "attempt")
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &count
+}
+
+func TestHedgedTransport_hedgeWins(t *testing.T) {
+	srv, count := newSlowFirstServer(t, 5*time.Second)
+	mock := logger.NewMock()
+	client := &http.Client{Transport: NewHedgedTransport(HedgeConfig{
+		Delay:  10 * time.Millisecond,
+		Logger: mock,
+	})}
+
+	start := time.Now()
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, "attempt", string(body))
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, int32(2), atomic.LoadInt32(count))
+	assert.Contains(t, mock.LogMessages, "Hedged request won.")
+}
+
+func TestHedgedTransport_fastOriginal(t *testing.T) {
+	srv, count := newSlowFirstServer(t, 0)
+	client := &http.Client{Transport: NewHedgedTransport(HedgeConfig{
+		Delay:  time.Second,
+		Logger: logger.NewMock(),
+	})}
+
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(count))
+}
+
+func TestHedgedTransport_notIdempotent(t *testing.T) {
+	srv, count := newSlowFirstServer(t, 50*time.Millisecond)
+	client := &http.Client{Transport: NewHedgedTransport(HedgeConfig{
+		Delay:  time.Millisecond,
+		Logger: logger.NewMock(),
+	})}
+
+	resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("body"))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(count))
+}
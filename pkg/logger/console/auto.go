@@ -0,0 +1,60 @@
+package console
+
+import (
+	"os"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/env"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger/consolejson"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger/consolepretty"
+	"github.com/mattn/go-isatty"
+)
+
+// Environment variables that are checked by Auto and NewAuto.
+const (
+	// EnvForceJSON is the environment variable that, when set to a truthy
+	// value such as "true" or "1", forces the JSON-formatted sink to be used
+	// even if STDOUT is a terminal.
+	EnvForceJSON = "FORCE_JSON"
+	// EnvNoColor is the environment variable that, when set to any non-empty
+	// value, disables coloring in the pretty-formatted sink. See
+	// https://no-color.org/
+	EnvNoColor = "NO_COLOR"
+)
+
+var isTerminal = func() bool {
+	fd := os.Stdout.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// Auto returns a logger Sink that outputs human-readable logs using the
+// consolepretty package when STDOUT is a terminal, and JSON-formatted logs
+// using the consolejson package otherwise. Both sinks use their default
+// settings.
+//
+// The JSON-formatted sink is always used if the FORCE_JSON environment
+// variable is set to a truthy value, and coloring of the human-readable logs
+// is disabled if the NO_COLOR environment variable is set.
+func Auto() logger.Sink {
+	return NewAuto(consolepretty.DefaultConfig, consolejson.Config{})
+}
+
+// NewAuto works the same as Auto, but uses the given configs when creating
+// the sinks.
+func NewAuto(prettyConf consolepretty.Config, jsonConf consolejson.Config) logger.Sink {
+	if useJSON() {
+		return consolejson.New(jsonConf)
+	}
+	if _, ok := env.LookupNoEmpty(EnvNoColor); ok {
+		prettyConf.ColorMode = consolepretty.ColorModeNever
+	}
+	return consolepretty.New(prettyConf)
+}
+
+func useJSON() bool {
+	var forceJSON bool
+	if err := env.Bind(&forceJSON, EnvForceJSON); err == nil && forceJSON {
+		return true
+	}
+	return !isTerminal()
+}
@@ -0,0 +1,73 @@
+package console
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/iver-wharf/wharf-core/v2/internal/testutil"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger/consolejson"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger/consolepretty"
+	"github.com/stretchr/testify/assert"
+)
+
+func setIsTerminal(t *testing.T, value bool) {
+	old := isTerminal
+	isTerminal = func() bool { return value }
+	t.Cleanup(func() { isTerminal = old })
+}
+
+func TestAuto(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		isTerminal bool
+		forceJSON  string
+		want       string
+	}{
+		{
+			name:       "terminal",
+			isTerminal: true,
+			want:       "consolepretty.sink",
+		},
+		{
+			name:       "not terminal",
+			isTerminal: false,
+			want:       "consolejson.sink",
+		},
+		{
+			name:       "terminal with FORCE_JSON",
+			isTerminal: true,
+			forceJSON:  "true",
+			want:       "consolejson.sink",
+		},
+		{
+			name:       "terminal with FORCE_JSON=false",
+			isTerminal: true,
+			forceJSON:  "false",
+			want:       "consolepretty.sink",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setIsTerminal(t, tc.isTerminal)
+			testutil.SetEnv(t, EnvForceJSON, tc.forceJSON)
+			assert.Equal(t, tc.want, fmt.Sprintf("%T", Auto()))
+		})
+	}
+}
+
+func TestNewAuto_noColor(t *testing.T) {
+	setIsTerminal(t, true)
+	testutil.SetEnv(t, EnvNoColor, "1")
+
+	var buf bytes.Buffer
+	sink := NewAuto(consolepretty.Config{
+		Writer:        &buf,
+		DisableDate:   true,
+		DisableCaller: true,
+	}, consolejson.Config{})
+	sink.NewContext("").WriteOut(logger.LevelInfo, "hello")
+
+	assert.Equal(t, "[INFO ] hello\n", buf.String())
+}
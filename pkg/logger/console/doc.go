@@ -0,0 +1,4 @@
+// Package console contains helpers for choosing between the console logging
+// sinks found in the consolepretty and consolejson packages, based on the
+// environment that the application runs in.
+package console
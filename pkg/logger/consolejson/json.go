@@ -1,11 +1,19 @@
 package consolejson
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
 	"math"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
 )
@@ -34,9 +42,46 @@ const (
 	TimeUnixNano TimeFormat = "wharf-core/UnixNano"
 )
 
+// DurationFormat specifies the formatting used when logging time.Duration
+// values.
+type DurationFormat string
+
+const (
+	// DurationNumber will render a time.Duration as a number, using the
+	// Config.TimeDurationUnit and Config.TimeDurationUseFloat settings.
+	DurationNumber DurationFormat = ""
+	// DurationString will render a time.Duration as a string, such as "1.52s",
+	// using the same formatting as time.Duration.String.
+	DurationString DurationFormat = "wharf-core/String"
+)
+
+// LevelFormat specifies the formatting used when logging the logging level.
+type LevelFormat string
+
+const (
+	// LevelFormatLowercase will render the logging level as a lowercase
+	// string, such as "debug", "info", "warn", "error", and "panic".
+	LevelFormatLowercase LevelFormat = ""
+	// LevelFormatUppercase will render the logging level as an uppercase
+	// string, such as "DEBUG", "INFO", "WARN", "ERROR", and "PANIC".
+	LevelFormatUppercase LevelFormat = "wharf-core/Uppercase"
+	// LevelFormatSyslog will render the logging level as an integer of the
+	// numerical syslog severity as defined in RFC 5424, where debug is 7, info
+	// is 6, warn is 4, error is 3, and panic is 2.
+	LevelFormatSyslog LevelFormat = "wharf-core/Syslog"
+)
+
 // Config lets you gradually configure the output of the logger by disabling
 // certain features or changing the format of certain field types.
 type Config struct {
+	// Writer is the io.Writer target that the JSON-console logger will write
+	// to, such as a file, a buffer in tests, or os.Stderr. Defaults to
+	// os.Stdout, as resolved when each log event is written.
+	//
+	// Each log event is written using a single Write call, and the calls are
+	// serialized by the Sink so that concurrent log events never interleave,
+	// even if the writer is not safe for concurrent use.
+	Writer io.Writer
 	// DisableDate removes the date field from the log when set to true.
 	//
 	// When set to false:
@@ -60,6 +105,15 @@ type Config struct {
 	// When set to true:
 	// 	{"level":"info","caller":"example.go","message":"Sample message."}
 	DisableCallerLine bool
+	// CallerCombined renders the caller file name and line as a single field
+	// formatted as "file:line" when set to true, using the CallerFileField as
+	// the field name. The CallerLineField is then ignored.
+	//
+	// When set to false:
+	// 	{"level":"info","caller":"example.go","line":20,"message":"Sample message."}
+	// When set to true:
+	// 	{"level":"info","caller":"example.go:20","message":"Sample message."}
+	CallerCombined bool
 	// CallerFileField sets the name of the JSON property used in the logs
 	// caller file path. The value is automatically escaped.
 	// Defaults to "caller".
@@ -87,6 +141,15 @@ type Config struct {
 	// When set to "foo":
 	// 	{"level":"info","message":"Sample message.","foo":"strconv.Atoi: parsing \"bar\": invalid syntax"}
 	ErrorField string
+	// StackField sets the name of the JSON property used in the logs stack
+	// trace, as added via Event.WithStack. The value is automatically escaped.
+	// Defaults to "stack".
+	//
+	// When set to "" (empty string):
+	// 	{"level":"info","message":"Sample message.","stack":[{"function":"main.main","file":"/src/main.go","line":20}]}
+	// When set to "foo":
+	// 	{"level":"info","message":"Sample message.","foo":[{"function":"main.main","file":"/src/main.go","line":20}]}
+	StackField string
 	// LevelField sets the name of the JSON property used in the logs severity
 	// level. The value is automatically escaped.
 	// Defaults to "level".
@@ -96,6 +159,24 @@ type Config struct {
 	// When set to "foo":
 	// 	{"foo":"info","message":"Sample message."}
 	LevelField string
+	// LevelFormat defines how the logging level is rendered. Defaults to
+	// LevelFormatLowercase.
+	//
+	// When set to LevelFormatLowercase:
+	// 	{"level":"warn","message":"Sample message."}
+	// When set to LevelFormatUppercase:
+	// 	{"level":"WARN","message":"Sample message."}
+	// When set to LevelFormatSyslog:
+	// 	{"level":4,"message":"Sample message."}
+	LevelFormat LevelFormat
+	// LevelLabels overrides the rendered string of specific logging levels,
+	// such as for matching the tokens expected by a log ingestion pipeline.
+	// Any logging levels missing from the map are rendered using LevelFormat.
+	// The values are automatically escaped.
+	//
+	// When set to map[logger.Level]string{logger.LevelWarn: "WARNING"}:
+	// 	{"level":"WARNING","message":"Sample message."}
+	LevelLabels map[logger.Level]string
 	// MessageField sets the name of the JSON property used in the logs message.
 	// The value is automatically escaped.
 	// Defaults to "message".
@@ -127,6 +208,17 @@ type Config struct {
 	// rendered. Defaults to TimeRFC3339, which looks like so:
 	// 	2006-01-02T15:04:05Z
 	TimeFormat TimeFormat
+	// TimeLocation converts the date field and all time.Time fields added via
+	// Event.WithTime to this time zone before formatting them, such as
+	// time.UTC to avoid mixed offsets in aggregated logs from containers
+	// running in different time zones. Defaults to nil, which leaves the
+	// times as-is.
+	//
+	// When set to nil:
+	// 	{"level":"info","date":"2006-01-02T16:04:05+01:00","message":"Sample message."}
+	// When set to time.UTC:
+	// 	{"level":"info","date":"2006-01-02T15:04:05Z","message":"Sample message."}
+	TimeLocation *time.Location
 	// TimeDurationUnit defines how time.Duration fields added via
 	// Event.WithDuration is rendered. A value of time.Second will then show
 	// the duration in whole seconds, whereas time.Minute will
@@ -148,6 +240,99 @@ type Config struct {
 	// When set to false (which is the default) the duration is formatted as an
 	// integer.
 	TimeDurationUseFloat bool
+	// TimeDurationFormat defines how time.Duration fields added via
+	// Event.WithDuration is rendered. Defaults to DurationNumber.
+	//
+	// When set to DurationString, the TimeDurationUnit is instead used to
+	// round the duration, and TimeDurationUseFloat is ignored.
+	//
+	// When set to DurationNumber:
+	// 	{"level":"info","message":"Sample message.","elapsed":1523000000}
+	// When set to DurationString:
+	// 	{"level":"info","message":"Sample message.","elapsed":"1.523s"}
+	// When set to DurationString and TimeDurationUnit to time.Millisecond*10:
+	// 	{"level":"info","message":"Sample message.","elapsed":"1.52s"}
+	TimeDurationFormat DurationFormat
+	// StaticFields are fields added to every log event, such as the service
+	// name, environment, or region. The fields are encoded once when creating
+	// the Sink, and are rendered sorted by key after the scope field.
+	//
+	// Supported value types are the same as for logger.Field, where any other
+	// types are encoded using encoding/json.
+	//
+	// When set to map[string]any{"service": "wharf-api", "env": "prod"}:
+	// 	{"level":"info","env":"prod","service":"wharf-api","message":"Sample message."}
+	StaticFields map[string]any
+	// Indent pretty-prints each log event across multiple lines, using this
+	// string as the indentation. Meant for local debugging of the exact
+	// output, as most log collectors expect one event per line. Defaults to ""
+	// (empty string), which disables the indentation.
+	//
+	// When set to "" (empty string):
+	// 	{"level":"info","message":"Sample message."}
+	// When set to "  " (two spaces):
+	// 	{
+	// 	  "level": "info",
+	// 	  "message": "Sample message."
+	// 	}
+	Indent string
+	// FieldsBeforeMessage renders the fields added via the Event.With...
+	// methods before the message and error fields when set to true, instead of
+	// at the end of the log event. Useful with JSON viewers that truncate long
+	// lines, to keep the important fields visible.
+	//
+	// When set to false:
+	// 	{"level":"info","message":"Sample message.","error":"some error","id":1}
+	// When set to true:
+	// 	{"level":"info","id":1,"message":"Sample message.","error":"some error"}
+	FieldsBeforeMessage bool
+	// SortFields renders the fields added via the Event.With... methods sorted
+	// by their keys when set to true, instead of in the order they were added.
+	// Fields of nested objects are not sorted.
+	//
+	// When set to false:
+	// 	{"level":"info","message":"Sample message.","b":2,"a":1}
+	// When set to true:
+	// 	{"level":"info","message":"Sample message.","a":1,"b":2}
+	SortFields bool
+
+	// staticFieldsJSON is the pre-encoded StaticFields.
+	staticFieldsJSON []byte
+	// levelValues is the pre-encoded JSON values of each logging level.
+	levelValues [logger.LevelSilence]string
+	// sourceLocation renders the caller as a Google Cloud Logging source
+	// location object. Used by presets.
+	sourceLocation bool
+}
+
+// GoogleCloudConfig is a preset that outputs JSON-formatted logs in the
+// structure that Google Cloud Logging expects from the logs of containers, such
+// as when running in Google Kubernetes Engine (GKE). This includes the
+// "severity", "time", "message", and "logging.googleapis.com/sourceLocation"
+// fields, so that the logs get their levels and source code locations
+// recognized by Cloud Logging.
+//
+// Sample output:
+// 	{"severity":"INFO","time":"2006-01-02T15:04:05.999999999Z","logging.googleapis.com/sourceLocation":{"file":"example.go","line":"20"},"message":"Sample message."}
+//
+// The preset can be combined with other settings by copying it:
+// 	conf := consolejson.GoogleCloudConfig
+// 	conf.DisableCallerLine = true
+// 	logger.AddOutput(logger.LevelDebug, consolejson.New(conf))
+//
+// See: https://cloud.google.com/logging/docs/structured-logging
+var GoogleCloudConfig = Config{
+	LevelField: "severity",
+	DateField:  "time",
+	TimeFormat: TimeFormat(time.RFC3339Nano),
+	LevelLabels: map[logger.Level]string{
+		logger.LevelDebug: "DEBUG",
+		logger.LevelInfo:  "INFO",
+		logger.LevelWarn:  "WARNING",
+		logger.LevelError: "ERROR",
+		logger.LevelPanic: "CRITICAL",
+	},
+	sourceLocation: true,
 }
 
 // Default is a logger Sink that outputs JSON-formatted logs to the console
@@ -159,49 +344,173 @@ func New(conf Config) logger.Sink {
 	conf.CallerFileField = prepareFieldName(conf.CallerFileField, "caller")
 	conf.CallerLineField = prepareFieldName(conf.CallerLineField, "line")
 	conf.ErrorField = prepareFieldName(conf.ErrorField, "error")
+	conf.StackField = prepareFieldName(conf.StackField, "stack")
 	conf.LevelField = prepareFieldName(conf.LevelField, "level")
 	conf.MessageField = prepareFieldName(conf.MessageField, "message")
 	conf.ScopeField = prepareFieldName(conf.ScopeField, "scope")
 	conf.DateField = prepareFieldName(conf.DateField, "date")
-	return sink{&conf}
+	conf.levelValues = encodeLevelValues(&conf)
+	if conf.TimeFormat == "" {
+		conf.TimeFormat = TimeRFC3339
+	}
+	conf.staticFieldsJSON = encodeStaticFields(&conf)
+	return sink{&conf, &sync.Mutex{}}
+}
+
+func encodeStaticFields(conf *Config) []byte {
+	if len(conf.StaticFields) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(conf.StaticFields))
+	for key := range conf.StaticFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	c := &context{Config: conf}
+	for _, key := range keys {
+		switch v := conf.StaticFields[key].(type) {
+		case string:
+			c.AppendString(key, v)
+		case bool:
+			c.AppendBool(key, v)
+		case int:
+			c.AppendInt(key, v)
+		case int32:
+			c.AppendInt32(key, v)
+		case int64:
+			c.AppendInt64(key, v)
+		case uint:
+			c.AppendUint(key, v)
+		case uint32:
+			c.AppendUint32(key, v)
+		case uint64:
+			c.AppendUint64(key, v)
+		case float32:
+			c.AppendFloat32(key, v)
+		case float64:
+			c.AppendFloat64(key, v)
+		case time.Time:
+			c.AppendTime(key, v)
+		case time.Duration:
+			c.AppendDuration(key, v)
+		case error:
+			c.AppendString(key, v.Error())
+		case fmt.Stringer:
+			c.AppendString(key, v.String())
+		default:
+			c.fields = appendFieldName(c.fields, key)
+			if b, err := json.Marshal(v); err == nil {
+				c.fields = append(c.fields, b...)
+			} else {
+				c.fields = append(c.fields, "null"...)
+			}
+		}
+	}
+	return c.fields
 }
 
 type sink struct {
 	config *Config
+	mu     *sync.Mutex
 }
 
 // NewContext creates a new JSON-console logging Context using the
 // same configuration as the one given when creating the Sink.
 func (s sink) NewContext(scope string) logger.Context {
-	return context{
-		Config: s.config,
-		scope:  scope,
+	c := contextPool.Get().(*context)
+	c.Config = s.config
+	c.mu = s.mu
+	c.scope = scope
+	return c
+}
+
+// maxPooledBufferSize is the largest buffer capacity that is returned to the
+// pools, so that a single huge log event does not retain its memory forever.
+const maxPooledBufferSize = 64 << 10
+
+// typicalEventSize is a moving average of the written log events' sizes, and
+// is used as the initial capacity of newly allocated buffers.
+var typicalEventSize int64 = 256
+
+var (
+	contextPool = sync.Pool{
+		New: func() any {
+			return &context{}
+		},
 	}
+	bufferPool = sync.Pool{
+		New: func() any {
+			b := make([]byte, 0, atomic.LoadInt64(&typicalEventSize))
+			return &b
+		},
+	}
+)
+
+func putContext(c *context) {
+	if cap(c.fields) > maxPooledBufferSize {
+		c.fields = nil
+	}
+	*c = context{fields: c.fields[:0]}
+	contextPool.Put(c)
+}
+
+func putBuffer(b *[]byte, size int) {
+	typical := atomic.LoadInt64(&typicalEventSize)
+	atomic.StoreInt64(&typicalEventSize, typical+(int64(size)-typical)/8)
+	if cap(*b) > maxPooledBufferSize {
+		return
+	}
+	*b = (*b)[:0]
+	bufferPool.Put(b)
 }
 
 type context struct {
 	*Config
+	mu         *sync.Mutex
 	fields     []byte
 	caller     string
 	callerLine int
 	scope      string
 	error      error
+	stack      []logger.StackFrame
 }
 
-func (c context) WriteOut(level logger.Level, message string) {
-	var buf []byte
+// WriteOut writes the log event and then returns the context to the pool, so
+// the context must not be used again afterwards.
+func (c *context) WriteOut(level logger.Level, message string) {
+	bufPtr := bufferPool.Get().(*[]byte)
+	buf := *bufPtr
 	buf = append(buf, `{"`...)
 	buf = append(buf, c.LevelField...)
-	buf = append(buf, `":"`...)
-	buf = append(buf, levelString(level)...)
-	buf = append(buf, '"')
+	buf = append(buf, `":`...)
+	if level < logger.LevelSilence {
+		buf = append(buf, c.levelValues[level]...)
+	} else {
+		buf = append(buf, `"unknown"`...)
+	}
 
 	if !c.DisableDate {
 		buf = appendFieldNameRaw(buf, c.DateField)
-		buf = appendTime(buf, time.Now(), c.TimeFormat)
+		buf = appendTime(buf, c.inLocation(time.Now()), c.TimeFormat)
 	}
 
-	if !c.DisableCaller {
+	if !c.DisableCaller && c.sourceLocation {
+		buf = append(buf, `,"logging.googleapis.com/sourceLocation":{"file":`...)
+		buf = appendEscapedString(buf, c.caller)
+		if !c.DisableCallerLine {
+			buf = append(buf, `,"line":"`...)
+			buf = strconv.AppendInt(buf, int64(c.callerLine), 10)
+			buf = append(buf, '"')
+		}
+		buf = append(buf, '}')
+	} else if !c.DisableCaller && c.CallerCombined && !c.DisableCallerLine {
+		buf = appendFieldNameRaw(buf, c.CallerFileField)
+		buf = appendEscapedString(buf, c.caller)
+		// replace the closing quote with the line number
+		buf = append(buf[:len(buf)-1], ':')
+		buf = strconv.AppendInt(buf, int64(c.callerLine), 10)
+		buf = append(buf, '"')
+	} else if !c.DisableCaller {
 		buf = appendFieldNameRaw(buf, c.CallerFileField)
 		buf = appendEscapedString(buf, c.caller)
 		if !c.DisableCallerLine {
@@ -215,6 +524,12 @@ func (c context) WriteOut(level logger.Level, message string) {
 		buf = appendEscapedString(buf, c.scope)
 	}
 
+	buf = append(buf, c.staticFieldsJSON...)
+
+	if c.FieldsBeforeMessage {
+		buf = c.appendFields(buf)
+	}
+
 	if message != "" {
 		buf = appendFieldNameRaw(buf, c.MessageField)
 		buf = appendEscapedString(buf, message)
@@ -225,82 +540,185 @@ func (c context) WriteOut(level logger.Level, message string) {
 		buf = appendEscapedString(buf, c.error.Error())
 	}
 
-	buf = append(buf, c.fields...)
-	buf = append(buf, "}\n"...)
+	if c.stack != nil {
+		buf = appendFieldNameRaw(buf, c.StackField)
+		buf = appendStack(buf, c.stack)
+	}
+
+	if !c.FieldsBeforeMessage {
+		buf = c.appendFields(buf)
+	}
+	buf = append(buf, '}')
+	if c.Indent != "" {
+		buf = indentJSON(buf, c.Indent)
+	}
+	buf = append(buf, '\n')
 
-	os.Stdout.Write(buf)
+	c.mu.Lock()
+	c.writer().Write(buf)
+	c.mu.Unlock()
+
+	*bufPtr = buf
+	putBuffer(bufPtr, len(buf))
+	putContext(c)
+}
+
+func (c *context) appendFields(b []byte) []byte {
+	if !c.SortFields {
+		return append(b, c.fields...)
+	}
+	return appendSortedFields(b, c.fields)
 }
 
-func (c context) SetCaller(file string, line int) logger.Context {
+// appendSortedFields appends the encoded fields sorted by their keys. The
+// order of fields with equal keys is kept as-is.
+func appendSortedFields(b []byte, fields []byte) []byte {
+	type field struct {
+		start, keyEnd, end int
+	}
+	var sorted []field
+	var (
+		inString bool
+		escaped  bool
+		depth    int
+	)
+	for i, ch := range fields {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			switch ch {
+			case '\\':
+				escaped = true
+			case '"':
+				inString = false
+			}
+		case ch == '"':
+			inString = true
+		case ch == '{' || ch == '[':
+			depth++
+		case ch == '}' || ch == ']':
+			depth--
+		case depth > 0:
+		case ch == ',':
+			if len(sorted) > 0 {
+				sorted[len(sorted)-1].end = i
+			}
+			sorted = append(sorted, field{start: i})
+		case ch == ':' && len(sorted) > 0 && sorted[len(sorted)-1].keyEnd == 0:
+			sorted[len(sorted)-1].keyEnd = i
+		}
+	}
+	if len(sorted) == 0 {
+		return append(b, fields...)
+	}
+	sorted[len(sorted)-1].end = len(fields)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		return string(fields[a.start:a.keyEnd]) < string(fields[b.start:b.keyEnd])
+	})
+	for _, f := range sorted {
+		b = append(b, fields[f.start:f.end]...)
+	}
+	return b
+}
+
+func indentJSON(b []byte, indent string) []byte {
+	var out bytes.Buffer
+	if err := json.Indent(&out, b, "", indent); err != nil {
+		return b
+	}
+	return append(b[:0], out.Bytes()...)
+}
+
+func (c *context) writer() io.Writer {
+	if c.Writer == nil {
+		return os.Stdout
+	}
+	return c.Writer
+}
+
+func (c *context) SetCaller(file string, line int) logger.Context {
 	c.caller, c.callerLine = file, line
 	return c
 }
 
-func (c context) SetError(value error) logger.Context {
+func (c *context) SetError(value error) logger.Context {
 	c.error = value
 	return c
 }
 
-func (c context) AppendString(key string, value string) logger.Context {
+func (c *context) SetStack(value []logger.StackFrame) logger.Context {
+	c.stack = value
+	return c
+}
+
+func (c *context) AppendString(key string, value string) logger.Context {
 	c.fields = appendFieldName(c.fields, key)
 	c.fields = appendEscapedString(c.fields, value)
 	return c
 }
 
-func (c context) AppendRune(key string, value rune) logger.Context {
+func (c *context) AppendRune(key string, value rune) logger.Context {
 	return c.AppendString(key, string(value))
 }
 
-func (c context) AppendBool(key string, value bool) logger.Context {
+func (c *context) AppendBool(key string, value bool) logger.Context {
 	c.fields = appendFieldName(c.fields, key)
 	c.fields = strconv.AppendBool(c.fields, value)
 	return c
 }
 
-func (c context) AppendInt(k string, v int) logger.Context {
+func (c *context) AppendInt(k string, v int) logger.Context {
 	c.fields = appendInt64(c.fields, k, int64(v))
 	return c
 }
-func (c context) AppendInt32(k string, v int32) logger.Context {
+func (c *context) AppendInt32(k string, v int32) logger.Context {
 	c.fields = appendInt64(c.fields, k, int64(v))
 	return c
 }
-func (c context) AppendInt64(k string, v int64) logger.Context {
+func (c *context) AppendInt64(k string, v int64) logger.Context {
 	c.fields = appendInt64(c.fields, k, v)
 	return c
 }
 
-func (c context) AppendUint(k string, v uint) logger.Context {
+func (c *context) AppendUint(k string, v uint) logger.Context {
 	c.fields = appendUint64(c.fields, k, uint64(v))
 	return c
 }
-func (c context) AppendUint32(k string, v uint32) logger.Context {
+func (c *context) AppendUint32(k string, v uint32) logger.Context {
 	c.fields = appendUint64(c.fields, k, uint64(v))
 	return c
 }
-func (c context) AppendUint64(k string, v uint64) logger.Context {
+func (c *context) AppendUint64(k string, v uint64) logger.Context {
 	c.fields = appendUint64(c.fields, k, v)
 	return c
 }
 
-func (c context) AppendFloat32(key string, value float32) logger.Context {
+func (c *context) AppendFloat32(key string, value float32) logger.Context {
 	c.fields = appendFloat(c.fields, key, float64(value), 32)
 	return c
 }
 
-func (c context) AppendFloat64(key string, value float64) logger.Context {
+func (c *context) AppendFloat64(key string, value float64) logger.Context {
 	c.fields = appendFloat(c.fields, key, value, 64)
 	return c
 }
 
-func (c context) AppendTime(key string, value time.Time) logger.Context {
+func (c *context) AppendTime(key string, value time.Time) logger.Context {
 	c.fields = appendFieldName(c.fields, key)
-	c.fields = appendTime(c.fields, value, c.TimeFormat)
+	c.fields = appendTime(c.fields, c.inLocation(value), c.TimeFormat)
 	return c
 }
 
-func (c context) AppendDuration(key string, value time.Duration) logger.Context {
+func (c *context) AppendDuration(key string, value time.Duration) logger.Context {
 	switch {
+	case c.TimeDurationFormat == DurationString:
+		if c.TimeDurationUnit > 0 {
+			value = value.Round(c.TimeDurationUnit)
+		}
+		c.fields = appendFieldName(c.fields, key)
+		c.fields = appendEscapedString(c.fields, value.String())
 	case c.TimeDurationUseFloat:
 		valueFloat := float64(value)
 		if c.TimeDurationUnit > 0 {
@@ -317,6 +735,44 @@ func (c context) AppendDuration(key string, value time.Duration) logger.Context
 	return c
 }
 
+func (c *context) AppendObject(key string, fn func(logger.ObjectEncoder)) logger.Context {
+	c.fields = appendFieldName(c.fields, key)
+	c.fields = append(c.fields, '{')
+	start := len(c.fields)
+	fn(objectEncoder{c})
+	if len(c.fields) > start {
+		// remove the leading comma of the first field in the object
+		c.fields = append(c.fields[:start], c.fields[start+1:]...)
+	}
+	c.fields = append(c.fields, '}')
+	return c
+}
+
+// objectEncoder adds the fields of a nested object directly to the context's
+// fields buffer, which is then wrapped in curly braces by AppendObject.
+type objectEncoder struct {
+	c *context
+}
+
+func (e objectEncoder) AddString(k string, v string)          { e.c.AppendString(k, v) }
+func (e objectEncoder) AddBool(k string, v bool)              { e.c.AppendBool(k, v) }
+func (e objectEncoder) AddInt(k string, v int)                { e.c.AppendInt(k, v) }
+func (e objectEncoder) AddInt64(k string, v int64)            { e.c.AppendInt64(k, v) }
+func (e objectEncoder) AddUint64(k string, v uint64)          { e.c.AppendUint64(k, v) }
+func (e objectEncoder) AddFloat64(k string, v float64)        { e.c.AppendFloat64(k, v) }
+func (e objectEncoder) AddTime(k string, v time.Time)         { e.c.AppendTime(k, v) }
+func (e objectEncoder) AddDuration(k string, v time.Duration) { e.c.AppendDuration(k, v) }
+func (e objectEncoder) AddObject(k string, fn func(logger.ObjectEncoder)) {
+	e.c.AppendObject(k, fn)
+}
+
+func (c *context) inLocation(t time.Time) time.Time {
+	if c.TimeLocation == nil {
+		return t
+	}
+	return t.In(c.TimeLocation)
+}
+
 func appendTime(b []byte, value time.Time, format TimeFormat) []byte {
 	switch format {
 	case TimeUnix:
@@ -382,13 +838,80 @@ func appendFieldNameRaw(b []byte, preEscapedKey string) []byte {
 	return b
 }
 
+func appendStack(b []byte, stack []logger.StackFrame) []byte {
+	b = append(b, '[')
+	for i, frame := range stack {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, `{"function":`...)
+		b = appendEscapedString(b, frame.Function)
+		b = append(b, `,"file":`...)
+		b = appendEscapedString(b, frame.File)
+		b = append(b, `,"line":`...)
+		b = strconv.AppendInt(b, int64(frame.Line), 10)
+		b = append(b, '}')
+	}
+	return append(b, ']')
+}
+
+// appendEscapedString appends the value as a quoted JSON string. Strings that
+// do not need any escaping are appended as-is, and only the strings that do are
+// escaped using encoding/json, which results in the same output but without
+// its reflection and allocation cost in the common case.
 func appendEscapedString(b []byte, value string) []byte {
+	if !needsEscaping(value) {
+		b = append(b, '"')
+		b = append(b, value...)
+		return append(b, '"')
+	}
 	if out, err := json.Marshal(value); err == nil {
 		return append(b, out...)
 	}
 	return append(b, '"', '"')
 }
 
+// needsEscaping returns true if the string contains any characters that are
+// escaped by encoding/json, such as quotes, backslashes, control characters,
+// HTML characters, or invalid UTF-8.
+func needsEscaping(s string) bool {
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c < 0x20 || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+				return true
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if (r == utf8.RuneError && size == 1) || r == '\u2028' || r == '\u2029' {
+			return true
+		}
+		i += size
+	}
+	return false
+}
+
+func encodeLevelValues(conf *Config) [logger.LevelSilence]string {
+	var values [logger.LevelSilence]string
+	for level := logger.LevelDebug; level < logger.LevelSilence; level++ {
+		if label, ok := conf.LevelLabels[level]; ok {
+			values[level] = string(appendEscapedString(nil, label))
+			continue
+		}
+		switch conf.LevelFormat {
+		case LevelFormatSyslog:
+			values[level] = strconv.Itoa(syslogSeverity(level))
+		case LevelFormatUppercase:
+			values[level] = `"` + strings.ToUpper(levelString(level)) + `"`
+		default:
+			values[level] = `"` + levelString(level) + `"`
+		}
+	}
+	return values
+}
+
 func levelString(level logger.Level) string {
 	switch level {
 	case logger.LevelDebug:
@@ -406,6 +929,22 @@ func levelString(level logger.Level) string {
 	}
 }
 
+// syslogSeverity returns the numerical severity as defined in RFC 5424.
+func syslogSeverity(level logger.Level) int {
+	switch level {
+	case logger.LevelDebug:
+		return 7
+	case logger.LevelInfo:
+		return 6
+	case logger.LevelWarn:
+		return 4
+	case logger.LevelError:
+		return 3
+	default:
+		return 2
+	}
+}
+
 func prepareFieldName(field, fallback string) string {
 	if field == "" {
 		return inefficientlyEscapeJSON(fallback)
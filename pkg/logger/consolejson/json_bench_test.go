@@ -0,0 +1,15 @@
+package consolejson_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/iver-wharf/wharf-core/v2/internal/logbench"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger/consolejson"
+)
+
+func BenchmarkSink(b *testing.B) {
+	logbench.RunSinkBenchmarks(b, consolejson.New(consolejson.Config{
+		Writer: io.Discard,
+	}))
+}
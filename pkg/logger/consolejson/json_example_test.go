@@ -40,3 +40,96 @@ func ExampleTimeFormat() {
 	// {"level":"debug","message":"Sample message.","sample":1136171045}
 	// {"level":"debug","message":"Sample message.","sample":"3:04AM"}
 }
+
+func ExampleConfig_nestedObject() {
+	defer logger.ClearOutputs()
+	logger.AddOutput(logger.LevelDebug, consolejson.New(consolejson.Config{
+		DisableDate:   true,
+		DisableCaller: true,
+	}))
+
+	logger.New().Info().
+		WithObject("http", func(enc logger.ObjectEncoder) {
+			enc.AddString("method", "GET")
+			enc.AddInt("status", 200)
+			enc.AddObject("client", func(enc logger.ObjectEncoder) {
+				enc.AddString("ip", "127.0.0.1")
+			})
+		}).
+		WithObject("empty", func(logger.ObjectEncoder) {}).
+		Message("Sample message.")
+
+	// Output:
+	// {"level":"info","message":"Sample message.","http":{"method":"GET","status":200,"client":{"ip":"127.0.0.1"}},"empty":{}}
+}
+
+func ExampleGoogleCloudConfig() {
+	defer logger.ClearOutputs()
+	conf := consolejson.GoogleCloudConfig
+	conf.DisableDate = true
+	logger.AddOutput(logger.LevelDebug, consolejson.New(conf))
+
+	logger.NewScoped("GORM").Warn().Message("Sample message.")
+
+	// Output:
+	// {"severity":"WARNING","logging.googleapis.com/sourceLocation":{"file":"consolejson/json_example_test.go","line":"72"},"scope":"GORM","message":"Sample message."}
+}
+
+func ExampleConfig_indent() {
+	defer logger.ClearOutputs()
+	logger.AddOutput(logger.LevelDebug, consolejson.New(consolejson.Config{
+		DisableDate:   true,
+		DisableCaller: true,
+		Indent:        "  ",
+	}))
+
+	logger.New().Info().
+		WithObject("http", func(enc logger.ObjectEncoder) {
+			enc.AddString("method", "GET")
+		}).
+		Message("Sample message.")
+
+	// Output:
+	// {
+	//   "level": "info",
+	//   "message": "Sample message.",
+	//   "http": {
+	//     "method": "GET"
+	//   }
+	// }
+}
+
+func ExampleConfig_timeLocation() {
+	defer logger.ClearOutputs()
+	logger.AddOutput(logger.LevelDebug, consolejson.New(consolejson.Config{
+		DisableDate:   true,
+		DisableCaller: true,
+		TimeLocation:  time.UTC,
+	}))
+
+	t := time.Date(2006, 1, 2, 16, 4, 5, 0, time.FixedZone("CET", 60*60))
+	logger.New().Debug().WithTime("sample", t).Message("Sample message.")
+
+	// Output:
+	// {"level":"debug","message":"Sample message.","sample":"2006-01-02T15:04:05Z"}
+}
+
+func ExampleDurationFormat() {
+	defer logger.ClearOutputs()
+	logger.AddOutput(logger.LevelDebug, consolejson.New(consolejson.Config{
+		DisableDate:   true,
+		DisableCaller: true,
+	}))
+	logger.AddOutput(logger.LevelDebug, consolejson.New(consolejson.Config{
+		DisableDate:        true,
+		DisableCaller:      true,
+		TimeDurationFormat: consolejson.DurationString,
+		TimeDurationUnit:   10 * time.Millisecond, // rounds the duration
+	}))
+
+	logger.New().Debug().WithDuration("elapsed", 1523*time.Millisecond).Message("Sample message.")
+
+	// Output:
+	// {"level":"debug","message":"Sample message.","elapsed":1523000000}
+	// {"level":"debug","message":"Sample message.","elapsed":"1.52s"}
+}
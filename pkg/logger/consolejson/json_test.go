@@ -1,8 +1,15 @@
 package consolejson
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -47,6 +54,7 @@ func TestNew_defaults(t *testing.T) {
 	assert.Equal(t, "date", jsonSink.config.DateField)
 	assert.Equal(t, "message", jsonSink.config.MessageField)
 	assert.Equal(t, "level", jsonSink.config.LevelField)
+	assert.Equal(t, TimeRFC3339, jsonSink.config.TimeFormat)
 }
 
 func TestNew_escaping(t *testing.T) {
@@ -62,3 +70,206 @@ func TestNew_escaping(t *testing.T) {
 	assert.Equal(t, `\"simon says\"`, jsonSink.config.MessageField)
 	assert.Equal(t, `lävel`, jsonSink.config.LevelField)
 }
+
+func TestConfig_Writer(t *testing.T) {
+	var buf bytes.Buffer
+	jsonSink := New(Config{
+		Writer:        &buf,
+		DisableDate:   true,
+		DisableCaller: true,
+	})
+	jsonSink.NewContext("TEST").AppendInt("id", 1).WriteOut(logger.LevelInfo, "Sample message.")
+	assert.Equal(t, `{"level":"info","scope":"TEST","message":"Sample message.","id":1}`+"\n", buf.String())
+}
+
+// byteWriter writes one byte at a time, yielding in between, and is not safe
+// for concurrent use.
+type byteWriter struct {
+	buf []byte
+}
+
+func (w *byteWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		w.buf = append(w.buf, b)
+		runtime.Gosched()
+	}
+	return len(p), nil
+}
+
+func TestContext_WriteOut_concurrent(t *testing.T) {
+	w := &byteWriter{}
+	jsonSink := New(Config{Writer: w})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			jsonSink.NewContext("TEST").AppendInt("id", i).WriteOut(logger.LevelInfo, "Sample message.")
+		}(i)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(string(w.buf), "\n"), "\n")
+	assert.Len(t, lines, 20)
+	for _, line := range lines {
+		assert.True(t, json.Valid([]byte(line)), "valid JSON: %s", line)
+	}
+}
+
+func TestConfig_StaticFields(t *testing.T) {
+	var buf bytes.Buffer
+	jsonSink := New(Config{
+		Writer:        &buf,
+		DisableDate:   true,
+		DisableCaller: true,
+		StaticFields: map[string]any{
+			"service": "wharf-api",
+			"env":     "prod",
+			"replica": 2,
+			"tags":    []string{"a", "b"},
+		},
+	})
+	jsonSink.NewContext("TEST").AppendInt("id", 1).WriteOut(logger.LevelInfo, "Sample message.")
+	jsonSink.NewContext("").WriteOut(logger.LevelInfo, "")
+	assert.Equal(t,
+		`{"level":"info","scope":"TEST","env":"prod","replica":2,"service":"wharf-api","tags":["a","b"],"message":"Sample message.","id":1}`+"\n"+
+			`{"level":"info","env":"prod","replica":2,"service":"wharf-api","tags":["a","b"]}`+"\n",
+		buf.String())
+}
+
+func TestContext_SetStack(t *testing.T) {
+	var buf bytes.Buffer
+	jsonSink := New(Config{
+		Writer:        &buf,
+		DisableDate:   true,
+		DisableCaller: true,
+	})
+	jsonSink.NewContext("").(logger.StackContext).
+		SetStack([]logger.StackFrame{
+			{Function: "main.run", File: "/src/main.go", Line: 12},
+			{Function: "main.main", File: "/src/main.go", Line: 5},
+		}).
+		WriteOut(logger.LevelError, "Sample message.")
+	assert.Equal(t,
+		`{"level":"error","message":"Sample message.","stack":[`+
+			`{"function":"main.run","file":"/src/main.go","line":12},`+
+			`{"function":"main.main","file":"/src/main.go","line":5}]}`+"\n",
+		buf.String())
+}
+
+func TestConfig_LevelFormat(t *testing.T) {
+	var testCases = []struct {
+		name string
+		conf Config
+		want string
+	}{
+		{
+			name: "default",
+			conf: Config{},
+			want: `{"level":"warn"}`,
+		},
+		{
+			name: "uppercase",
+			conf: Config{LevelFormat: LevelFormatUppercase},
+			want: `{"level":"WARN"}`,
+		},
+		{
+			name: "syslog",
+			conf: Config{LevelFormat: LevelFormatSyslog},
+			want: `{"level":4}`,
+		},
+		{
+			name: "custom label",
+			conf: Config{
+				LevelFormat: LevelFormatSyslog,
+				LevelLabels: map[logger.Level]string{logger.LevelWarn: `"WARNING"`},
+			},
+			want: `{"level":"\"WARNING\""}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tc.conf.Writer = &buf
+			tc.conf.DisableDate = true
+			tc.conf.DisableCaller = true
+			New(tc.conf).NewContext("").WriteOut(logger.LevelWarn, "")
+			assert.Equal(t, tc.want+"\n", buf.String())
+		})
+	}
+}
+
+func TestConfig_fieldPlacement(t *testing.T) {
+	var testCases = []struct {
+		name string
+		conf Config
+		want string
+	}{
+		{
+			name: "default",
+			conf: Config{},
+			want: `{"level":"info","message":"Sample message.","error":"some error","b":"x:,{}","a":{"d":1,"c":2},"b":3}`,
+		},
+		{
+			name: "before message",
+			conf: Config{FieldsBeforeMessage: true},
+			want: `{"level":"info","b":"x:,{}","a":{"d":1,"c":2},"b":3,"message":"Sample message.","error":"some error"}`,
+		},
+		{
+			name: "sorted",
+			conf: Config{SortFields: true},
+			want: `{"level":"info","message":"Sample message.","error":"some error","a":{"d":1,"c":2},"b":"x:,{}","b":3}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tc.conf.Writer = &buf
+			tc.conf.DisableDate = true
+			tc.conf.DisableCaller = true
+			New(tc.conf).NewContext("").
+				SetError(errors.New("some error")).
+				AppendString("b", "x:,{}").(logger.ObjectContext).
+				AppendObject("a", func(enc logger.ObjectEncoder) {
+					enc.AddInt("d", 1)
+					enc.AddInt("c", 2)
+				}).
+				AppendInt("b", 3).
+				WriteOut(logger.LevelInfo, "Sample message.")
+			assert.Equal(t, tc.want+"\n", buf.String())
+		})
+	}
+}
+
+func TestConfig_CallerCombined(t *testing.T) {
+	var buf bytes.Buffer
+	New(Config{
+		Writer:         &buf,
+		DisableDate:    true,
+		CallerCombined: true,
+	}).NewContext("").SetCaller(`my "dir"/pretty.go`, 123).WriteOut(logger.LevelInfo, "")
+	assert.Equal(t, `{"level":"info","caller":"my \"dir\"/pretty.go:123"}`+"\n", buf.String())
+}
+
+func TestAppendEscapedString(t *testing.T) {
+	var testCases = []string{
+		"",
+		"foo bar",
+		`"quoted"`,
+		`back\slash`,
+		"new\nline\ttab\r",
+		"<html> & stuff",
+		"\x00\x1f control",
+		"nön-ASCII 日本語",
+		"line\u2028separator\u2029",
+		"invalid \xff UTF-8",
+	}
+	for _, tc := range testCases {
+		t.Run(tc, func(t *testing.T) {
+			want, err := json.Marshal(tc)
+			assert.NoError(t, err)
+			assert.Equal(t, string(want), string(appendEscapedString(nil, tc)))
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package consolepretty
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// ColorMode decides whether the pretty-console logger writes colored output.
+type ColorMode byte
+
+const (
+	// ColorModeAuto writes colored output only if the writer is a terminal and
+	// the NO_COLOR environment variable is unset or empty. See
+	// https://no-color.org/
+	ColorModeAuto ColorMode = iota
+	// ColorModeAlways writes colored output regardless of the writer and the
+	// environment.
+	ColorModeAlways
+	// ColorModeNever never writes colored output.
+	ColorModeNever
+)
+
+// EnvNoColor is the environment variable that, when set to any non-empty
+// value, disables coloring when using ColorModeAuto.
+const EnvNoColor = "NO_COLOR"
+
+func (m ColorMode) enabled(w io.Writer) bool {
+	switch m {
+	case ColorModeAlways:
+		return true
+	case ColorModeNever:
+		return false
+	default:
+		return os.Getenv(EnvNoColor) == "" && isTerminal(w)
+	}
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(fdWriter)
+	if !ok {
+		return false
+	}
+	fd := f.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// ansiSequence is the precomputed escape codes written before and after a
+// colored value, so that writing colored values does not have to go through
+// the allocating color.Color.Fprint.
+type ansiSequence struct {
+	prefix string
+	suffix string
+}
+
+type ansiCacheKey struct {
+	color   *color.Color
+	noColor bool
+}
+
+var (
+	ansiCacheMu sync.RWMutex
+	ansiCache   = map[ansiCacheKey]ansiSequence{}
+)
+
+// ansiSentinel is a value that is never altered by the color package, used to
+// split the colored output into its prefix and suffix.
+const ansiSentinel = "\x00"
+
+// getANSISequence returns the escape codes for the color, regardless of the
+// global color.NoColor value. A color that has explicitly been disabled via
+// color.Color.DisableColor results in no escape codes, but this can only be
+// detected while color.NoColor is false.
+//
+// The result is cached per color, so any changes to a color, such as via
+// color.Color.Add, after it has been used for logging are not picked up.
+func getANSISequence(c *color.Color) ansiSequence {
+	if c == nil {
+		return ansiSequence{}
+	}
+	key := ansiCacheKey{c, color.NoColor}
+	ansiCacheMu.RLock()
+	seq, ok := ansiCache[key]
+	ansiCacheMu.RUnlock()
+	if ok {
+		return seq
+	}
+	if color.NoColor || c.Sprint(ansiSentinel) != ansiSentinel {
+		enabled := *c
+		enabled.EnableColor()
+		colored := enabled.Sprint(ansiSentinel)
+		if i := strings.Index(colored, ansiSentinel); i != -1 {
+			seq = ansiSequence{
+				prefix: colored[:i],
+				suffix: colored[i+len(ansiSentinel):],
+			}
+		}
+	}
+	ansiCacheMu.Lock()
+	ansiCache[key] = seq
+	ansiCacheMu.Unlock()
+	return seq
+}
+
+func (c context) ansiSequence(col *color.Color) ansiSequence {
+	if c.noColor {
+		return ansiSequence{}
+	}
+	return getANSISequence(col)
+}
+
+func (c context) writeColored(buf *bytes.Buffer, col *color.Color, values ...string) {
+	seq := c.ansiSequence(col)
+	buf.WriteString(seq.prefix)
+	for _, v := range values {
+		buf.WriteString(v)
+	}
+	buf.WriteString(seq.suffix)
+}
@@ -0,0 +1,63 @@
+package consolepretty
+
+// Segment is a part of a log line, used to define the order and presence of
+// the parts via Config.Layout.
+type Segment byte
+
+const (
+	// SegmentPrefix is the Config.Prefix string. It is not separated by a
+	// space from the next segment.
+	SegmentPrefix Segment = iota + 1
+	// SegmentDate is the timestamp of when the message was logged.
+	SegmentDate
+	// SegmentLevel is the logging level, such as "INFO".
+	SegmentLevel
+	// SegmentScope is the scope of the logger, as given to logger.NewScoped.
+	SegmentScope
+	// SegmentCaller is the caller file name and line number.
+	SegmentCaller
+	// SegmentMessage is the logged message.
+	SegmentMessage
+	// SegmentFields is all the fields added via the Event.With* methods,
+	// followed by the error added via Event.WithError.
+	SegmentFields
+)
+
+// DefaultLayout is the layout used in New if Config.Layout and
+// DefaultConfig.Layout are both left unset.
+var DefaultLayout = []Segment{
+	SegmentPrefix,
+	SegmentDate,
+	SegmentLevel,
+	SegmentScope,
+	SegmentCaller,
+	SegmentMessage,
+	SegmentFields,
+}
+
+// isHeader returns true for the segments that are grouped together inside
+// square brackets when placed next to each other in the layout.
+func (s Segment) isHeader() bool {
+	return s == SegmentLevel || s == SegmentScope || s == SegmentCaller
+}
+
+func hasSegment(layout []Segment, seg Segment) bool {
+	for _, s := range layout {
+		if s == seg {
+			return true
+		}
+	}
+	return false
+}
+
+// separator returns the string written between two segments.
+func separator(prev, next Segment) string {
+	switch {
+	case prev == 0, prev == SegmentPrefix:
+		return ""
+	case next == SegmentFields && (prev == SegmentMessage || prev == SegmentFields):
+		return "  "
+	default:
+		return " "
+	}
+}
@@ -4,17 +4,75 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unicode/utf8"
 
 	"github.com/fatih/color"
+	"github.com/iver-wharf/wharf-core/v2/internal/traceutil"
 	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
 	"github.com/mattn/go-colorable"
 )
 
-// ColorConfig lets you gradually configure the coloring of the logger.
+// CallerMode specifies how the caller file is displayed.
+type CallerMode byte
+
+const (
+	// CallerModeShort displays the file name with its direct parent
+	// directory, such as "logger/logger.go".
+	CallerModeShort CallerMode = iota
+	// CallerModeBase displays only the file name, such as "logger.go".
+	CallerModeBase
+	// CallerModePackage displays the file name prefixed with the Go package
+	// path of the calling function, such as
+	// "github.com/iver-wharf/wharf-core/v2/pkg/logger/logger.go".
+	CallerModePackage
+	// CallerModeFull displays the full file path, such as
+	// "/home/user/wharf-core/pkg/logger/logger.go".
+	CallerModeFull
+)
+
+func (m CallerMode) file(frame logger.StackFrame) string {
+	switch m {
+	case CallerModeBase:
+		return filepath.Base(frame.File)
+	case CallerModePackage:
+		if pkg := packagePath(frame.Function); pkg != "" {
+			return pkg + "/" + filepath.Base(frame.File)
+		}
+		return traceutil.FileAndLastDir(frame.File)
+	case CallerModeFull:
+		return frame.File
+	default:
+		return traceutil.FileAndLastDir(frame.File)
+	}
+}
+
+// packagePath returns the package path of the fully qualified function name,
+// such as "github.com/iver-wharf/wharf-core/v2/pkg/logger" from
+// "github.com/iver-wharf/wharf-core/v2/pkg/logger.(*Mock).Debug".
+func packagePath(function string) string {
+	lastSlash := strings.LastIndexByte(function, '/')
+	if lastSlash == -1 {
+		lastSlash = 0
+	}
+	if dot := strings.IndexByte(function[lastSlash:], '.'); dot != -1 {
+		return function[:lastSlash+dot]
+	}
+	return ""
+}
+
+// ColorConfig lets you gradually configure the coloring of the logger. Any nil
+// color is written without any formatting.
+//
+// The presets DarkColorConfig, LightColorConfig, and MonochromeColorConfig
+// can be used as-is, or as a base for a custom theme.
 type ColorConfig struct {
 	// Date sets the color attributes for the timestamp of the logs.
 	Date *color.Color
@@ -84,12 +142,29 @@ type ColorConfig struct {
 	// ErrorType sets the color attributes for the error type of the error
 	// added via Event.WithError method for the logs.
 	ErrorType *color.Color
+	// StackFunction sets the color attributes for the function names of the
+	// stack trace added via the Event.WithStack method for the logs.
+	StackFunction *color.Color
+	// StackFile sets the color attributes for the file paths of the stack
+	// trace added via the Event.WithStack method for the logs.
+	StackFile *color.Color
+	// StackDelimiter sets the color attributes for the delimiter between the
+	// file paths and line numbers of the stack trace added via the
+	// Event.WithStack method for the logs.
+	StackDelimiter *color.Color
+	// StackLine sets the color attributes for the line numbers of the stack
+	// trace added via the Event.WithStack method for the logs.
+	StackLine *color.Color
 }
 
 // DefaultColorConfig is the config used in New to populate some values if left
 // unset. Changing this global value also changes the fallback values used in
-// New.
-var DefaultColorConfig = ColorConfig{
+// New. Defaults to DarkColorConfig.
+var DefaultColorConfig = DarkColorConfig
+
+// DarkColorConfig is a color theme preset meant for terminals with a dark
+// background.
+var DarkColorConfig = ColorConfig{
 	Date:                color.New(color.FgHiBlack),
 	Scope:               color.New(color.FgCyan, color.Bold),
 	CallerFile:          color.New(color.FgHiBlack),
@@ -114,6 +189,71 @@ var DefaultColorConfig = ColorConfig{
 	ErrorDelimiter:      color.New(color.FgRed, color.Italic),
 	ErrorValue:          color.New(color.FgHiRed),
 	ErrorType:           color.New(color.FgRed, color.Italic),
+	StackFunction:       color.New(color.FgWhite),
+	StackFile:           color.New(color.FgHiBlack),
+	StackDelimiter:      color.New(color.FgHiBlack),
+	StackLine:           color.New(color.FgHiBlack),
+}
+
+// LightColorConfig is a color theme preset meant for terminals with a light
+// background.
+var LightColorConfig = ColorConfig{
+	Date:                color.New(color.FgHiBlack),
+	Scope:               color.New(color.FgBlue, color.Bold),
+	CallerFile:          color.New(color.FgHiBlack),
+	CallerDelimiter:     color.New(color.FgHiBlack),
+	CallerLine:          color.New(color.FgHiBlack),
+	PreMessageDelimiter: color.New(color.FgBlack),
+	MessageDebug:        color.New(color.FgHiBlack, color.Italic),
+	MessageInfo:         color.New(color.FgBlack),
+	MessageWarn:         color.New(color.FgYellow),
+	MessageError:        color.New(color.FgRed),
+	MessagePanic:        color.New(color.FgRed, color.Bold),
+	LevelDebug:          color.New(color.FgHiBlack, color.Italic),
+	LevelInfo:           color.New(color.FgGreen),
+	LevelWarn:           color.New(color.FgYellow, color.Bold),
+	LevelError:          color.New(color.FgRed, color.Bold),
+	LevelPanic:          color.New(color.FgHiWhite, color.BgRed, color.Bold),
+	FieldKey:            color.New(color.FgHiBlack, color.Italic),
+	FieldDelimiter:      color.New(color.FgHiBlack, color.Italic),
+	FieldValue:          color.New(color.FgBlack),
+	FieldValueZero:      color.New(color.FgHiBlack, color.Italic),
+	ErrorKey:            color.New(color.FgRed, color.Italic, color.Bold),
+	ErrorDelimiter:      color.New(color.FgRed, color.Italic),
+	ErrorValue:          color.New(color.FgRed),
+	ErrorType:           color.New(color.FgRed, color.Italic),
+	StackFunction:       color.New(color.FgBlack),
+	StackFile:           color.New(color.FgHiBlack),
+	StackDelimiter:      color.New(color.FgHiBlack),
+	StackLine:           color.New(color.FgHiBlack),
+}
+
+// MonochromeColorConfig is a color theme preset that only uses text
+// attributes such as bold and italic, and no colors. The nil values are
+// written without any formatting.
+var MonochromeColorConfig = ColorConfig{
+	Date:           color.New(color.Faint),
+	Scope:          color.New(color.Bold),
+	CallerFile:     color.New(color.Faint),
+	CallerLine:     color.New(color.Faint),
+	MessageDebug:   color.New(color.Italic),
+	MessageWarn:    color.New(color.Bold),
+	MessageError:   color.New(color.Bold),
+	MessagePanic:   color.New(color.Bold, color.Underline),
+	LevelDebug:     color.New(color.Italic),
+	LevelWarn:      color.New(color.Bold),
+	LevelError:     color.New(color.Bold),
+	LevelPanic:     color.New(color.ReverseVideo, color.Bold),
+	FieldKey:       color.New(color.Faint, color.Italic),
+	FieldDelimiter: color.New(color.Faint, color.Italic),
+	FieldValueZero: color.New(color.Faint, color.Italic),
+	ErrorKey:       color.New(color.Bold, color.Italic),
+	ErrorDelimiter: color.New(color.Italic),
+	ErrorValue:     color.New(color.Bold),
+	ErrorType:      color.New(color.Italic),
+	StackFile:      color.New(color.Faint),
+	StackDelimiter: color.New(color.Faint),
+	StackLine:      color.New(color.Faint),
 }
 
 // Config lets you gradually configure the output of the logger by disabling
@@ -122,15 +262,46 @@ type Config struct {
 	// Writer is the io.Writer target that the pretty-console logger will write
 	// to. Defaults to using a github.com/mattn/go-colorable wrapper around
 	// os.Stdout.
+	//
+	// The writes are serialized by the Sink so that log events from concurrent
+	// goroutines never interleave mid-line, even if the writer is not safe for
+	// concurrent use, such as a plain os.File.
 	Writer io.Writer
 
 	// Coloring defines how certain parts of the logs are colored.
 	Coloring *ColorConfig
 
+	// ColorMode decides if the logs are colored at all. By default the logs
+	// are only colored if the Writer is a terminal and the NO_COLOR
+	// environment variable is unset or empty.
+	//
+	// The global color.NoColor value from the github.com/fatih/color package
+	// is not used.
+	ColorMode ColorMode
+
 	// DateFormat is the format to display the timestamp of when a logged
 	// message was logged. This does not alter how Event.WithTime is rendered.
 	DateFormat string
 
+	// TimeFormat is the format to display the time.Time fields added via
+	// Event.WithTime, using the same layout syntax as time.Time.Format. This
+	// does not alter how the date of the log is rendered.
+	//
+	// When set to time.RFC3339:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.  since=2006-01-02T15:04:05Z
+	// When set to time.Kitchen:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.  since=3:04PM
+	TimeFormat string
+
+	// TimeDurationUnit rounds the time.Duration fields added via
+	// Event.WithDuration to this unit, if set to a value of 1 or higher.
+	//
+	// When set to 0:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.  elapsed=1.523456s
+	// When set to time.Millisecond*10:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.  elapsed=1.52s
+	TimeDurationUnit time.Duration
+
 	// Prefix sets an optional string added to the beginning of the log message.
 	//
 	// When set to "" (empty string):
@@ -165,6 +336,19 @@ type Config struct {
 	// 	Jan 02 15:04Z [INFO |example.go] Sample message.
 	DisableCallerLine bool
 
+	// CallerMode decides how the caller file is displayed. Defaults to
+	// CallerModeShort.
+	//
+	// When set to CallerModeShort:
+	// 	Jan 02 15:04Z [INFO |logger/example.go:20] Sample message.
+	// When set to CallerModeBase:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.
+	// When set to CallerModePackage:
+	// 	Jan 02 15:04Z [INFO |github.com/iver-wharf/wharf-core/v2/pkg/logger/example.go:20] Sample message.
+	// When set to CallerModeFull:
+	// 	Jan 02 15:04Z [INFO |/home/user/wharf-core/pkg/logger/example.go:20] Sample message.
+	CallerMode CallerMode
+
 	// DisableScope removes the log scope from the log when set to true.
 	//
 	// When set to false:
@@ -174,7 +358,7 @@ type Config struct {
 	DisableScope bool
 
 	// Ellipsis defines the string used when trimming the values, as an effect
-	// of the caller or scope max length configs.
+	// of the caller, scope, or field value max length configs.
 	//
 	// Setting this to a value longer than the max length is considered
 	// undefined behavior, and should be avoided.
@@ -230,6 +414,177 @@ type Config struct {
 	// 	Jan 02 15:04Z [INFO |GORM      ] Sample message.
 	// 	Jan 02 15:04Z [INFO |GORM-debug] Sample message.
 	ScopeMinLengthAuto bool
+
+	// MessageMinLength will pad the message with spaces so that it reaches the
+	// target character width, so that the fields start at the same column on
+	// consecutive log lines.
+	//
+	// When set to 0:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Build started.  buildId=12
+	// 	Jan 02 15:04Z [INFO |example.go:20] Done.  buildId=12
+	// When set to 16:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Build started.    buildId=12
+	// 	Jan 02 15:04Z [INFO |example.go:20] Done.             buildId=12
+	MessageMinLength int
+
+	// Layout sets the order and presence of the segments of each log line.
+	// Consecutive level, scope, and caller segments are grouped together
+	// inside square brackets. Segments left out are not written. Defaults to
+	// DefaultLayout.
+	//
+	// When set to DefaultLayout:
+	// 	Jan 02 15:04Z [INFO |MY-SCOPE|example.go:20] Sample message.  foo=bar
+	// When set to:
+	// 	[]Segment{SegmentDate, SegmentScope, SegmentLevel, SegmentMessage,
+	// 		SegmentFields, SegmentCaller}
+	// Then it becomes:
+	// 	Jan 02 15:04Z [MY-SCOPE|INFO ] Sample message.  foo=bar [example.go:20]
+	Layout []Segment
+
+	// LevelLabels overrides the printed string of specific logging levels,
+	// such as shorter labels for narrow terminals, or localized labels. Any
+	// logging levels missing from the map use the default labels "DEBUG",
+	// "INFO", "WARN", "ERROR", and "PANIC".
+	//
+	// When set to nil:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.
+	// When set to map[logger.Level]string{logger.LevelInfo: "INF"}:
+	// 	Jan 02 15:04Z [INF  |example.go:20] Sample message.
+	LevelLabels map[logger.Level]string
+
+	// LevelMinLength will pad the level labels with spaces so that they reach
+	// the target character width. When set to 0, the labels are padded to the
+	// width of the longest label. Set to a negative value to disable padding.
+	//
+	// When set to 0:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.
+	// 	Jan 02 15:04Z [DEBUG|example.go:20] Sample message.
+	// When set to -1:
+	// 	Jan 02 15:04Z [INFO|example.go:20] Sample message.
+	// 	Jan 02 15:04Z [DEBUG|example.go:20] Sample message.
+	LevelMinLength int
+
+	// SortFields prints the fields added via the Event.With* methods sorted by
+	// their keys when set to true, instead of in the order they were added.
+	//
+	// When set to false:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.  b=2  a=1
+	// When set to true:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.  a=1  b=2
+	SortFields bool
+
+	// PriorityFields is a list of field keys that are always printed first,
+	// in the given order, before any other fields. Useful to keep important
+	// context visible before the log line gets visually long.
+	//
+	// When set to nil:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.  a=1  reqId=3
+	// When set to []string{"reqId"}:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.  reqId=3  a=1
+	PriorityFields []string
+
+	// MultilineFields prints each field on its own indented line below the
+	// message when set to true, regardless of where the fields are placed in
+	// the Layout. Useful for wide values, such as SQL queries.
+	//
+	// When set to false:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.  a=1  b=2
+	// When set to true:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.
+	// 		a=1
+	// 		b=2
+	MultilineFields bool
+
+	// MultilineFieldsWidth automatically prints the fields on their own lines,
+	// as with MultilineFields, for log lines that would otherwise be wider
+	// than this number of characters, if set to a value of 1 or higher.
+	MultilineFieldsWidth int
+
+	// WrapWidth soft-wraps the message and fields at the spaces between words
+	// to fit the log line within this number of characters, if set to a value
+	// of 1 or higher, instead of letting the terminal break the lines
+	// mid-word. The continuation lines are indented to line up with the start
+	// of the message. Fields and stack traces already printed on their own
+	// lines are not wrapped.
+	//
+	// When set to 0:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message that is long.  a=1  b=2
+	// When set to 55:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message that
+	// 	                                    is long.  a=1  b=2
+	WrapWidth int
+
+	// WrapWidthAuto overrides the WrapWidth with the width of the terminal
+	// when set to true, as obtained on each written log line from the Writer
+	// if it is a terminal, or else from the COLUMNS environment variable. The
+	// WrapWidth is used as a fallback if neither are available.
+	WrapWidthAuto bool
+
+	// DisableErrorStack disables printing the stack trace carried by the
+	// error added via Event.WithError, when no stack trace has been added via
+	// Event.WithStack. See logger.ErrorStack for which errors carry a stack
+	// trace.
+	DisableErrorStack bool
+
+	// Formatters overrides how field values of specific types are printed,
+	// such as time.Time, time.Duration, or float64 values. The returned string
+	// is printed as-is, without any escaping. Values of types missing from the
+	// map are printed using the default formatting.
+	//
+	// When set to nil:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.  ratio=0.3333333333333333
+	// When set to:
+	// 	map[reflect.Type]func(any) string{
+	// 		reflect.TypeOf(float64(0)): func(v any) string {
+	// 			return strconv.FormatFloat(v.(float64), 'f', 2, 64)
+	// 		},
+	// 	}
+	// Then it becomes:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.  ratio=0.33
+	Formatters map[reflect.Type]func(any) string
+
+	// FieldColorFunc overrides the color of specific field values, such as to
+	// highlight error status codes or SQL statements. The function is called
+	// for every field, and returning nil falls back to the ColorConfig
+	// coloring.
+	//
+	// When set to:
+	// 	func(key string, value any) *color.Color {
+	// 		if status, ok := value.(int); ok && key == "status" && status >= 500 {
+	// 			return color.New(color.FgHiRed, color.Bold)
+	// 		}
+	// 		return nil
+	// 	}
+	// Then the value of "status=503" is printed in bold red, while the value
+	// of "status=200" is printed using ColorConfig.FieldValue.
+	FieldColorFunc func(key string, value any) *color.Color
+
+	// SlowDuration colors any duration field value, as added via
+	// Event.WithDuration, that is equal to or longer than this duration using
+	// the ColorConfig.LevelWarn color, if set to a value higher than 0. Useful
+	// to make slow SQL queries or HTTP requests stand out. The FieldColorFunc
+	// takes precedence over this.
+	//
+	// When set to 0:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.  elapsed=1.5s
+	// When set to time.Second:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.  elapsed=1.5s (in yellow)
+	SlowDuration time.Duration
+
+	// FieldValueMaxLength will trim string field values, and the values
+	// returned by Formatters, down to this length using the Ellipsis if set to
+	// a value of 1 or higher. Useful for long values such as SQL statements or
+	// payload dumps.
+	//
+	// When set to 0:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.  sql=“SELECT * FROM build”
+	// When set to 10:
+	// 	Jan 02 15:04Z [INFO |example.go:20] Sample message.  sql=“SELECT * …”
+	FieldValueMaxLength int
+
+	// levelLabels is the padded labels of each logging level, where the last
+	// item is used for unknown logging levels.
+	levelLabels [logger.LevelSilence + 1]string
 }
 
 // DefaultConfig is the config used in New to populate some values if left
@@ -238,6 +593,7 @@ type Config struct {
 var DefaultConfig = Config{
 	Ellipsis:           "…",
 	DateFormat:         "Jan-02 15:04Z0700",
+	TimeFormat:         time.RFC3339,
 	CallerMaxLength:    23,
 	CallerMinLength:    23,
 	ScopeMinLengthAuto: true,
@@ -252,15 +608,18 @@ var Default = New(DefaultConfig)
 //
 // 	Config.Writer = DefaultConfig.Writer
 // 	Config.DateFormat = DefaultConfig.DateFormat
+// 	Config.TimeFormat = DefaultConfig.TimeFormat
+// 	Config.Layout = DefaultConfig.Layout
 //
 // 	Config.Coloring = DefaultColorConfig
 func New(conf Config) logger.Sink {
 	if conf.Writer == nil {
-		if DefaultConfig.Writer == nil {
-			conf.Writer = colorable.NewColorableStdout()
-		} else {
-			conf.Writer = DefaultConfig.Writer
-		}
+		conf.Writer = DefaultConfig.Writer
+	}
+	colorWriter := conf.Writer
+	if conf.Writer == nil {
+		conf.Writer = colorable.NewColorableStdout()
+		colorWriter = os.Stdout
 	}
 	if conf.Coloring == nil {
 		conf.Coloring = &DefaultColorConfig
@@ -268,18 +627,34 @@ func New(conf Config) logger.Sink {
 	if conf.DateFormat == "" {
 		conf.DateFormat = DefaultConfig.DateFormat
 	}
+	if conf.TimeFormat == "" {
+		conf.TimeFormat = DefaultConfig.TimeFormat
+	}
 	if conf.Ellipsis == "" {
 		conf.Ellipsis = DefaultConfig.Ellipsis
 	}
+	if len(conf.Layout) == 0 {
+		conf.Layout = DefaultConfig.Layout
+	}
+	conf.levelLabels = padLevelLabels(&conf)
+	terminal, _ := colorWriter.(fdWriter)
 	return sink{
 		config:      &conf,
 		ellipsisLen: utf8.RuneCountInString(conf.Ellipsis),
+		noColor:     !conf.ColorMode.enabled(colorWriter),
+		terminal:    terminal,
+		mu:          &sync.Mutex{},
 	}
 }
 
 type sink struct {
 	config      *Config
 	ellipsisLen int
+	// mu serializes the writes to the Config.Writer, so that log events from
+	// concurrent goroutines never interleave mid-line.
+	mu       *sync.Mutex
+	noColor  bool
+	terminal fdWriter
 }
 
 // NewContext creates a new pretty-console logging Context using the
@@ -289,6 +664,9 @@ func (s sink) NewContext(scope string) logger.Context {
 		Config:      s.config,
 		scope:       scope,
 		ellipsisLen: s.ellipsisLen,
+		noColor:     s.noColor,
+		terminal:    s.terminal,
+		mu:          s.mu,
 	}
 }
 
@@ -299,7 +677,11 @@ type context struct {
 	callerFile  string
 	callerLine  int
 	err         error
+	stack       []logger.StackFrame
 	ellipsisLen int
+	noColor     bool
+	terminal    fdWriter
+	mu          *sync.Mutex
 }
 
 type fieldPair struct {
@@ -307,71 +689,315 @@ type fieldPair struct {
 	value any
 }
 
+// maxPooledBufferSize is the largest buffer capacity that is returned to the
+// pool, so that a single huge log message does not keep its memory allocated.
+const maxPooledBufferSize = 64 << 10
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
 func (c context) WriteOut(level logger.Level, message string) {
-	var buf bytes.Buffer
-	var coloring = c.Coloring
-	if c.Prefix != "" {
-		buf.WriteString(c.Prefix)
-	}
-	if !c.DisableDate {
-		coloring.Date.Fprint(&buf, time.Now().Format(c.DateFormat))
-		buf.WriteRune(' ')
-	}
-	coloring.PreMessageDelimiter.Fprint(&buf, "[")
-	c.writeLevel(&buf, level)
-	c.writeScope(&buf)
-	c.writeCaller(&buf)
-	coloring.PreMessageDelimiter.Fprint(&buf, "]")
-	buf.WriteRune(' ')
-	needsSeparator := false
-	if message != "" {
-		c.writeMessage(&buf, level, message)
-		needsSeparator = true
-	}
-	for _, pair := range c.fields {
-		if needsSeparator {
-			buf.WriteString("  ")
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer putBuffer(buf)
+	now := time.Now()
+	multiline := c.MultilineFields
+	bodyStart := c.writeLine(buf, now, level, message, multiline)
+	if !multiline && c.MultilineFieldsWidth > 0 && c.hasFields() &&
+		maxVisibleLineWidth(buf.Bytes()) > c.MultilineFieldsWidth {
+		buf.Reset()
+		bodyStart = c.writeLine(buf, now, level, message, true)
+	}
+	if width := c.wrapWidth(); width > 0 && bodyStart != -1 &&
+		maxVisibleLineWidth(buf.Bytes()) > width {
+		wrapped := bufferPool.Get().(*bytes.Buffer)
+		defer putBuffer(wrapped)
+		softWrap(wrapped, buf.Bytes(), bodyStart, width)
+		buf = wrapped
+	}
+	c.writeStack(buf)
+	buf.WriteByte('\n')
+	c.mu.Lock()
+	buf.WriteTo(c.Writer)
+	c.mu.Unlock()
+}
+
+// writeLine writes the log line, without the stack trace, and returns the
+// index of where the message or fields start in the buffer, or -1 if neither
+// were written on the first line.
+func (c context) writeLine(buf *bytes.Buffer, now time.Time, level logger.Level, message string, multiline bool) int {
+	w := lineWriter{buf: buf, multiline: multiline, bodyStart: -1}
+	layout := c.Layout
+	if len(layout) == 0 {
+		layout = DefaultLayout
+	}
+	for i := 0; i < len(layout); i++ {
+		switch seg := layout[i]; seg {
+		case SegmentPrefix:
+			if c.Prefix != "" {
+				w.begin(seg)
+				buf.WriteString(c.Prefix)
+			}
+		case SegmentDate:
+			if !c.DisableDate {
+				w.begin(seg)
+				c.writeDate(buf, now)
+			}
+		case SegmentLevel, SegmentScope, SegmentCaller:
+			end := i + 1
+			for end < len(layout) && layout[end].isHeader() {
+				end++
+			}
+			c.writeHeader(&w, level, layout[i:end])
+			i = end - 1
+		case SegmentMessage:
+			pad := c.MessageMinLength > 0 && !multiline && c.hasFields() &&
+				hasSegment(layout[i+1:], SegmentFields)
+			if message != "" || pad {
+				w.begin(seg)
+				c.writeMessage(buf, level, message)
+			}
+			if pad {
+				writePadding(buf, lastLineWidth(message), c.MessageMinLength)
+			}
+		case SegmentFields:
+			if !multiline {
+				c.writeFields(&w)
+			}
 		}
-		coloring.FieldKey.Fprint(&buf, pair.key)
-		coloring.FieldDelimiter.Fprint(&buf, "=")
-		str, hasValue := getPrintableStringRepresentation(pair.value)
-		if hasValue {
-			coloring.FieldValue.Fprint(&buf, str)
+	}
+	if multiline {
+		c.writeFields(&w)
+	}
+	return w.bodyStart
+}
+
+// lineWriter keeps track of the previously written segment, to write the
+// correct separator before the next one.
+type lineWriter struct {
+	buf  *bytes.Buffer
+	prev Segment
+	// multiline writes each field on its own indented line.
+	multiline bool
+	// bodyStart is the index of where the message or fields start in the
+	// buffer, or -1 if neither has been written yet.
+	bodyStart int
+}
+
+func (w *lineWriter) beginField() {
+	if w.multiline {
+		w.buf.WriteString("\n\t")
+		return
+	}
+	w.begin(SegmentFields)
+}
+
+func (w *lineWriter) begin(seg Segment) {
+	w.buf.WriteString(separator(w.prev, seg))
+	w.prev = seg
+	if w.bodyStart == -1 && (seg == SegmentMessage || seg == SegmentFields) {
+		w.bodyStart = w.buf.Len()
+	}
+}
+
+// writeStack writes the stack trace, if any, as indented lines below the log
+// line, in the same style as the stack traces of Go panics.
+func (c context) writeStack(buf *bytes.Buffer) {
+	stack := c.stack
+	if stack == nil && c.err != nil && !c.DisableErrorStack {
+		stack = logger.ErrorStack(c.err)
+	}
+	for _, frame := range stack {
+		buf.WriteString("\n\t")
+		c.writeColored(buf, c.Coloring.StackFunction, frame.Function)
+		buf.WriteString("\n\t\t")
+		c.writeColored(buf, c.Coloring.StackFile, frame.File)
+		c.writeColored(buf, c.Coloring.StackDelimiter, ":")
+		var scratch [20]byte
+		seq := c.ansiSequence(c.Coloring.StackLine)
+		buf.WriteString(seq.prefix)
+		buf.Write(strconv.AppendInt(scratch[:0], int64(frame.Line), 10))
+		buf.WriteString(seq.suffix)
+	}
+}
+
+func (c context) writeDate(buf *bytes.Buffer, now time.Time) {
+	var scratch [64]byte
+	seq := c.ansiSequence(c.Coloring.Date)
+	buf.WriteString(seq.prefix)
+	buf.Write(now.AppendFormat(scratch[:0], c.DateFormat))
+	buf.WriteString(seq.suffix)
+}
+
+// writeHeader writes the consecutive level, scope, and caller segments inside
+// square brackets, delimited by pipes. Nothing is written if none of the
+// segments have any content.
+func (c context) writeHeader(w *lineWriter, level logger.Level, segments []Segment) {
+	wroteAny := false
+	for _, seg := range segments {
+		if !c.hasHeaderContent(seg) {
+			continue
+		}
+		if wroteAny {
+			c.writeColored(w.buf, c.Coloring.PreMessageDelimiter, "|")
 		} else {
-			coloring.FieldValueZero.Fprint(&buf, str)
+			w.begin(seg)
+			c.writeColored(w.buf, c.Coloring.PreMessageDelimiter, "[")
+			wroteAny = true
+		}
+		switch seg {
+		case SegmentLevel:
+			c.writeLevel(w.buf, level)
+		case SegmentScope:
+			c.writeScopeValue(w.buf)
+		case SegmentCaller:
+			c.writeCallerValue(w.buf)
+		}
+	}
+	if wroteAny {
+		c.writeColored(w.buf, c.Coloring.PreMessageDelimiter, "]")
+	}
+}
+
+func (c context) hasHeaderContent(seg Segment) bool {
+	switch seg {
+	case SegmentLevel:
+		return true
+	case SegmentScope:
+		return c.hasScope()
+	case SegmentCaller:
+		return c.hasCaller()
+	default:
+		return false
+	}
+}
+
+func (c context) writeFields(w *lineWriter) {
+	for _, key := range c.PriorityFields {
+		for _, pair := range c.fields {
+			if pair.key == key {
+				c.writeField(w, pair)
+			}
+		}
+	}
+	fields := c.fields
+	if c.SortFields && len(fields) > 1 {
+		fields = make([]fieldPair, len(c.fields))
+		copy(fields, c.fields)
+		sort.SliceStable(fields, func(i, j int) bool {
+			return fields[i].key < fields[j].key
+		})
+	}
+	for _, pair := range fields {
+		if !c.isPriorityField(pair.key) {
+			c.writeField(w, pair)
 		}
-		needsSeparator = true
 	}
 	if c.err != nil {
-		if needsSeparator {
-			buf.WriteString("  ")
+		w.beginField()
+		c.writeColored(w.buf, c.Coloring.ErrorKey, "error")
+		c.writeColored(w.buf, c.Coloring.ErrorDelimiter, "=")
+		c.writeColored(w.buf, c.Coloring.ErrorValue, printableString(strings.TrimSpace(c.err.Error())))
+		w.buf.WriteByte(' ')
+		seq := c.ansiSequence(c.Coloring.ErrorType)
+		w.buf.WriteString(seq.prefix)
+		fmt.Fprintf(w.buf, "(%T)", c.err)
+		w.buf.WriteString(seq.suffix)
+	}
+}
+
+func (c context) writeField(w *lineWriter, pair fieldPair) {
+	w.beginField()
+	c.writeColored(w.buf, c.Coloring.FieldKey, pair.key)
+	c.writeColored(w.buf, c.Coloring.FieldDelimiter, "=")
+	c.writeFieldValue(w.buf, pair.key, pair.value)
+}
+
+func (c context) isPriorityField(key string) bool {
+	for _, priorityKey := range c.PriorityFields {
+		if key == priorityKey {
+			return true
 		}
-		coloring.ErrorKey.Fprint(&buf, "error")
-		coloring.ErrorDelimiter.Fprint(&buf, "=")
-		str, _ := getPrintableStringRepresentation(strings.TrimSpace(c.err.Error()))
-		coloring.ErrorValue.Fprint(&buf, str)
-		buf.WriteRune(' ')
-		coloring.ErrorType.Fprintf(&buf, "(%T)", c.err)
 	}
-	buf.WriteRune('\n')
-	io.Copy(c.Writer, &buf)
+	return false
 }
 
-func getPrintableStringRepresentation(value any) (str string, hasValue bool) {
-	if value == nil {
-		return "<nil>", false
+// writeFieldValue writes the field value without first formatting it into a
+// separate string, where nil and empty strings are colored as zero-values.
+func (c context) writeFieldValue(buf *bytes.Buffer, key string, value any) {
+	valueColor, zeroColor := c.Coloring.FieldValue, c.Coloring.FieldValueZero
+	if d, ok := value.(time.Duration); ok && c.SlowDuration > 0 && d >= c.SlowDuration {
+		valueColor = c.Coloring.LevelWarn
+	}
+	if c.FieldColorFunc != nil {
+		if col := c.FieldColorFunc(key, value); col != nil {
+			valueColor, zeroColor = col, col
+		}
+	}
+	if len(c.Formatters) > 0 && value != nil {
+		if format, ok := c.Formatters[reflect.TypeOf(value)]; ok {
+			c.writeColored(buf, valueColor, c.trimFieldValue(format(value)))
+			return
+		}
 	}
 	switch v := value.(type) {
+	case nil:
+		c.writeColored(buf, zeroColor, "<nil>")
 	case string:
 		if v == "" {
-			return "“”", false
+			c.writeColored(buf, zeroColor, printableString(v))
+		} else {
+			c.writeColored(buf, valueColor, printableString(c.trimFieldValue(v)))
+		}
+	case time.Time:
+		var scratch [64]byte
+		seq := c.ansiSequence(valueColor)
+		buf.WriteString(seq.prefix)
+		buf.Write(v.AppendFormat(scratch[:0], c.TimeFormat))
+		buf.WriteString(seq.suffix)
+	case time.Duration:
+		if c.TimeDurationUnit > 0 {
+			v = v.Round(c.TimeDurationUnit)
 		}
-		return escapeString(v), true
+		c.writeColored(buf, valueColor, v.String())
 	default:
-		return fmt.Sprint(value), true
+		seq := c.ansiSequence(valueColor)
+		buf.WriteString(seq.prefix)
+		fmt.Fprint(buf, value)
+		buf.WriteString(seq.suffix)
 	}
 }
 
+// trimFieldValue trims the value down to the FieldValueMaxLength, counted in
+// runes, by replacing the end of the value with the Ellipsis.
+func (c context) trimFieldValue(value string) string {
+	maxLen := c.FieldValueMaxLength
+	if maxLen <= 0 || len(value) <= maxLen || runeCount(value) <= maxLen {
+		return value
+	}
+	if maxLen <= c.ellipsisLen {
+		return c.Ellipsis
+	}
+	return value[:runeIndex(value, maxLen-c.ellipsisLen)] + c.Ellipsis
+}
+
+func printableString(value string) string {
+	if value == "" {
+		return "“”"
+	}
+	return escapeString(value)
+}
+
 var escapeStringReplacer = strings.NewReplacer(
 	"\a", `\a`,
 	"\b", `\b`,
@@ -395,11 +1021,23 @@ func (c context) SetCaller(file string, line int) logger.Context {
 	return c
 }
 
+// SetCallerFrame implements logger.CallerFrameContext.
+func (c context) SetCallerFrame(frame logger.StackFrame) logger.Context {
+	c.callerFile = c.CallerMode.file(frame)
+	c.callerLine = frame.Line
+	return c
+}
+
 func (c context) SetError(value error) logger.Context {
 	c.err = value
 	return c
 }
 
+func (c context) SetStack(value []logger.StackFrame) logger.Context {
+	c.stack = value
+	return c
+}
+
 func (c context) AppendString(k string, v string) logger.Context          { return c.addField(k, v) }
 func (c context) AppendRune(k string, v rune) logger.Context              { return c.addField(k, v) }
 func (c context) AppendBool(k string, v bool) logger.Context              { return c.addField(k, v) }
@@ -414,12 +1052,16 @@ func (c context) AppendFloat64(k string, v float64) logger.Context        { retu
 func (c context) AppendTime(k string, v time.Time) logger.Context         { return c.addField(k, v) }
 func (c context) AppendDuration(k string, v time.Duration) logger.Context { return c.addField(k, v) }
 
+func (c context) AppendObject(k string, v func(logger.ObjectEncoder)) logger.Context {
+	return logger.FlattenObject(c, k, v)
+}
+
 func (c context) addField(key string, value any) logger.Context {
 	c.fields = append(c.fields, fieldPair{key, value})
 	return c
 }
 
-func (c context) writeMessage(w io.Writer, level logger.Level, msg string) {
+func (c context) writeMessage(buf *bytes.Buffer, level logger.Level, msg string) {
 	var color *color.Color
 	switch level {
 	case logger.LevelDebug:
@@ -436,56 +1078,92 @@ func (c context) writeMessage(w io.Writer, level logger.Level, msg string) {
 		color = c.Coloring.MessageDebug
 	}
 	msg = strings.ReplaceAll(msg, "\n", "\n\t")
-	color.Fprint(w, msg)
+	c.writeColored(buf, color, msg)
+}
+
+var defaultLevelLabels = [logger.LevelSilence + 1]string{
+	logger.LevelDebug:   "DEBUG",
+	logger.LevelInfo:    "INFO",
+	logger.LevelWarn:    "WARN",
+	logger.LevelError:   "ERROR",
+	logger.LevelPanic:   "PANIC",
+	logger.LevelSilence: "???",
+}
+
+func padLevelLabels(conf *Config) [logger.LevelSilence + 1]string {
+	labels := defaultLevelLabels
+	for level := logger.LevelDebug; level < logger.LevelSilence; level++ {
+		if label, ok := conf.LevelLabels[level]; ok {
+			labels[level] = label
+		}
+	}
+	width := conf.LevelMinLength
+	if width == 0 {
+		for _, label := range labels {
+			if n := utf8.RuneCountInString(label); n > width {
+				width = n
+			}
+		}
+	}
+	for i, label := range labels {
+		if pad := width - utf8.RuneCountInString(label); pad > 0 {
+			labels[i] = label + strings.Repeat(" ", pad)
+		}
+	}
+	return labels
 }
 
-func (c context) writeLevel(w io.Writer, level logger.Level) {
+func (c context) writeLevel(buf *bytes.Buffer, level logger.Level) {
+	var col *color.Color
 	switch level {
 	case logger.LevelDebug:
-		c.Coloring.LevelDebug.Fprint(w, "DEBUG")
+		col = c.Coloring.LevelDebug
 	case logger.LevelInfo:
-		c.Coloring.LevelInfo.Fprint(w, "INFO ")
+		col = c.Coloring.LevelInfo
 	case logger.LevelWarn:
-		c.Coloring.LevelWarn.Fprint(w, "WARN ")
+		col = c.Coloring.LevelWarn
 	case logger.LevelError:
-		c.Coloring.LevelError.Fprint(w, "ERROR")
+		col = c.Coloring.LevelError
 	case logger.LevelPanic:
-		c.Coloring.LevelPanic.Fprint(w, "PANIC")
+		col = c.Coloring.LevelPanic
 	default:
-		c.Coloring.LevelDebug.Fprint(w, "???  ")
+		col = c.Coloring.LevelDebug
+		level = logger.LevelSilence
 	}
+	c.writeColored(buf, col, c.levelLabels[level])
 }
 
-func (c context) writeScope(buf *bytes.Buffer) {
+func (c context) hasScope() bool {
 	if c.DisableScope {
-		return
-	}
-	scopeMinWidth := c.Config.ScopeMinLength
-	if c.Config.ScopeMinLengthAuto {
-		scopeMinWidth = logger.LongestScopeNameLength
+		return false
 	}
 	anyNonEmptyScope := c.scope != "" || logger.LongestScopeNameLength > 0
-	if !anyNonEmptyScope && scopeMinWidth <= 0 {
-		return
+	return anyNonEmptyScope || c.scopeMinWidth() > 0
+}
+
+func (c context) scopeMinWidth() int {
+	if c.Config.ScopeMinLengthAuto {
+		return logger.LongestScopeNameLength
 	}
-	c.Coloring.PreMessageDelimiter.Fprint(buf, "|")
-	scopeWrittenWidth := len(c.scope)
+	return c.Config.ScopeMinLength
+}
+
+func (c context) writeScopeValue(buf *bytes.Buffer) {
+	scopeWrittenWidth := runeCount(c.scope)
 	if c.Config.ScopeMaxLength > 0 {
 		scopeWrittenWidth = c.writeTrimmedRight(buf,
 			c.Coloring.Scope, c.scope, c.Config.ScopeMaxLength)
 	} else {
-		c.Coloring.Scope.Fprint(buf, c.scope)
-	}
-	for i := scopeWrittenWidth; i < scopeMinWidth; i++ {
-		buf.WriteRune(' ')
+		c.writeColored(buf, c.Coloring.Scope, c.scope)
 	}
+	writePadding(buf, scopeWrittenWidth, c.scopeMinWidth())
 }
 
-func (c context) writeCaller(buf *bytes.Buffer) {
-	if c.callerFile == "" || c.DisableCaller {
-		return
-	}
-	c.Coloring.PreMessageDelimiter.Fprint(buf, "|")
+func (c context) hasCaller() bool {
+	return c.callerFile != "" && !c.DisableCaller
+}
+
+func (c context) writeCallerValue(buf *bytes.Buffer) {
 	writtenWidth := 0
 	maxFileWidth := c.Config.CallerMaxLength
 	if maxFileWidth > 0 {
@@ -495,40 +1173,48 @@ func (c context) writeCaller(buf *bytes.Buffer) {
 		}
 		writtenWidth = c.writeTrimmedLeft(buf, c.Coloring.CallerFile, c.callerFile, maxFileWidth)
 	} else {
-		c.Coloring.CallerFile.Fprint(buf, c.callerFile)
-		writtenWidth = len(c.callerFile)
+		c.writeColored(buf, c.Coloring.CallerFile, c.callerFile)
+		writtenWidth = runeCount(c.callerFile)
 	}
 	if !c.DisableCallerLine {
-		c.Coloring.CallerDelimiter.Fprint(buf, ":")
-		lineStr := strconv.FormatInt(int64(c.callerLine), 10)
-		c.Coloring.CallerLine.Fprint(buf, lineStr)
-		writtenWidth += len(lineStr) + 1
+		c.writeColored(buf, c.Coloring.CallerDelimiter, ":")
+		var scratch [20]byte
+		line := strconv.AppendInt(scratch[:0], int64(c.callerLine), 10)
+		seq := c.ansiSequence(c.Coloring.CallerLine)
+		buf.WriteString(seq.prefix)
+		buf.Write(line)
+		buf.WriteString(seq.suffix)
+		writtenWidth += len(line) + 1
 	}
-	for i := writtenWidth; i < c.Config.CallerMinLength; i++ {
-		buf.WriteRune(' ')
+	writePadding(buf, writtenWidth, c.Config.CallerMinLength)
+}
+
+func writePadding(buf *bytes.Buffer, writtenWidth, minWidth int) {
+	for i := writtenWidth; i < minWidth; i++ {
+		buf.WriteByte(' ')
 	}
 }
 
-func (c context) writeTrimmedRight(w io.Writer, col *color.Color, value string, maxLen int) int {
-	if written, ok := c.writeUntrimmedString(w, col, value, maxLen); ok {
+func (c context) writeTrimmedRight(buf *bytes.Buffer, col *color.Color, value string, maxLen int) int {
+	if written, ok := c.writeUntrimmedString(buf, col, value, maxLen); ok {
 		return written
 	}
-	sliceLen := maxLen - c.ellipsisLen
-	col.Fprint(w, value[:sliceLen], c.Ellipsis)
+	end := runeIndex(value, maxLen-c.ellipsisLen)
+	c.writeColored(buf, col, value[:end], c.Ellipsis)
 	return maxLen
 }
 
-func (c context) writeTrimmedLeft(w io.Writer, col *color.Color, value string, maxLen int) int {
-	if written, ok := c.writeUntrimmedString(w, col, value, maxLen); ok {
+func (c context) writeTrimmedLeft(buf *bytes.Buffer, col *color.Color, value string, maxLen int) int {
+	if written, ok := c.writeUntrimmedString(buf, col, value, maxLen); ok {
 		return written
 	}
-	sliceStartIndex := len(value) - maxLen + c.ellipsisLen
-	col.Fprint(w, c.Ellipsis, value[sliceStartIndex:])
+	start := runeIndexFromEnd(value, maxLen-c.ellipsisLen)
+	c.writeColored(buf, col, c.Ellipsis, value[start:])
 	return maxLen
 }
 
-func (c context) writeUntrimmedString(w io.Writer, col *color.Color, value string, maxLen int) (int, bool) {
-	valueLen := len(value)
+func (c context) writeUntrimmedString(buf *bytes.Buffer, col *color.Color, value string, maxLen int) (int, bool) {
+	valueLen := runeCount(value)
 	if valueLen > maxLen {
 		return 0, false
 	}
@@ -537,14 +1223,83 @@ func (c context) writeUntrimmedString(w io.Writer, col *color.Color, value strin
 		// do nothing
 		return 0, true
 	case maxLen <= c.ellipsisLen && valueLen > c.ellipsisLen:
-		col.Fprint(w, c.Ellipsis)
+		c.writeColored(buf, col, c.Ellipsis)
 		return c.ellipsisLen, true
 	default:
-		col.Fprint(w, value)
+		c.writeColored(buf, col, value)
 		return valueLen, true
 	}
 }
 
+// runeCount returns the number of runes in the string, with a fast path for
+// ASCII-only strings.
+func runeCount(s string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return utf8.RuneCountInString(s)
+		}
+	}
+	return len(s)
+}
+
+// runeIndex returns the byte index of the n-th rune in the string, or the
+// length of the string if it has n runes or fewer.
+func runeIndex(s string, n int) int {
+	for i := range s {
+		if n <= 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
+
+// runeIndexFromEnd returns the byte index of where the last n runes of the
+// string start, or 0 if it has n runes or fewer.
+func runeIndexFromEnd(s string, n int) int {
+	i := len(s)
+	for ; n > 0 && i > 0; n-- {
+		_, size := utf8.DecodeLastRuneInString(s[:i])
+		i -= size
+	}
+	return i
+}
+
+func (c context) hasFields() bool {
+	return len(c.fields) > 0 || c.err != nil
+}
+
+// lastLineWidth returns the number of characters after the last newline.
+func lastLineWidth(s string) int {
+	if i := strings.LastIndexByte(s, '\n'); i != -1 {
+		// +1 for the tab added by writeMessage
+		return utf8.RuneCountInString(s[i+1:]) + 1
+	}
+	return utf8.RuneCountInString(s)
+}
+
+// maxVisibleLineWidth returns the number of characters in the widest line,
+// not counting ANSI escape sequences.
+func maxVisibleLineWidth(b []byte) int {
+	maxWidth, width := 0, 0
+	for i := 0; i < len(b); i++ {
+		switch {
+		case b[i] == '\x1b':
+			for i < len(b) && b[i] != 'm' {
+				i++
+			}
+		case b[i] == '\n':
+			width = 0
+		case utf8.RuneStart(b[i]):
+			width++
+			if width > maxWidth {
+				maxWidth = width
+			}
+		}
+	}
+	return maxWidth
+}
+
 func printedIntLenFast(number int) int {
 	// could do log10(number), but as the benchmark shows, that's approx 8-10
 	// times slower
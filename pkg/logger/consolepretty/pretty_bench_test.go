@@ -0,0 +1,15 @@
+package consolepretty_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/iver-wharf/wharf-core/v2/internal/logbench"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger/consolepretty"
+)
+
+func BenchmarkSink(b *testing.B) {
+	conf := consolepretty.DefaultConfig
+	conf.Writer = io.Discard
+	logbench.RunSinkBenchmarks(b, consolepretty.New(conf))
+}
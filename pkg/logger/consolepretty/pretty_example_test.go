@@ -1,6 +1,9 @@
 package consolepretty_test
 
 import (
+	"reflect"
+	"strconv"
+
 	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
 	"github.com/iver-wharf/wharf-core/v2/pkg/logger/consolepretty"
 )
@@ -56,3 +59,108 @@ func ExampleConfig_Ellipsis() {
 	// Output:
 	// [DEBUG|…xample_test.go] Sample message.
 }
+
+func ExampleConfig_Layout() {
+	defer logger.ClearOutputs()
+	logger.AddOutput(logger.LevelDebug, consolepretty.New(consolepretty.Config{
+		DisableDate:       true,
+		DisableCallerLine: true,
+
+		Layout: []consolepretty.Segment{
+			consolepretty.SegmentScope,
+			consolepretty.SegmentLevel,
+			consolepretty.SegmentMessage,
+			consolepretty.SegmentFields,
+			consolepretty.SegmentCaller,
+		},
+	}))
+
+	logger.NewScoped("WHARF").Debug().
+		WithInt("id", 1).
+		Message("Sample message.")
+
+	// Output:
+	// [WHARF|DEBUG] Sample message.  id=1 [consolepretty/pretty_example_test.go]
+}
+
+func ExampleConfig_LevelLabels() {
+	defer logger.ClearOutputs()
+	logger.AddOutput(logger.LevelDebug, consolepretty.New(consolepretty.Config{
+		DisableDate:   true,
+		DisableCaller: true,
+		DisableScope:  true,
+
+		LevelLabels: map[logger.Level]string{
+			logger.LevelDebug: "DBG",
+			logger.LevelInfo:  "INF",
+			logger.LevelWarn:  "WRN",
+			logger.LevelError: "ERR",
+			logger.LevelPanic: "PNC",
+		},
+	}))
+
+	log := logger.New()
+	log.Debug().Message("Sample message.")
+	log.Info().Message("Sample message.")
+
+	// Output:
+	// [DBG] Sample message.
+	// [INF] Sample message.
+}
+
+func ExampleConfig_Formatters() {
+	defer logger.ClearOutputs()
+	logger.AddOutput(logger.LevelDebug, consolepretty.New(consolepretty.Config{
+		DisableDate:   true,
+		DisableCaller: true,
+		DisableScope:  true,
+
+		Formatters: map[reflect.Type]func(any) string{
+			reflect.TypeOf(float64(0)): func(v any) string {
+				return strconv.FormatFloat(v.(float64), 'f', 2, 64)
+			},
+		},
+	}))
+
+	logger.New().Info().
+		WithFloat64("ratio", 1.0/3).
+		Message("Sample message.")
+
+	// Output:
+	// [INFO ] Sample message.  ratio=0.33
+}
+
+func ExampleConfig_MessageMinLength() {
+	defer logger.ClearOutputs()
+	logger.AddOutput(logger.LevelDebug, consolepretty.New(consolepretty.Config{
+		DisableDate:   true,
+		DisableCaller: true,
+		DisableScope:  true,
+
+		MessageMinLength: 16,
+	}))
+
+	log := logger.New()
+	log.Info().WithInt("buildId", 12).Message("Build started.")
+	log.Info().WithInt("buildId", 12).Message("Done.")
+
+	// Output:
+	// [INFO ] Build started.    buildId=12
+	// [INFO ] Done.             buildId=12
+}
+
+func ExampleConfig_CallerMode() {
+	defer logger.ClearOutputs()
+	logger.AddOutput(logger.LevelDebug, consolepretty.New(consolepretty.Config{
+		DisableDate:       true,
+		DisableCallerLine: true,
+		DisableScope:      true,
+
+		CallerMode: consolepretty.CallerModePackage,
+	}))
+
+	logger.New().Info().Message("Sample message.")
+
+	// Output:
+	// [INFO |github.com/iver-wharf/wharf-core/v2/pkg/logger/consolepretty_test/pretty_example_test.go] Sample message.
+}
@@ -2,11 +2,17 @@ package consolepretty
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"math"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/iver-wharf/wharf-core/v2/internal/testutil"
 	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
 	"github.com/stretchr/testify/assert"
 )
@@ -60,47 +66,569 @@ func TestContextWriteScope(t *testing.T) {
 			name:    "with scope",
 			scope:   "abc",
 			longest: 0,
-			want:    "|abc",
+			want:    "[abc]",
 		},
 		{
 			name:    "padded",
 			scope:   "abc",
 			config:  Config{ScopeMinLength: 6},
 			longest: 0,
-			want:    "|abc   ",
+			want:    "[abc   ]",
 		},
 		{
 			name:    "autopadded",
 			scope:   "abc",
 			config:  Config{ScopeMinLengthAuto: true},
 			longest: 6,
-			want:    "|abc   ",
+			want:    "[abc   ]",
 		},
 		{
 			name:    "maxxed",
 			scope:   "abcdef",
 			config:  Config{ScopeMaxLength: 3},
 			longest: 0,
-			want:    "|abc",
+			want:    "[abc]",
+		},
+		{
+			name:    "non-ASCII padded",
+			scope:   "åäö",
+			config:  Config{ScopeMinLength: 6},
+			longest: 0,
+			want:    "[åäö   ]",
+		},
+		{
+			name:    "non-ASCII maxxed",
+			scope:   "åäöåäö",
+			config:  Config{ScopeMaxLength: 3},
+			longest: 0,
+			want:    "[åäö]",
 		},
 	}
-	color.NoColor = true
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			logger.LongestScopeNameLength = tc.longest
 			tc.config.Coloring = &DefaultColorConfig
 			ctx := context{
-				scope:  tc.scope,
-				Config: &tc.config,
+				scope:   tc.scope,
+				Config:  &tc.config,
+				noColor: true,
 			}
 			var buf bytes.Buffer
-			ctx.writeScope(&buf)
+			w := lineWriter{buf: &buf, bodyStart: -1}
+			ctx.writeHeader(&w, logger.LevelInfo, []Segment{SegmentScope})
 			assert.Equal(t, tc.want, buf.String())
 		})
 	}
 	logger.LongestScopeNameLength = 0
 }
 
+func TestContext_writeTrimmed(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		maxLen    int
+		wantRight string
+		wantLeft  string
+	}{
+		{
+			name:      "untrimmed",
+			value:     "abc",
+			maxLen:    3,
+			wantRight: "abc",
+			wantLeft:  "abc",
+		},
+		{
+			name:      "ASCII",
+			value:     "abcdef",
+			maxLen:    4,
+			wantRight: "abc…",
+			wantLeft:  "…def",
+		},
+		{
+			name:      "non-ASCII",
+			value:     "åäöÅÄÖ",
+			maxLen:    4,
+			wantRight: "åäö…",
+			wantLeft:  "…ÅÄÖ",
+		},
+		{
+			name:      "non-ASCII untrimmed",
+			value:     "åäö",
+			maxLen:    3,
+			wantRight: "åäö",
+			wantLeft:  "åäö",
+		},
+		{
+			name:      "only ellipsis",
+			value:     "åäö",
+			maxLen:    1,
+			wantRight: "…",
+			wantLeft:  "…",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context{
+				Config:      &Config{Ellipsis: "…"},
+				ellipsisLen: 1,
+				noColor:     true,
+			}
+			var right, left bytes.Buffer
+			ctx.writeTrimmedRight(&right, nil, tc.value, tc.maxLen)
+			ctx.writeTrimmedLeft(&left, nil, tc.value, tc.maxLen)
+			assert.Equal(t, tc.wantRight, right.String(), "right")
+			assert.Equal(t, tc.wantLeft, left.String(), "left")
+		})
+	}
+}
+
+func TestContext_writeColored(t *testing.T) {
+	defer func(noColor bool) { color.NoColor = noColor }(color.NoColor)
+	color.NoColor = false
+	col := color.New(color.FgHiWhite, color.BgRed, color.Bold)
+	var coloredBuf bytes.Buffer
+	col.Fprint(&coloredBuf, "foo", "bar")
+	colored := coloredBuf.String()
+
+	disabled := color.New(color.FgRed)
+	disabled.DisableColor()
+
+	tests := []struct {
+		name          string
+		globalNoColor bool
+		noColor       bool
+		color         *color.Color
+		want          string
+	}{
+		{
+			name:  "colored",
+			color: col,
+			want:  colored,
+		},
+		{
+			name:          "colored ignores global NoColor",
+			globalNoColor: true,
+			color:         col,
+			want:          colored,
+		},
+		{
+			name:    "no color",
+			noColor: true,
+			color:   col,
+			want:    "foobar",
+		},
+		{
+			name:  "nil color",
+			color: nil,
+			want:  "foobar",
+		},
+		{
+			name:  "disabled color",
+			color: disabled,
+			want:  "foobar",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			color.NoColor = tc.globalNoColor
+			ctx := context{noColor: tc.noColor}
+			var buf bytes.Buffer
+			ctx.writeColored(&buf, tc.color, "foo", "bar")
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func TestColorMode_enabled(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    ColorMode
+		noColor string
+		want    bool
+	}{
+		{name: "auto non-terminal", mode: ColorModeAuto, want: false},
+		{name: "always", mode: ColorModeAlways, want: true},
+		{name: "always with NO_COLOR", mode: ColorModeAlways, noColor: "1", want: true},
+		{name: "never", mode: ColorModeNever, want: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			testutil.SetEnv(t, EnvNoColor, tc.noColor)
+			assert.Equal(t, tc.want, tc.mode.enabled(&bytes.Buffer{}))
+		})
+	}
+}
+
+// byteWriter writes one byte at a time, yielding in between, to provoke
+// interleaved output if the writes are not synchronized. Not safe for
+// concurrent use.
+type byteWriter struct {
+	buf []byte
+}
+
+func (w *byteWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		w.buf = append(w.buf, b)
+		runtime.Gosched()
+	}
+	return len(p), nil
+}
+
+func TestContext_WriteOut_concurrent(t *testing.T) {
+	w := &byteWriter{}
+	prettySink := New(Config{
+		Writer:        w,
+		DisableDate:   true,
+		DisableCaller: true,
+		DisableScope:  true,
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			prettySink.NewContext("TEST").AppendInt("id", i).WriteOut(logger.LevelInfo, "Sample message.")
+		}(i)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(string(w.buf), "\n"), "\n")
+	assert.Len(t, lines, 20)
+	for _, line := range lines {
+		assert.Regexp(t, `^\[INFO \] Sample message\.  id=\d+$`, line)
+	}
+}
+
+func TestContext_WriteOut_layout(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout []Segment
+		want   string
+	}{
+		{
+			name: "default",
+			want: "foo:[INFO |SCOPE|main.go:12] Sample message.  id=1\n",
+		},
+		{
+			name:   "caller last",
+			layout: []Segment{SegmentLevel, SegmentMessage, SegmentFields, SegmentCaller},
+			want:   "[INFO ] Sample message.  id=1 [main.go:12]\n",
+		},
+		{
+			name:   "fields before message",
+			layout: []Segment{SegmentFields, SegmentMessage},
+			want:   "id=1 Sample message.\n",
+		},
+		{
+			name:   "separate headers",
+			layout: []Segment{SegmentScope, SegmentMessage, SegmentLevel},
+			want:   "[SCOPE] Sample message. [INFO ]\n",
+		},
+		{
+			name:   "prefix not first",
+			layout: []Segment{SegmentLevel, SegmentPrefix, SegmentMessage},
+			want:   "[INFO ] foo:Sample message.\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			prettySink := New(Config{
+				Writer:      &buf,
+				Prefix:      "foo:",
+				DisableDate: true,
+				Layout:      tc.layout,
+			})
+			prettySink.NewContext("SCOPE").
+				SetCaller("main.go", 12).
+				AppendInt("id", 1).
+				WriteOut(logger.LevelInfo, "Sample message.")
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func TestPadLevelLabels(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		wantInfo  string
+		wantDebug string
+	}{
+		{
+			name:      "defaults",
+			wantInfo:  "INFO ",
+			wantDebug: "DEBUG",
+		},
+		{
+			name:      "no padding",
+			config:    Config{LevelMinLength: -1},
+			wantInfo:  "INFO",
+			wantDebug: "DEBUG",
+		},
+		{
+			name:      "wider",
+			config:    Config{LevelMinLength: 7},
+			wantInfo:  "INFO   ",
+			wantDebug: "DEBUG  ",
+		},
+		{
+			name: "longer custom label",
+			config: Config{LevelLabels: map[logger.Level]string{
+				logger.LevelInfo: "INFORMATION",
+			}},
+			wantInfo:  "INFORMATION",
+			wantDebug: "DEBUG      ",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			labels := padLevelLabels(&tc.config)
+			assert.Equal(t, tc.wantInfo, labels[logger.LevelInfo])
+			assert.Equal(t, tc.wantDebug, labels[logger.LevelDebug])
+		})
+	}
+}
+
+func TestContext_WriteOut_fieldOrder(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   string
+	}{
+		{
+			name: "insertion order",
+			want: "Sample message.  b=2  reqId=3  a=1\n",
+		},
+		{
+			name:   "sorted",
+			config: Config{SortFields: true},
+			want:   "Sample message.  a=1  b=2  reqId=3\n",
+		},
+		{
+			name:   "priority",
+			config: Config{PriorityFields: []string{"reqId", "missing", "a"}},
+			want:   "Sample message.  reqId=3  a=1  b=2\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tc.config.Writer = &buf
+			tc.config.Layout = []Segment{SegmentMessage, SegmentFields}
+			New(tc.config).NewContext("").
+				AppendInt("b", 2).
+				AppendInt("reqId", 3).
+				AppendInt("a", 1).
+				WriteOut(logger.LevelInfo, "Sample message.")
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func TestContext_WriteOut_multilineFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		err    error
+		want   string
+	}{
+		{
+			name: "single line",
+			want: "[INFO ] Sample message.  a=1  b=“foo bar”\n",
+		},
+		{
+			name:   "multiline",
+			config: Config{MultilineFields: true},
+			want:   "[INFO ] Sample message.\n\ta=1\n\tb=“foo bar”\n",
+		},
+		{
+			name:   "multiline with error",
+			config: Config{MultilineFields: true},
+			err:    errors.New("oops"),
+			want:   "[INFO ] Sample message.\n\ta=1\n\tb=“foo bar”\n\terror=oops (*errors.errorString)\n",
+		},
+		{
+			name:   "below width threshold",
+			config: Config{MultilineFieldsWidth: 41},
+			want:   "[INFO ] Sample message.  a=1  b=“foo bar”\n",
+		},
+		{
+			name:   "above width threshold",
+			config: Config{MultilineFieldsWidth: 40},
+			want:   "[INFO ] Sample message.\n\ta=1\n\tb=“foo bar”\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tc.config.Writer = &buf
+			tc.config.DisableDate = true
+			tc.config.DisableScope = true
+			New(tc.config).NewContext("").
+				AppendInt("a", 1).
+				AppendString("b", "foo bar").
+				SetError(tc.err).
+				WriteOut(logger.LevelInfo, "Sample message.")
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func TestContext_WriteOut_stack(t *testing.T) {
+	var buf bytes.Buffer
+	prettySink := New(Config{
+		Writer:       &buf,
+		DisableDate:  true,
+		DisableScope: true,
+	})
+	prettySink.NewContext("").(logger.StackContext).
+		SetStack([]logger.StackFrame{
+			{Function: "main.run", File: "/src/main.go", Line: 12},
+			{Function: "main.main", File: "/src/main.go", Line: 5},
+		}).
+		AppendInt("id", 1).
+		WriteOut(logger.LevelError, "Sample message.")
+	assert.Equal(t, "[ERROR] Sample message.  id=1"+
+		"\n\tmain.run\n\t\t/src/main.go:12"+
+		"\n\tmain.main\n\t\t/src/main.go:5\n", buf.String())
+}
+
+func TestContext_WriteOut_timeFields(t *testing.T) {
+	since := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	elapsed := 1523456 * time.Microsecond
+	tests := []struct {
+		name   string
+		config Config
+		want   string
+	}{
+		{
+			name: "defaults",
+			want: "since=2006-01-02T15:04:05Z  elapsed=1.523456s\n",
+		},
+		{
+			name: "custom",
+			config: Config{
+				TimeFormat:       time.Kitchen,
+				TimeDurationUnit: 10 * time.Millisecond,
+			},
+			want: "since=3:04PM  elapsed=1.52s\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tc.config.Writer = &buf
+			tc.config.Layout = []Segment{SegmentFields}
+			New(tc.config).NewContext("").
+				AppendTime("since", since).
+				AppendDuration("elapsed", elapsed).
+				WriteOut(logger.LevelInfo, "")
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func TestContext_WriteOut_fieldColorFunc(t *testing.T) {
+	highlight := color.New(color.FgHiRed)
+	var buf bytes.Buffer
+	New(Config{
+		Writer:    &buf,
+		Layout:    []Segment{SegmentFields},
+		ColorMode: ColorModeAlways,
+		Coloring:  &ColorConfig{},
+		FieldColorFunc: func(key string, value any) *color.Color {
+			if status, ok := value.(int); ok && key == "status" && status >= 500 {
+				return highlight
+			}
+			return nil
+		},
+	}).NewContext("").
+		AppendInt("status", 503).
+		AppendInt("other", 503).
+		WriteOut(logger.LevelInfo, "")
+
+	seq := getANSISequence(highlight)
+	want := "status=" + seq.prefix + "503" + seq.suffix + "  other=503\n"
+	assert.Equal(t, want, buf.String())
+}
+
+func TestContext_WriteOut_slowDuration(t *testing.T) {
+	warn := color.New(color.FgYellow)
+	var buf bytes.Buffer
+	New(Config{
+		Writer:       &buf,
+		Layout:       []Segment{SegmentFields},
+		ColorMode:    ColorModeAlways,
+		Coloring:     &ColorConfig{LevelWarn: warn},
+		SlowDuration: time.Second,
+	}).NewContext("").
+		AppendDuration("fast", 999*time.Millisecond).
+		AppendDuration("slow", time.Second).
+		WriteOut(logger.LevelInfo, "")
+
+	seq := getANSISequence(warn)
+	want := "fast=999ms  slow=" + seq.prefix + "1s" + seq.suffix + "\n"
+	assert.Equal(t, want, buf.String())
+}
+
+func TestContext_trimFieldValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		maxLen int
+		value  string
+		want   string
+	}{
+		{name: "disabled", maxLen: 0, value: "SELECT * FROM build", want: "SELECT * FROM build"},
+		{name: "short enough", maxLen: 19, value: "SELECT * FROM build", want: "SELECT * FROM build"},
+		{name: "trimmed", maxLen: 10, value: "SELECT * FROM build", want: "SELECT * …"},
+		{name: "multibyte", maxLen: 4, value: "åäöåäö", want: "åäö…"},
+		{name: "only ellipsis", maxLen: 1, value: "abc", want: "…"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context{
+				Config:      &Config{FieldValueMaxLength: tc.maxLen, Ellipsis: "…"},
+				ellipsisLen: 1,
+			}
+			assert.Equal(t, tc.want, ctx.trimFieldValue(tc.value))
+		})
+	}
+}
+
+func TestCallerMode_file(t *testing.T) {
+	frame := logger.StackFrame{
+		Function: "github.com/iver-wharf/wharf-core/v2/pkg/logger.(*Mock).Debug",
+		File:     "/src/wharf-core/pkg/logger/mock.go",
+		Line:     12,
+	}
+	tests := []struct {
+		mode CallerMode
+		want string
+	}{
+		{mode: CallerModeShort, want: "logger/mock.go"},
+		{mode: CallerModeBase, want: "mock.go"},
+		{mode: CallerModePackage, want: "github.com/iver-wharf/wharf-core/v2/pkg/logger/mock.go"},
+		{mode: CallerModeFull, want: "/src/wharf-core/pkg/logger/mock.go"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.want, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.mode.file(frame))
+		})
+	}
+}
+
+func TestPackagePath(t *testing.T) {
+	assert.Equal(t, "main", packagePath("main.main"))
+	assert.Equal(t, "example.com/foo.v2/bar", packagePath("example.com/foo.v2/bar.Baz.func1"))
+	assert.Equal(t, "", packagePath(""))
+}
+
+func TestMaxVisibleLineWidth(t *testing.T) {
+	assert.Equal(t, 6, maxVisibleLineWidth([]byte("\x1b[31mfoo\x1b[0m\nfoobar\n“”")))
+}
+
 var varThatDisablesCompilerOptimizations int
 
 func BenchmarkPrintedIntLenSlow(b *testing.B) {
@@ -0,0 +1,7 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris && !windows
+
+package consolepretty
+
+func terminalWidth(fd uintptr) int {
+	return 0
+}
@@ -0,0 +1,11 @@
+package consolepretty
+
+import "golang.org/x/sys/windows"
+
+func terminalWidth(fd uintptr) int {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(fd), &info); err != nil {
+		return 0
+	}
+	return int(info.Window.Right - info.Window.Left + 1)
+}
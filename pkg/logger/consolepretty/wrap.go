@@ -0,0 +1,80 @@
+package consolepretty
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+)
+
+// EnvColumns is the environment variable used as the terminal width when
+// using Config.WrapWidthAuto and the width could not be obtained from the
+// terminal itself.
+const EnvColumns = "COLUMNS"
+
+type fdWriter interface {
+	Fd() uintptr
+}
+
+// wrapWidth returns the width to soft-wrap the log lines at, or 0 if the log
+// lines should not be wrapped.
+func (c context) wrapWidth() int {
+	if !c.WrapWidthAuto {
+		return c.WrapWidth
+	}
+	if c.terminal != nil {
+		if width := terminalWidth(c.terminal.Fd()); width > 0 {
+			return width
+		}
+	}
+	if width, err := strconv.Atoi(os.Getenv(EnvColumns)); err == nil && width > 0 {
+		return width
+	}
+	return c.WrapWidth
+}
+
+// softWrap writes the first line, up until the first newline, of the log line
+// to dst, where the spaces after the start index are replaced with line breaks
+// wherever the line would otherwise be wider than the width. The continuation
+// lines are indented to the column of the start index, but never more than
+// half of the width. Words wider than the width are left as-is.
+func softWrap(dst *bytes.Buffer, line []byte, start, width int) {
+	end := bytes.IndexByte(line, '\n')
+	if end == -1 {
+		end = len(line)
+	}
+	col := maxVisibleLineWidth(line[:start])
+	indent := col
+	if indent > width/2 {
+		indent = width / 2
+	}
+	dst.Write(line[:start])
+	spaces := 0
+	rest := line[start:end]
+	for len(rest) > 0 {
+		if rest[0] == ' ' {
+			spaces++
+			rest = rest[1:]
+			continue
+		}
+		wordEnd := bytes.IndexByte(rest, ' ')
+		if wordEnd == -1 {
+			wordEnd = len(rest)
+		}
+		word := rest[:wordEnd]
+		rest = rest[wordEnd:]
+		wordWidth := maxVisibleLineWidth(word)
+		if wordWidth > 0 && col > indent && col+spaces+wordWidth > width {
+			dst.WriteByte('\n')
+			writePadding(dst, 0, indent)
+			col = indent
+		} else {
+			writePadding(dst, 0, spaces)
+			col += spaces
+		}
+		spaces = 0
+		dst.Write(word)
+		col += wordWidth
+	}
+	writePadding(dst, 0, spaces)
+	dst.Write(line[end:])
+}
@@ -0,0 +1,121 @@
+package consolepretty
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/iver-wharf/wharf-core/v2/internal/testutil"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSoftWrap(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		start int
+		width int
+		want  string
+	}{
+		{
+			name:  "fits",
+			line:  "[INFO] foo bar",
+			start: 7,
+			width: 14,
+			want:  "[INFO] foo bar",
+		},
+		{
+			name:  "hanging indent",
+			line:  "[INFO] foo bar moo",
+			start: 7,
+			width: 14,
+			want:  "[INFO] foo bar\n       moo",
+		},
+		{
+			name:  "keeps field separator",
+			line:  "[INFO] foo  a=1  b=2",
+			start: 7,
+			width: 16,
+			want:  "[INFO] foo  a=1\n       b=2",
+		},
+		{
+			name:  "indent capped to half width",
+			line:  "[INFO] foo bar moo",
+			start: 7,
+			width: 10,
+			want:  "[INFO] foo\n     bar\n     moo",
+		},
+		{
+			name:  "long word left as-is",
+			line:  "[INFO] foobarmoodoo",
+			start: 7,
+			width: 14,
+			want:  "[INFO] foobarmoodoo",
+		},
+		{
+			name:  "ignores escape codes",
+			line:  "[INFO] \x1b[31mfoo\x1b[0m bar moo",
+			start: 7,
+			width: 14,
+			want:  "[INFO] \x1b[31mfoo\x1b[0m bar\n       moo",
+		},
+		{
+			name:  "only first line",
+			line:  "[INFO] foo bar moo\n\ta=1 b=2 c=3 d=4",
+			start: 7,
+			width: 14,
+			want:  "[INFO] foo bar\n       moo\n\ta=1 b=2 c=3 d=4",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			softWrap(&buf, []byte(tc.line), tc.start, tc.width)
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func TestContext_WriteOut_wrap(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		columns string
+		want    string
+	}{
+		{
+			name: "disabled",
+			want: "[INFO] Sample message that is long.  a=1  b=2\n",
+		},
+		{
+			name:   "width",
+			config: Config{WrapWidth: 30},
+			want:   "[INFO] Sample message that is\n       long.  a=1  b=2\n",
+		},
+		{
+			name:    "auto from env",
+			config:  Config{WrapWidthAuto: true},
+			columns: "30",
+			want:    "[INFO] Sample message that is\n       long.  a=1  b=2\n",
+		},
+		{
+			name:   "auto fallback",
+			config: Config{WrapWidth: 30, WrapWidthAuto: true},
+			want:   "[INFO] Sample message that is\n       long.  a=1  b=2\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			testutil.SetEnv(t, EnvColumns, tc.columns)
+			var buf bytes.Buffer
+			tc.config.Writer = &buf
+			tc.config.Layout = []Segment{SegmentLevel, SegmentMessage, SegmentFields}
+			tc.config.LevelMinLength = -1
+			New(tc.config).NewContext("").
+				AppendInt("a", 1).
+				AppendInt("b", 2).
+				WriteOut(logger.LevelInfo, "Sample message that is long.")
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
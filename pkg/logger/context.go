@@ -2,6 +2,53 @@ package logger
 
 import "time"
 
+// CallerFrameContext is an optional interface implemented by contexts that
+// want more details about the caller than given to Context.SetCaller, such as
+// to render the full file path or the Go package path of the caller.
+type CallerFrameContext interface {
+	Context
+	// SetCallerFrame is called instead of SetCaller with the automatically
+	// resolved caller of the log event. The Function and File fields of the
+	// frame holds the fully qualified function name and the full file path.
+	//
+	// Calling Context.SetCaller afterwards, such as via Event.WithCaller,
+	// shall override the value set by this method.
+	SetCallerFrame(frame StackFrame) Context
+}
+
+// StackContext is an optional interface implemented by contexts that can
+// render stack traces, as added via Event.WithStack and
+// Event.WithStackFrames. The stack trace is skipped for contexts that do not
+// implement this interface.
+type StackContext interface {
+	Context
+	// SetStack sets the stack trace for this context, such as when logging an
+	// error or a recovered panic.
+	//
+	// Calling this method multiple times shall override the previous value.
+	// A nil slice signifies to unset this field.
+	//
+	// In contrast to Context.AppendString, the logging sink is allowed to
+	// render this differently. E.g. some may render it as an indented
+	// multi-line trace below the log message, others may render it as a field
+	// named "stack".
+	SetStack(stack []StackFrame) Context
+}
+
+// ObjectContext is an optional interface implemented by contexts that can
+// render nested objects, as added via Event.WithObject. For contexts that do
+// not implement this interface, the fields of the object are instead added
+// directly to the context using FlattenObject.
+type ObjectContext interface {
+	Context
+	// AppendObject adds a nested object value for a specific key to this
+	// context, where the fields of the object are added by the given function.
+	//
+	// Calling this method multiple times with the same key may lead to
+	// unexpected behaviour.
+	AppendObject(key string, fn func(ObjectEncoder)) Context
+}
+
 // Context is data held about a certain logging event for a particular sink.
 // The data can be stored in any way that seems suitable for efficiently
 // composing a logging message for that sink.
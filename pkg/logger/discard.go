@@ -0,0 +1,33 @@
+package logger
+
+import "time"
+
+// Discard is a Sink that discards all log events without formatting them.
+// Useful to measure the overhead of the logging API itself in benchmarks, or
+// to silence logs in tests.
+var Discard Sink = discardSink{}
+
+type discardSink struct{}
+
+func (discardSink) NewContext(string) Context { return discardCtx{} }
+
+type discardCtx struct{}
+
+func (c discardCtx) WriteOut(Level, string)                           {}
+func (c discardCtx) SetCaller(string, int) Context                    { return c }
+func (c discardCtx) SetError(error) Context                           { return c }
+func (c discardCtx) SetStack([]StackFrame) Context                    { return c }
+func (c discardCtx) AppendString(string, string) Context              { return c }
+func (c discardCtx) AppendRune(string, rune) Context                  { return c }
+func (c discardCtx) AppendBool(string, bool) Context                  { return c }
+func (c discardCtx) AppendInt(string, int) Context                    { return c }
+func (c discardCtx) AppendInt32(string, int32) Context                { return c }
+func (c discardCtx) AppendInt64(string, int64) Context                { return c }
+func (c discardCtx) AppendUint(string, uint) Context                  { return c }
+func (c discardCtx) AppendUint32(string, uint32) Context              { return c }
+func (c discardCtx) AppendUint64(string, uint64) Context              { return c }
+func (c discardCtx) AppendFloat32(string, float32) Context            { return c }
+func (c discardCtx) AppendFloat64(string, float64) Context            { return c }
+func (c discardCtx) AppendTime(string, time.Time) Context             { return c }
+func (c discardCtx) AppendObject(string, func(ObjectEncoder)) Context { return c }
+func (c discardCtx) AppendDuration(string, time.Duration) Context     { return c }
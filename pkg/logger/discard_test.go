@@ -0,0 +1,12 @@
+package logger_test
+
+import (
+	"testing"
+
+	"github.com/iver-wharf/wharf-core/v2/internal/logbench"
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+)
+
+func BenchmarkDiscard(b *testing.B) {
+	logbench.RunSinkBenchmarks(b, logger.Discard)
+}
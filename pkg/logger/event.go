@@ -1,8 +1,10 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/iver-wharf/wharf-core/v2/internal/traceutil"
@@ -103,6 +105,19 @@ type Event interface {
 	// "error".
 	WithError(value error) Event
 
+	// WithStack adds the stack trace of the calling goroutine to this logged
+	// message. Calling this method multiple times shall override the previous
+	// value.
+	//
+	// It's up to the logger sink to decide how this stack trace is rendered in
+	// the log message.
+	WithStack() Event
+
+	// WithStackFrames adds a given stack trace to this logged message, such as
+	// the one returned by ErrorStack. Calling this method multiple times shall
+	// override the previous value.
+	WithStackFrames(stack []StackFrame) Event
+
 	// WithTime adds a timestamp field to this logged message. Calling
 	// this method multiple times with the same key may lead to unexpected behaviour.
 	//
@@ -116,6 +131,36 @@ type Event interface {
 	// It's up to the logger sink to decide how this error is rendered in the log
 	// message, e.g. in milliseconds integer form or string formatted duration.
 	WithDuration(key string, value time.Duration) Event
+
+	// WithObject adds a nested object field to this logged message, where the
+	// fields of the object are added by the given function. Useful to group
+	// related fields, such as:
+	//
+	// 	ev.WithObject("http", func(enc logger.ObjectEncoder) {
+	// 		enc.AddString("method", "GET")
+	// 		enc.AddInt("status", 200)
+	// 	})
+	//
+	// Calling this method multiple times with the same key may lead to
+	// unexpected behaviour.
+	//
+	// It's up to the logger sink to decide how this object is rendered in the
+	// log message, e.g. as a nested JSON object or as flattened fields with
+	// the keys "http.method" and "http.status".
+	WithObject(key string, fn func(ObjectEncoder)) Event
+
+	// WithFields adds multiple fields to this logged message, using the
+	// With... method matching the type of each field's value. Calling this
+	// method multiple times with the same keys may lead to unexpected
+	// behaviour.
+	WithFields(fields ...Field) Event
+
+	// WithProvidedFields adds the fields given by all providers registered via
+	// RegisterFieldsProvider for the given context.
+	//
+	// Useful for propagating fields, such as request IDs, from integrations
+	// without having to add them to each logged message manually.
+	WithProvidedFields(ctx context.Context) Event
 }
 
 var contextPool = sync.Pool{
@@ -125,9 +170,11 @@ var contextPool = sync.Pool{
 }
 
 type event struct {
-	level Level
-	ctxs  []Context
-	done  DoneFunc
+	level   Level
+	ctxs    []Context
+	done    DoneFunc
+	fields  int
+	dropped int
 }
 
 // NewEvent creates a new event and prepares it to use a list of logging sinks
@@ -149,13 +196,50 @@ func newEventFromSinks(level Level, scope string, done DoneFunc, sinks []registe
 		}
 		ctxs = append(ctxs, reg.sink.NewContext(scope))
 	}
-	ev := event{level, ctxs, done}
-	if caller, line := traceutil.CallerFileWithLineNum(); caller != "" {
-		return ev.WithCaller(caller, line)
+	ev := event{level: level, ctxs: ctxs, done: done}
+	if len(ev.ctxs) == 0 {
+		return ev
+	}
+	if function, path, line, ok := traceutil.CallerFrame(); ok {
+		return ev.withCallerFrame(StackFrame{function, path, line})
 	}
 	return ev
 }
 
+func (ev event) withCallerFrame(frame StackFrame) event {
+	for i, ctx := range ev.ctxs {
+		ev.ctxs[i] = setCallerFrame(ctx, frame)
+	}
+	return ev
+}
+
+// setCallerFrame uses CallerFrameContext.SetCallerFrame if implemented by the
+// context, and falls back to Context.SetCaller otherwise.
+func setCallerFrame(ctx Context, frame StackFrame) Context {
+	if frameCtx, ok := ctx.(CallerFrameContext); ok {
+		return frameCtx.SetCallerFrame(frame)
+	}
+	return ctx.SetCaller(traceutil.FileAndLastDir(frame.File), frame.Line)
+}
+
+// setStack uses StackContext.SetStack if implemented by the context, and skips
+// the stack trace otherwise.
+func setStack(ctx Context, stack []StackFrame) Context {
+	if stackCtx, ok := ctx.(StackContext); ok {
+		return stackCtx.SetStack(stack)
+	}
+	return ctx
+}
+
+// appendObject uses ObjectContext.AppendObject if implemented by the context,
+// and falls back to FlattenObject otherwise.
+func appendObject(ctx Context, key string, fn func(ObjectEncoder)) Context {
+	if objCtx, ok := ctx.(ObjectContext); ok {
+		return objCtx.AppendObject(key, fn)
+	}
+	return FlattenObject(ctx, key, fn)
+}
+
 // NewEventFromLogger creates an event using the logger itself based on the
 // logging level. Useful in edge-cases and when testing with a slice of test
 // cases.
@@ -189,6 +273,9 @@ func (ev event) Messagef(format string, args ...any) {
 
 func (ev event) Message(message string) {
 	for _, log := range ev.ctxs {
+		if ev.dropped > 0 {
+			log = log.AppendInt(DroppedFieldsKey, ev.dropped)
+		}
 		log.WriteOut(ev.level, message)
 	}
 	ev.returnPooledSlice()
@@ -208,7 +295,10 @@ func (ev event) WithFunc(f func(Event) Event) Event {
 }
 
 func (ev event) WithCaller(file string, line int) Event {
-	return withKeyedFunc(ev, file, line, Context.SetCaller)
+	for i, ctx := range ev.ctxs {
+		ev.ctxs[i] = ctx.SetCaller(file, line)
+	}
+	return ev
 }
 
 func (ev event) WithString(key string, value string) Event {
@@ -273,6 +363,17 @@ func (ev event) WithError(value error) Event {
 	return withFunc(ev, value, Context.SetError)
 }
 
+func (ev event) WithStack() Event {
+	if len(ev.ctxs) == 0 {
+		return ev
+	}
+	return withFunc(ev, CaptureStack(1), setStack)
+}
+
+func (ev event) WithStackFrames(stack []StackFrame) Event {
+	return withFunc(ev, stack, setStack)
+}
+
 func (ev event) WithTime(key string, value time.Time) Event {
 	return withKeyedFunc(ev, key, value, Context.AppendTime)
 }
@@ -281,6 +382,10 @@ func (ev event) WithDuration(key string, value time.Duration) Event {
 	return withKeyedFunc(ev, key, value, Context.AppendDuration)
 }
 
+func (ev event) WithObject(key string, fn func(ObjectEncoder)) Event {
+	return withKeyedFunc(ev, key, fn, appendObject)
+}
+
 func (ev event) with(f func(Context) Context) Event {
 	for i, ctx := range ev.ctxs {
 		ev.ctxs[i] = f(ctx)
@@ -300,6 +405,13 @@ func withFunc[T any](ev event, value T, f contextFunc[T]) event {
 type contextKeyedFunc[T any] func(ctx Context, key string, value T) Context
 
 func withKeyedFunc[T any](ev event, key string, value T, f contextKeyedFunc[T]) event {
+	if max := atomic.LoadInt64(&maxFieldsPerEvent); max > 0 && len(ev.ctxs) > 0 {
+		if int64(ev.fields) >= max {
+			ev.dropped++
+			return ev
+		}
+		ev.fields++
+	}
 	for i, ctx := range ev.ctxs {
 		ev.ctxs[i] = f(ctx, key, value)
 	}
@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Field is a single key-value pair that can be added to an Event using
+// Event.WithFields.
+//
+// The value is added using the Event.With... method matching its type, such
+// as Event.WithInt for int values. Error values are added as strings of their
+// error message using the field's key, instead of via Event.WithError, so that
+// multiple error fields don't override each other. Any unsupported type is
+// formatted into a string using fmt.Sprint.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// FieldsProvider is the signature of a function that provides fields from a
+// context.Context, such as a request ID stored there by a HTTP middleware.
+type FieldsProvider func(ctx context.Context) []Field
+
+var fieldsProviders []FieldsProvider
+
+// RegisterFieldsProvider registers a fields provider globally. All registered
+// providers are used by Event.WithProvidedFields.
+//
+// Meant to be used by integrations, such as the ones found in the ginutil and
+// gormutil packages, so that fields like request IDs are propagated to all
+// logs that have access to the same context.Context.
+//
+// This function is not safe to call concurrently with logging, and should be
+// called while initializing the application.
+func RegisterFieldsProvider(provider FieldsProvider) {
+	fieldsProviders = append(fieldsProviders, provider)
+}
+
+// ClearFieldsProviders resets the providers added by RegisterFieldsProvider.
+// Should not be needed in production code, but is quite useful to be called at
+// the beginning of an example test.
+func ClearFieldsProviders() {
+	fieldsProviders = nil
+}
+
+func (ev event) WithFields(fields ...Field) Event {
+	if len(ev.ctxs) == 0 {
+		return ev
+	}
+	var e Event = ev
+	for _, f := range fields {
+		e = withField(e, f)
+	}
+	return e
+}
+
+func (ev event) WithProvidedFields(ctx context.Context) Event {
+	if len(ev.ctxs) == 0 || ctx == nil {
+		return ev
+	}
+	var e Event = ev
+	for _, provider := range fieldsProviders {
+		e = e.WithFields(provider(ctx)...)
+	}
+	return e
+}
+
+func withField(ev Event, f Field) Event {
+	switch v := f.Value.(type) {
+	case string:
+		return ev.WithString(f.Key, v)
+	case bool:
+		return ev.WithBool(f.Key, v)
+	case int:
+		return ev.WithInt(f.Key, v)
+	case int32:
+		return ev.WithInt32(f.Key, v)
+	case int64:
+		return ev.WithInt64(f.Key, v)
+	case uint:
+		return ev.WithUint(f.Key, v)
+	case uint32:
+		return ev.WithUint32(f.Key, v)
+	case uint64:
+		return ev.WithUint64(f.Key, v)
+	case float32:
+		return ev.WithFloat32(f.Key, v)
+	case float64:
+		return ev.WithFloat64(f.Key, v)
+	case time.Time:
+		return ev.WithTime(f.Key, v)
+	case time.Duration:
+		return ev.WithDuration(f.Key, v)
+	case error:
+		return ev.WithString(f.Key, v.Error())
+	case fmt.Stringer:
+		return ev.WithStringer(f.Key, v)
+	default:
+		return ev.WithString(f.Key, fmt.Sprint(v))
+	}
+}
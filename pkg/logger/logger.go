@@ -3,15 +3,24 @@ package logger
 import (
 	"io"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
 )
 
 var (
-	minGlobalLevel  = LevelDebug
-	minScopedLevels = make(map[string]Level)
-	registeredSinks []registeredSink
+	// levelsMu guards minGlobalLevel and minScopedLevels, so that the logging
+	// levels can be changed at runtime, such as from an admin endpoint.
+	levelsMu          sync.RWMutex
+	minGlobalLevel    = LevelDebug
+	minScopedLevels   = make(map[string]Level)
+	registeredSinks   []registeredSink
+	// maxFieldsPerEvent is only accessed atomically, as it is read each time
+	// a field is added to an event.
+	maxFieldsPerEvent int64
 
 	// LongestScopeNameLength is updated whenever NewScoped is called, and is
-	// the string length of longest scope created. Useful when logging to align
+	// the number of characters in the longest scope created. Useful when logging to align
 	// the scopes in the output by padding to obtain this width.
 	LongestScopeNameLength int
 )
@@ -21,7 +30,9 @@ var (
 //
 // If LevelSilence is used, then all logs will be disabled.
 func SetLevel(level Level) {
+	levelsMu.Lock()
 	minGlobalLevel = level
+	levelsMu.Unlock()
 }
 
 // SetLevelScoped will suppress all events for a given scope that has a logging
@@ -34,10 +45,33 @@ func SetLevel(level Level) {
 //
 // If LevelSilence is used, then this scope will be completely disabled.
 func SetLevelScoped(level Level, scope string) {
+	levelsMu.Lock()
 	minScopedLevels[strings.ToUpper(scope)] = level
+	levelsMu.Unlock()
+}
+
+// DroppedFieldsKey is the field key added to log events that had fields
+// dropped due to the limit set via SetMaxFieldsPerEvent, with the number of
+// dropped fields as its value.
+const DroppedFieldsKey = "droppedFields"
+
+// SetMaxFieldsPerEvent limits the number of fields that can be added to a
+// single log event. Any fields added beyond this limit are dropped, and the
+// number of dropped fields is instead added as the field "droppedFields".
+//
+// This protects the logging sinks and log collectors from call sites that
+// attach an unbounded number of fields, such as from within a loop.
+//
+// The error set via Event.WithError and the caller set via Event.WithCaller
+// are not counted as fields. A value of zero or less disables the limit,
+// which is the default.
+func SetMaxFieldsPerEvent(max int) {
+	atomic.StoreInt64(&maxFieldsPerEvent, int64(max))
 }
 
 func getLevelScoped(scope string) Level {
+	levelsMu.RLock()
+	defer levelsMu.RUnlock()
 	if level, ok := minScopedLevels[strings.ToUpper(scope)]; ok && level > minGlobalLevel {
 		return level
 	}
@@ -129,8 +163,8 @@ func New() Logger {
 // 	logger.NewScoped("GIN") // use when registering logger to gin-gonic
 // 	logger.New() // use in the apps top-level domain
 func NewScoped(scope string) Logger {
-	if len(scope) > LongestScopeNameLength {
-		LongestScopeNameLength = len(scope)
+	if n := utf8.RuneCountInString(scope); n > LongestScopeNameLength {
+		LongestScopeNameLength = n
 	}
 	return logger{
 		newEvent: func(level Level, done DoneFunc) Event {
@@ -1,15 +1,24 @@
 package logger
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func reset() {
 	minGlobalLevel = LevelDebug
 	minScopedLevels = make(map[string]Level)
 	ClearOutputs()
+	ClearFieldsProviders()
+	SetFieldNamespacing(false)
+	SetMaxFieldsPerEvent(0)
 }
 
 func TestSetLevel(t *testing.T) {
@@ -65,3 +74,117 @@ func TestLevelSilenceScoped(t *testing.T) {
 
 	assert.ElementsMatch(t, mock.LogMessages, []string{"Logged"})
 }
+
+type testCtxKey struct{}
+
+func TestWithProvidedFields(t *testing.T) {
+	t.Cleanup(reset)
+
+	RegisterFieldsProvider(func(ctx context.Context) []Field {
+		if reqID, ok := ctx.Value(testCtxKey{}).(string); ok {
+			return []Field{{Key: "reqId", Value: reqID}}
+		}
+		return nil
+	})
+	RegisterFieldsProvider(func(context.Context) []Field {
+		return []Field{{Key: "count", Value: 5}}
+	})
+
+	mock := NewMock()
+	ctx := context.WithValue(context.Background(), testCtxKey{}, "abc")
+	mock.Info().WithProvidedFields(ctx).Message("")
+	mock.Info().WithProvidedFields(context.Background()).Message("")
+
+	require.Len(t, mock.Logs, 2)
+	assert.Equal(t, "abc", mock.Logs[0].Fields["reqId"])
+	assert.Equal(t, 5, mock.Logs[0].Fields["count"])
+	assert.NotContains(t, mock.Logs[1].Fields, "reqId")
+	assert.Equal(t, 5, mock.Logs[1].Fields["count"])
+}
+
+func TestWithFields(t *testing.T) {
+	mock := NewMock()
+	err := errors.New("some error")
+	mock.Info().WithFields(
+		Field{"str", "foo"},
+		Field{"int", 12},
+		Field{"dur", time.Second},
+		Field{"err", err},
+		Field{"other", []int{1, 2}},
+	).Message("")
+
+	require.Len(t, mock.Logs, 1)
+	fields := mock.Logs[0].Fields
+	assert.Equal(t, "foo", fields["str"])
+	assert.Equal(t, 12, fields["int"])
+	assert.Equal(t, time.Second, fields["dur"])
+	assert.Equal(t, "some error", fields["err"])
+	assert.Equal(t, "[1 2]", fields["other"])
+}
+
+func TestWithFields_multipleErrors(t *testing.T) {
+	mock := NewMock()
+	mock.Info().WithFields(
+		Field{"dbError", errors.New("db error")},
+		Field{"cacheError", errors.New("cache error")},
+	).Message("")
+
+	require.Len(t, mock.Logs, 1)
+	fields := mock.Logs[0].Fields
+	assert.Equal(t, "db error", fields["dbError"])
+	assert.Equal(t, "cache error", fields["cacheError"])
+	assert.NotContains(t, fields, "error")
+}
+
+func TestNamespacedKey(t *testing.T) {
+	t.Cleanup(reset)
+
+	assert.Equal(t, "method", NamespacedKey(NamespaceHTTP, "method"))
+	SetFieldNamespacing(true)
+	assert.Equal(t, "http.method", NamespacedKey(NamespaceHTTP, "method"))
+	assert.Equal(t, "method", NamespacedKey("", "method"))
+}
+
+func TestSetMaxFieldsPerEvent(t *testing.T) {
+	t.Cleanup(reset)
+
+	mock := NewMock()
+	AddOutput(LevelDebug, mock)
+	SetMaxFieldsPerEvent(2)
+
+	ev := New().Info().WithError(errors.New("some error"))
+	for i := 0; i < 5; i++ {
+		ev = ev.WithInt(fmt.Sprintf("field%d", i), i)
+	}
+	ev.Message("Capped")
+
+	require.Len(t, mock.Logs, 1)
+	fields := mock.Logs[0].Fields
+	assert.Equal(t, 0, fields["field0"])
+	assert.Equal(t, 1, fields["field1"])
+	assert.NotContains(t, fields, "field2")
+	assert.Equal(t, 3, fields[DroppedFieldsKey])
+	assert.Contains(t, fields, "error")
+	assert.Contains(t, fields, "caller")
+}
+
+func TestSetMaxFieldsPerEvent_concurrent(t *testing.T) {
+	t.Cleanup(reset)
+
+	AddOutput(LevelDebug, NewMock())
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			SetMaxFieldsPerEvent(i % 3)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			New().Info().WithInt("first", i).WithInt("second", i).Message("")
+		}
+	}()
+	wg.Wait()
+}
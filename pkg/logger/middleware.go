@@ -0,0 +1,296 @@
+package logger
+
+import "time"
+
+// SinkMiddleware is a function that wraps a Sink to alter its behavior, such as
+// filtering, sampling, or redacting log events, before they reach the wrapped
+// sink.
+//
+// Use NewContextMiddleware to create a middleware from a set of hooks without
+// having to implement the full Context interface, or WrapContext when more
+// control is needed:
+//
+// 	redact := logger.NewContextMiddleware(logger.ContextHooks{
+// 		String: func(key, value string) string {
+// 			if key == "password" {
+// 				return "*****"
+// 			}
+// 			return value
+// 		},
+// 	})
+// 	logger.AddOutput(logger.LevelDebug, logger.WrapSink(consolepretty.Default, redact))
+type SinkMiddleware func(next Sink) Sink
+
+// WrapSink wraps the sink with all of the middlewares. The first middleware is
+// the outermost, meaning it sees each log event before the others do.
+func WrapSink(sink Sink, middlewares ...SinkMiddleware) Sink {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		sink = middlewares[i](sink)
+	}
+	return sink
+}
+
+// SinkFunc is a function that implements the Sink interface.
+type SinkFunc func(scope string) Context
+
+// NewContext calls the function itself.
+func (f SinkFunc) NewContext(scope string) Context {
+	return f(scope)
+}
+
+// ContextHooks holds optional functions that are called by the Context
+// returned by WrapContext before forwarding calls to the wrapped Context.
+// Any unset hook is skipped, and SetCaller is always forwarded as-is.
+type ContextHooks struct {
+	// Field is called with the key of each field added via the Append...
+	// methods, and of each field of nested objects added via AppendObject,
+	// where nested fields are given with their key within the object. The
+	// returned key is used instead, which allows renaming the field, and if
+	// false is returned then the field is dropped.
+	Field func(key string) (string, bool)
+	// String is called with the key and value of each field added via
+	// AppendString, and of each string field of nested objects, after the
+	// Field hook. The returned value is used instead,
+	// which allows redacting sensitive values.
+	String func(key, value string) string
+	// Error is called with the error set via SetError. The returned error is
+	// used instead, where nil unsets the error.
+	Error func(err error) error
+	// WriteOut is called instead of the wrapped context's WriteOut. Call
+	// next.WriteOut to write out the log event, or skip calling it to drop
+	// the log event, such as when sampling.
+	WriteOut func(next Context, level Level, message string)
+}
+
+// NewContextMiddleware creates a SinkMiddleware that wraps each context
+// created by the next sink using WrapContext and the given hooks.
+func NewContextMiddleware(hooks ContextHooks) SinkMiddleware {
+	return func(next Sink) Sink {
+		return SinkFunc(func(scope string) Context {
+			return WrapContext(next.NewContext(scope), hooks)
+		})
+	}
+}
+
+// WrapContext creates a Context that forwards all calls to the wrapped
+// Context, while first passing them through the given hooks.
+func WrapContext(ctx Context, hooks ContextHooks) Context {
+	return hookCtx{ctx, &hooks}
+}
+
+type hookCtx struct {
+	inner Context
+	hooks *ContextHooks
+}
+
+func (c hookCtx) WriteOut(level Level, message string) {
+	if c.hooks.WriteOut != nil {
+		c.hooks.WriteOut(c.inner, level, message)
+		return
+	}
+	c.inner.WriteOut(level, message)
+}
+
+// TryWriteOut implements FallibleContext, so that a wrapped context can still
+// be used as the primary sink of NewFailover. Any error from the wrapped
+// context is returned, while wrapped contexts that do not implement
+// FallibleContext always succeed.
+func (c hookCtx) TryWriteOut(level Level, message string) error {
+	fallible, ok := c.inner.(FallibleContext)
+	if !ok {
+		c.WriteOut(level, message)
+		return nil
+	}
+	if c.hooks.WriteOut == nil {
+		return fallible.TryWriteOut(level, message)
+	}
+	var err error
+	c.hooks.WriteOut(fallibleWriteOutCtx{fallible, &err}, level, message)
+	return err
+}
+
+// fallibleWriteOutCtx is passed to the ContextHooks.WriteOut hook from
+// hookCtx.TryWriteOut, to capture the error of the wrapped context.
+type fallibleWriteOutCtx struct {
+	FallibleContext
+	err *error
+}
+
+func (c fallibleWriteOutCtx) WriteOut(level Level, message string) {
+	*c.err = c.TryWriteOut(level, message)
+}
+
+func (c hookCtx) key(key string) (string, bool) {
+	if c.hooks.Field == nil {
+		return key, true
+	}
+	return c.hooks.Field(key)
+}
+
+func (c hookCtx) SetCaller(file string, line int) Context {
+	c.inner = c.inner.SetCaller(file, line)
+	return c
+}
+
+func (c hookCtx) SetCallerFrame(frame StackFrame) Context {
+	c.inner = setCallerFrame(c.inner, frame)
+	return c
+}
+
+func (c hookCtx) SetError(v error) Context {
+	if c.hooks.Error != nil {
+		v = c.hooks.Error(v)
+	}
+	c.inner = c.inner.SetError(v)
+	return c
+}
+
+func (c hookCtx) SetStack(v []StackFrame) Context {
+	c.inner = setStack(c.inner, v)
+	return c
+}
+
+func (c hookCtx) AppendString(k string, v string) Context {
+	if c.hooks.String != nil {
+		return appendHooked(c, k, v, func(ctx Context, k string, v string) Context {
+			return ctx.AppendString(k, c.hooks.String(k, v))
+		})
+	}
+	return appendHooked(c, k, v, Context.AppendString)
+}
+
+func (c hookCtx) AppendRune(k string, v rune) Context {
+	return appendHooked(c, k, v, Context.AppendRune)
+}
+
+func (c hookCtx) AppendBool(k string, v bool) Context {
+	return appendHooked(c, k, v, Context.AppendBool)
+}
+
+func (c hookCtx) AppendInt(k string, v int) Context {
+	return appendHooked(c, k, v, Context.AppendInt)
+}
+
+func (c hookCtx) AppendInt32(k string, v int32) Context {
+	return appendHooked(c, k, v, Context.AppendInt32)
+}
+
+func (c hookCtx) AppendInt64(k string, v int64) Context {
+	return appendHooked(c, k, v, Context.AppendInt64)
+}
+
+func (c hookCtx) AppendUint(k string, v uint) Context {
+	return appendHooked(c, k, v, Context.AppendUint)
+}
+
+func (c hookCtx) AppendUint32(k string, v uint32) Context {
+	return appendHooked(c, k, v, Context.AppendUint32)
+}
+
+func (c hookCtx) AppendUint64(k string, v uint64) Context {
+	return appendHooked(c, k, v, Context.AppendUint64)
+}
+
+func (c hookCtx) AppendFloat32(k string, v float32) Context {
+	return appendHooked(c, k, v, Context.AppendFloat32)
+}
+
+func (c hookCtx) AppendFloat64(k string, v float64) Context {
+	return appendHooked(c, k, v, Context.AppendFloat64)
+}
+
+func (c hookCtx) AppendTime(k string, v time.Time) Context {
+	return appendHooked(c, k, v, Context.AppendTime)
+}
+
+func (c hookCtx) AppendDuration(k string, v time.Duration) Context {
+	return appendHooked(c, k, v, Context.AppendDuration)
+}
+
+func appendHooked[T any](c hookCtx, key string, value T, f contextKeyedFunc[T]) Context {
+	key, ok := c.key(key)
+	if !ok {
+		return c
+	}
+	c.inner = f(c.inner, key, value)
+	return c
+}
+
+func (c hookCtx) AppendObject(k string, v func(ObjectEncoder)) Context {
+	return appendHooked(c, k, func(enc ObjectEncoder) {
+		v(hookEncoder{enc, c.hooks})
+	}, appendObject)
+}
+
+// hookEncoder passes the fields of nested objects through the same Field and
+// String hooks as the fields added directly to the hookCtx.
+type hookEncoder struct {
+	inner ObjectEncoder
+	hooks *ContextHooks
+}
+
+func (e hookEncoder) key(key string) (string, bool) {
+	if e.hooks.Field == nil {
+		return key, true
+	}
+	return e.hooks.Field(key)
+}
+
+func (e hookEncoder) AddString(k string, v string) {
+	if k, ok := e.key(k); ok {
+		if e.hooks.String != nil {
+			v = e.hooks.String(k, v)
+		}
+		e.inner.AddString(k, v)
+	}
+}
+
+func (e hookEncoder) AddBool(k string, v bool) {
+	if k, ok := e.key(k); ok {
+		e.inner.AddBool(k, v)
+	}
+}
+
+func (e hookEncoder) AddInt(k string, v int) {
+	if k, ok := e.key(k); ok {
+		e.inner.AddInt(k, v)
+	}
+}
+
+func (e hookEncoder) AddInt64(k string, v int64) {
+	if k, ok := e.key(k); ok {
+		e.inner.AddInt64(k, v)
+	}
+}
+
+func (e hookEncoder) AddUint64(k string, v uint64) {
+	if k, ok := e.key(k); ok {
+		e.inner.AddUint64(k, v)
+	}
+}
+
+func (e hookEncoder) AddFloat64(k string, v float64) {
+	if k, ok := e.key(k); ok {
+		e.inner.AddFloat64(k, v)
+	}
+}
+
+func (e hookEncoder) AddTime(k string, v time.Time) {
+	if k, ok := e.key(k); ok {
+		e.inner.AddTime(k, v)
+	}
+}
+
+func (e hookEncoder) AddDuration(k string, v time.Duration) {
+	if k, ok := e.key(k); ok {
+		e.inner.AddDuration(k, v)
+	}
+}
+
+func (e hookEncoder) AddObject(k string, fn func(ObjectEncoder)) {
+	if k, ok := e.key(k); ok {
+		e.inner.AddObject(k, func(enc ObjectEncoder) {
+			fn(hookEncoder{enc, e.hooks})
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapSink_order(t *testing.T) {
+	var calls []string
+	newMiddleware := func(name string) SinkMiddleware {
+		return NewContextMiddleware(ContextHooks{
+			WriteOut: func(next Context, level Level, message string) {
+				calls = append(calls, name)
+				next.WriteOut(level, message)
+			},
+		})
+	}
+	mock := NewMock()
+	sink := WrapSink(mock, newMiddleware("first"), newMiddleware("second"))
+
+	sink.NewContext("").WriteOut(LevelInfo, "Logged")
+
+	assert.Equal(t, []string{"first", "second"}, calls)
+	assert.Equal(t, []string{"Logged"}, mock.LogMessages)
+}
+
+func TestNewContextMiddleware(t *testing.T) {
+	t.Cleanup(reset)
+
+	mock := NewMock()
+	AddOutput(LevelDebug, WrapSink(mock, NewContextMiddleware(ContextHooks{
+		Field: func(key string) (string, bool) {
+			if key == "dropped" {
+				return "", false
+			}
+			return strings.ToUpper(key), true
+		},
+		String: func(key, value string) string {
+			if key == "PASSWORD" {
+				return "*****"
+			}
+			return value
+		},
+		Error: func(err error) error {
+			return errors.New("redacted")
+		},
+		WriteOut: func(next Context, level Level, message string) {
+			if level >= LevelInfo {
+				next.WriteOut(level, message)
+			}
+		},
+	})))
+
+	log := New()
+	log.Debug().Message("Sampled away")
+	log.Info().
+		WithString("password", "hunter2").
+		WithInt("dropped", 1).
+		WithInt("count", 2).
+		WithError(errors.New("secret")).
+		Message("Logged")
+
+	assert.Equal(t, []string{"Logged"}, mock.LogMessages)
+	require.Len(t, mock.Logs, 1)
+	fields := mock.Logs[0].Fields
+	assert.Equal(t, "*****", fields["PASSWORD"])
+	assert.Equal(t, 2, fields["COUNT"])
+	assert.NotContains(t, fields, "dropped")
+	assert.NotContains(t, fields, "DROPPED")
+	assert.EqualError(t, fields["error"].(error), "redacted")
+}
+
+func TestNewContextMiddleware_nestedObject(t *testing.T) {
+	t.Cleanup(reset)
+
+	mock := NewMock()
+	AddOutput(LevelDebug, WrapSink(mock, NewContextMiddleware(ContextHooks{
+		Field: func(key string) (string, bool) {
+			return key, key != "dropped"
+		},
+		String: func(key, value string) string {
+			if key == "password" {
+				return "*****"
+			}
+			return value
+		},
+	})))
+
+	New().Info().
+		WithObject("req", func(enc ObjectEncoder) {
+			enc.AddString("user", "alice")
+			enc.AddInt("dropped", 1)
+			enc.AddObject("auth", func(enc ObjectEncoder) {
+				enc.AddString("password", "hunter2")
+			})
+		}).
+		Message("")
+
+	require.Len(t, mock.Logs, 1)
+	assert.Equal(t, map[string]any{
+		"user": "alice",
+		"auth": map[string]any{"password": "*****"},
+	}, mock.Logs[0].Fields["req"])
+}
+
+func TestNewContextMiddleware_failover(t *testing.T) {
+	testCases := []struct {
+		name  string
+		hooks ContextHooks
+	}{
+		{
+			name: "without WriteOut hook",
+		},
+		{
+			name: "with WriteOut hook",
+			hooks: ContextHooks{
+				WriteOut: func(next Context, level Level, message string) {
+					next.WriteOut(level, message)
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			primary := NewMock()
+			secondary := NewMock()
+			primaryErr := errors.New("sink is down")
+			sink := NewFailover(
+				WrapSink(fallibleMock{primary, primaryErr}, NewContextMiddleware(tc.hooks)),
+				secondary)
+
+			sink.NewContext("").WriteOut(LevelInfo, "Logged")
+
+			assert.Empty(t, primary.LogMessages)
+			require.Len(t, secondary.Logs, 1)
+			assert.Equal(t, "Logged", secondary.Logs[0].Message)
+			assert.Equal(t, primaryErr.Error(), secondary.Logs[0].Fields[FailoverErrorKey])
+		})
+	}
+}
@@ -49,13 +49,18 @@ type MockLog struct {
 	//
 	// 	Event.SetScope("foo")   => MockLog.Fields["scope"] = "foo"
 	// 	Event.SetError(someErr) => MockLog.Fields["error"] = someErr
+	// 	Event.SetStack(frames)  => MockLog.Fields["stack"] = frames
 	// 	Event.SetCaller("foo", 42)
 	// 		=> MockLog.Fields["caller"] = "foo"
 	// 		=> MockLog.Fields["line"] = 42
+	//
+	// Nested objects added via Event.WithObject are stored as values of type
+	// map[string]any.
 	Fields map[string]any
 	// FieldsAdded is a slice of strings with all the keys added to the Fields
 	// map. This includes the custom mapping of Event.SetScope,
-	// Event.SetError, and Event.SetCaller as mentioned in the Fields docs.
+	// Event.SetError, Event.SetStack, and Event.SetCaller as mentioned in the
+	// Fields docs.
 	//
 	// If a field is added more than one time, then it will show up in this list
 	// equally many times. Useful for checking if fields are misstakenly added
@@ -116,6 +121,7 @@ func (c mockCtx) SetCaller(file string, line int) Context {
 }
 
 func (c mockCtx) SetError(v error) Context                         { return c.addField("error", v) }
+func (c mockCtx) SetStack(v []StackFrame) Context                  { return c.addField("stack", v) }
 func (c mockCtx) AppendString(k string, v string) Context          { return c.addField(k, v) }
 func (c mockCtx) AppendRune(k string, v rune) Context              { return c.addField(k, v) }
 func (c mockCtx) AppendBool(k string, v bool) Context              { return c.addField(k, v) }
@@ -129,6 +135,21 @@ func (c mockCtx) AppendFloat32(k string, v float32) Context        { return c.ad
 func (c mockCtx) AppendFloat64(k string, v float64) Context        { return c.addField(k, v) }
 func (c mockCtx) AppendTime(k string, v time.Time) Context         { return c.addField(k, v) }
 func (c mockCtx) AppendDuration(k string, v time.Duration) Context { return c.addField(k, v) }
+func (c mockCtx) AppendObject(k string, v func(ObjectEncoder)) Context {
+	return c.addField(k, mockObject(ObjectFields(v)))
+}
+
+func mockObject(fields []Field) map[string]any {
+	obj := make(map[string]any, len(fields))
+	for _, f := range fields {
+		if nested, ok := f.Value.([]Field); ok {
+			obj[f.Key] = mockObject(nested)
+		} else {
+			obj[f.Key] = f.Value
+		}
+	}
+	return obj
+}
 
 func (c mockCtx) addField(key string, value any) Context {
 	c.Fields[key] = value
@@ -0,0 +1,106 @@
+package logger
+
+import "time"
+
+// multiCtx is a Context that forwards all calls to multiple contexts, and lets
+// its writeOut function decide which of them to write out. Nil contexts are
+// skipped, but are still passed to writeOut to retain their positions.
+type multiCtx struct {
+	ctxs     []Context
+	writeOut func(ctxs []Context, level Level, message string)
+}
+
+func newMultiContext(scope string, sinks []Sink, writeOut func([]Context, Level, string)) Context {
+	ctxs := make([]Context, len(sinks))
+	for i, sink := range sinks {
+		if sink != nil {
+			ctxs[i] = sink.NewContext(scope)
+		}
+	}
+	return multiCtx{ctxs, writeOut}
+}
+
+func (c multiCtx) WriteOut(level Level, message string) {
+	c.writeOut(c.ctxs, level, message)
+}
+
+func (c multiCtx) each(f func(Context) Context) Context {
+	for i, ctx := range c.ctxs {
+		if ctx != nil {
+			c.ctxs[i] = f(ctx)
+		}
+	}
+	return c
+}
+
+func (c multiCtx) SetCaller(file string, line int) Context {
+	return c.each(func(ctx Context) Context { return ctx.SetCaller(file, line) })
+}
+
+func (c multiCtx) SetCallerFrame(frame StackFrame) Context {
+	return c.each(func(ctx Context) Context { return setCallerFrame(ctx, frame) })
+}
+
+func (c multiCtx) SetError(v error) Context {
+	return c.each(func(ctx Context) Context { return ctx.SetError(v) })
+}
+
+func (c multiCtx) SetStack(v []StackFrame) Context {
+	return c.each(func(ctx Context) Context { return setStack(ctx, v) })
+}
+
+func (c multiCtx) AppendString(k string, v string) Context {
+	return c.each(func(ctx Context) Context { return ctx.AppendString(k, v) })
+}
+
+func (c multiCtx) AppendRune(k string, v rune) Context {
+	return c.each(func(ctx Context) Context { return ctx.AppendRune(k, v) })
+}
+
+func (c multiCtx) AppendBool(k string, v bool) Context {
+	return c.each(func(ctx Context) Context { return ctx.AppendBool(k, v) })
+}
+
+func (c multiCtx) AppendInt(k string, v int) Context {
+	return c.each(func(ctx Context) Context { return ctx.AppendInt(k, v) })
+}
+
+func (c multiCtx) AppendInt32(k string, v int32) Context {
+	return c.each(func(ctx Context) Context { return ctx.AppendInt32(k, v) })
+}
+
+func (c multiCtx) AppendInt64(k string, v int64) Context {
+	return c.each(func(ctx Context) Context { return ctx.AppendInt64(k, v) })
+}
+
+func (c multiCtx) AppendUint(k string, v uint) Context {
+	return c.each(func(ctx Context) Context { return ctx.AppendUint(k, v) })
+}
+
+func (c multiCtx) AppendUint32(k string, v uint32) Context {
+	return c.each(func(ctx Context) Context { return ctx.AppendUint32(k, v) })
+}
+
+func (c multiCtx) AppendUint64(k string, v uint64) Context {
+	return c.each(func(ctx Context) Context { return ctx.AppendUint64(k, v) })
+}
+
+func (c multiCtx) AppendFloat32(k string, v float32) Context {
+	return c.each(func(ctx Context) Context { return ctx.AppendFloat32(k, v) })
+}
+
+func (c multiCtx) AppendFloat64(k string, v float64) Context {
+	return c.each(func(ctx Context) Context { return ctx.AppendFloat64(k, v) })
+}
+
+func (c multiCtx) AppendTime(k string, v time.Time) Context {
+	return c.each(func(ctx Context) Context { return ctx.AppendTime(k, v) })
+}
+
+func (c multiCtx) AppendDuration(k string, v time.Duration) Context {
+	return c.each(func(ctx Context) Context { return ctx.AppendDuration(k, v) })
+}
+
+func (c multiCtx) AppendObject(k string, v func(ObjectEncoder)) Context {
+	return c.each(func(ctx Context) Context { return appendObject(ctx, k, v) })
+}
@@ -0,0 +1,41 @@
+package logger
+
+// Namespaces used by the integrations in wharf-core when prefixing their
+// field names via NamespacedKey.
+const (
+	// NamespaceHTTP is the namespace used for fields about HTTP requests and
+	// responses, such as by the ginutil package.
+	NamespaceHTTP = "http"
+	// NamespaceDB is the namespace used for fields about database queries,
+	// such as by the gormutil package.
+	NamespaceDB = "db"
+	// NamespaceMQ is the namespace used for fields about message queues.
+	NamespaceMQ = "mq"
+)
+
+var fieldNamespacing bool
+
+// SetFieldNamespacing enables or disables prefixing the field names added by
+// integrations with their namespace, so that field names from different
+// subsystems do not collide in aggregated log indexes.
+//
+// When disabled (which is the default):
+// 	{"level":"debug","method":"GET","status":200}
+// When enabled:
+// 	{"level":"debug","http.method":"GET","http.status":200}
+func SetFieldNamespacing(enabled bool) {
+	fieldNamespacing = enabled
+}
+
+// NamespacedKey returns the field name prefixed with the namespace and a dot,
+// if enabled via SetFieldNamespacing. Otherwise the field name is returned
+// as-is.
+//
+// Meant to be used by integrations when adding fields to their log events:
+// 	ev.WithString(logger.NamespacedKey(logger.NamespaceHTTP, "method"), method)
+func NamespacedKey(namespace, key string) string {
+	if !fieldNamespacing || namespace == "" {
+		return key
+	}
+	return namespace + "." + key
+}
@@ -0,0 +1,86 @@
+package logger
+
+import "time"
+
+// ObjectEncoder is used to add fields to a nested object, as created via
+// Event.WithObject and ObjectContext.AppendObject.
+//
+// Calling the Add... methods multiple times with the same key may lead to
+// unexpected behaviour.
+type ObjectEncoder interface {
+	// AddString adds a string field to the nested object.
+	AddString(key string, value string)
+	// AddBool adds a boolean field to the nested object.
+	AddBool(key string, value bool)
+	// AddInt adds an integer field to the nested object.
+	AddInt(key string, value int)
+	// AddInt64 adds an integer field to the nested object.
+	AddInt64(key string, value int64)
+	// AddUint64 adds an integer field to the nested object.
+	AddUint64(key string, value uint64)
+	// AddFloat64 adds a floating point number field to the nested object.
+	AddFloat64(key string, value float64)
+	// AddTime adds a timestamp field to the nested object.
+	AddTime(key string, value time.Time)
+	// AddDuration adds a time duration field to the nested object.
+	AddDuration(key string, value time.Duration)
+	// AddObject adds a nested object field to the nested object.
+	AddObject(key string, fn func(ObjectEncoder))
+}
+
+// FlattenObject is a helper for Context implementations that cannot render
+// nested objects. It adds all fields of the nested object directly to the
+// context, with the keys prefixed by the object's key and a dot, such as
+// "http.method" and "http.status".
+func FlattenObject(ctx Context, key string, fn func(ObjectEncoder)) Context {
+	enc := &flatEncoder{ctx, key + "."}
+	fn(enc)
+	return enc.ctx
+}
+
+type flatEncoder struct {
+	ctx    Context
+	prefix string
+}
+
+func (e *flatEncoder) AddString(k string, v string) { e.ctx = e.ctx.AppendString(e.prefix+k, v) }
+func (e *flatEncoder) AddBool(k string, v bool)     { e.ctx = e.ctx.AppendBool(e.prefix+k, v) }
+func (e *flatEncoder) AddInt(k string, v int)       { e.ctx = e.ctx.AppendInt(e.prefix+k, v) }
+func (e *flatEncoder) AddInt64(k string, v int64)   { e.ctx = e.ctx.AppendInt64(e.prefix+k, v) }
+func (e *flatEncoder) AddUint64(k string, v uint64) { e.ctx = e.ctx.AppendUint64(e.prefix+k, v) }
+func (e *flatEncoder) AddFloat64(k string, v float64) {
+	e.ctx = e.ctx.AppendFloat64(e.prefix+k, v)
+}
+func (e *flatEncoder) AddTime(k string, v time.Time) { e.ctx = e.ctx.AppendTime(e.prefix+k, v) }
+func (e *flatEncoder) AddDuration(k string, v time.Duration) {
+	e.ctx = e.ctx.AppendDuration(e.prefix+k, v)
+}
+func (e *flatEncoder) AddObject(k string, fn func(ObjectEncoder)) {
+	e.ctx = FlattenObject(e.ctx, e.prefix+k, fn)
+}
+
+// ObjectFields is a helper for Context implementations that store the fields
+// as values. It returns all fields of the nested object in the order they were
+// added, where the values of any nested objects are of type []Field.
+func ObjectFields(fn func(ObjectEncoder)) []Field {
+	enc := &fieldsEncoder{}
+	fn(enc)
+	return enc.fields
+}
+
+type fieldsEncoder struct {
+	fields []Field
+}
+
+func (e *fieldsEncoder) add(k string, v any)                   { e.fields = append(e.fields, Field{k, v}) }
+func (e *fieldsEncoder) AddString(k string, v string)          { e.add(k, v) }
+func (e *fieldsEncoder) AddBool(k string, v bool)              { e.add(k, v) }
+func (e *fieldsEncoder) AddInt(k string, v int)                { e.add(k, v) }
+func (e *fieldsEncoder) AddInt64(k string, v int64)            { e.add(k, v) }
+func (e *fieldsEncoder) AddUint64(k string, v uint64)          { e.add(k, v) }
+func (e *fieldsEncoder) AddFloat64(k string, v float64)        { e.add(k, v) }
+func (e *fieldsEncoder) AddTime(k string, v time.Time)         { e.add(k, v) }
+func (e *fieldsEncoder) AddDuration(k string, v time.Duration) { e.add(k, v) }
+func (e *fieldsEncoder) AddObject(k string, fn func(ObjectEncoder)) {
+	e.add(k, ObjectFields(fn))
+}
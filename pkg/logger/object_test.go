@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func addHTTPObject(enc ObjectEncoder) {
+	enc.AddString("method", "GET")
+	enc.AddInt("status", 200)
+	enc.AddObject("client", func(enc ObjectEncoder) {
+		enc.AddString("ip", "127.0.0.1")
+	})
+}
+
+func TestWithObject(t *testing.T) {
+	mock := NewMock()
+	mock.Info().WithObject("http", addHTTPObject).Message("")
+
+	require.Len(t, mock.Logs, 1)
+	assert.Equal(t, map[string]any{
+		"method": "GET",
+		"status": 200,
+		"client": map[string]any{"ip": "127.0.0.1"},
+	}, mock.Logs[0].Fields["http"])
+}
+
+func TestFlattenObject(t *testing.T) {
+	mock := NewMock()
+	FlattenObject(mock.NewContext(""), "http", addHTTPObject).WriteOut(LevelInfo, "")
+
+	require.Len(t, mock.Logs, 1)
+	assert.Equal(t, map[string]any{
+		"http.method":    "GET",
+		"http.status":    200,
+		"http.client.ip": "127.0.0.1",
+	}, mock.Logs[0].Fields)
+}
+
+// contextOnly hides the optional interfaces implemented by the inner context.
+type contextOnly struct {
+	Context
+}
+
+func TestAppendObject_flattensWithoutObjectContext(t *testing.T) {
+	mock := NewMock()
+	appendObject(contextOnly{mock.NewContext("")}, "http", addHTTPObject).
+		WriteOut(LevelInfo, "")
+
+	require.Len(t, mock.Logs, 1)
+	assert.Equal(t, map[string]any{
+		"http.method":    "GET",
+		"http.status":    200,
+		"http.client.ip": "127.0.0.1",
+	}, mock.Logs[0].Fields)
+}
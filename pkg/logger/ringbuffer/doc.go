@@ -0,0 +1,5 @@
+// Package ringbuffer is a concrete implementation of the logger.Sink and
+// logger.Context used for keeping the most recent log events in memory, so
+// they can be queried and presented via a debug endpoint for quick triage
+// without access to a log collector.
+package ringbuffer
@@ -0,0 +1,264 @@
+package ringbuffer
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+)
+
+// Config lets you configure the behavior of the ring buffer logging Sink.
+type Config struct {
+	// Size is the maximum number of log events kept in memory. When the buffer
+	// is full, the oldest event is discarded for each new event. Defaults to
+	// 1000.
+	Size int
+	// DisableCaller will omit the caller file and line from the stored events
+	// when set to true.
+	DisableCaller bool
+}
+
+// Event is a single stored log event.
+type Event struct {
+	// Level is the logging level of the event.
+	Level logger.Level
+	// Date is when the event was logged.
+	Date time.Time
+	// Scope is the scope of the logger that logged the event, if any.
+	Scope string
+	// Message is the final message of the event.
+	Message string
+	// Caller is the file name of where the event was logged from, if any.
+	Caller string
+	// Line is the line number of where the event was logged from, if any.
+	Line int
+	// Error is the error message of the error added to the event, if any.
+	Error string
+	// Stack is the stack trace added to the event, if any, with each frame
+	// formatted as "function (file:line)".
+	Stack []string
+	// Fields holds all fields added to the event, using the same value types
+	// as they were added with. Nested objects are stored as values of type
+	// map[string]any.
+	Fields map[string]any
+}
+
+// MarshalJSON implements json.Marshaler, and encodes the level as a string.
+func (ev Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Level   string         `json:"level"`
+		Date    time.Time      `json:"date"`
+		Scope   string         `json:"scope,omitempty"`
+		Message string         `json:"message"`
+		Caller  string         `json:"caller,omitempty"`
+		Line    int            `json:"line,omitempty"`
+		Error   string         `json:"error,omitempty"`
+		Stack   []string       `json:"stack,omitempty"`
+		Fields  map[string]any `json:"fields,omitempty"`
+	}{
+		Level:   ev.Level.String(),
+		Date:    ev.Date,
+		Scope:   ev.Scope,
+		Message: ev.Message,
+		Caller:  ev.Caller,
+		Line:    ev.Line,
+		Error:   ev.Error,
+		Stack:   ev.Stack,
+		Fields:  ev.Fields,
+	})
+}
+
+// Filter is used when querying the stored events. The zero value matches all
+// events.
+type Filter struct {
+	// MinLevel excludes all events with a lower logging level.
+	MinLevel logger.Level
+	// Scope only includes events from this scope, when set. The scope is
+	// case-insensitive.
+	Scope string
+	// Since excludes all events logged before this time, when set.
+	Since time.Time
+	// Until excludes all events logged after this time, when set.
+	Until time.Time
+	// Limit is the maximum number of events returned, where the most recent
+	// events are kept. Zero means no limit.
+	Limit int
+}
+
+// Match returns true if the event is matched by the filter. The Limit field is
+// not taken into account.
+func (f Filter) Match(ev Event) bool {
+	if ev.Level < f.MinLevel {
+		return false
+	}
+	if f.Scope != "" && !strings.EqualFold(f.Scope, ev.Scope) {
+		return false
+	}
+	if !f.Since.IsZero() && ev.Date.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && ev.Date.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Sink is a logging sink that keeps the most recent events in memory. It is
+// safe for concurrent use.
+type Sink struct {
+	config Config
+	mu     sync.RWMutex
+	events []Event
+	next   int
+	full   bool
+}
+
+// New creates a new ring buffer logging Sink.
+func New(conf Config) *Sink {
+	if conf.Size <= 0 {
+		conf.Size = 1000
+	}
+	return &Sink{
+		config: conf,
+		events: make([]Event, conf.Size),
+	}
+}
+
+// NewContext creates a new ring buffer logging Context that stores the event
+// in this Sink.
+func (s *Sink) NewContext(scope string) logger.Context {
+	return &context{sink: s, event: Event{Scope: scope}}
+}
+
+// Query returns the stored events that match the filter, ordered from oldest
+// to most recent.
+func (s *Sink) Query(f Filter) []Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []Event
+	s.each(func(ev Event) {
+		if f.Match(ev) {
+			result = append(result, ev)
+		}
+	})
+	if f.Limit > 0 && len(result) > f.Limit {
+		result = result[len(result)-f.Limit:]
+	}
+	return result
+}
+
+// Len returns the number of stored events.
+func (s *Sink) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.full {
+		return len(s.events)
+	}
+	return s.next
+}
+
+// Clear removes all stored events.
+func (s *Sink) Clear() {
+	s.mu.Lock()
+	s.events = make([]Event, len(s.events))
+	s.next = 0
+	s.full = false
+	s.mu.Unlock()
+}
+
+func (s *Sink) each(f func(Event)) {
+	if s.full {
+		for _, ev := range s.events[s.next:] {
+			f(ev)
+		}
+	}
+	for _, ev := range s.events[:s.next] {
+		f(ev)
+	}
+}
+
+func (s *Sink) add(ev Event) {
+	s.mu.Lock()
+	s.events[s.next] = ev
+	s.next++
+	if s.next == len(s.events) {
+		s.next = 0
+		s.full = true
+	}
+	s.mu.Unlock()
+}
+
+type context struct {
+	sink  *Sink
+	event Event
+}
+
+func (c *context) WriteOut(level logger.Level, message string) {
+	c.event.Level = level
+	c.event.Message = message
+	c.event.Date = time.Now()
+	c.sink.add(c.event)
+}
+
+func (c *context) SetCaller(file string, line int) logger.Context {
+	if !c.sink.config.DisableCaller {
+		c.event.Caller, c.event.Line = file, line
+	}
+	return c
+}
+
+func (c *context) SetError(value error) logger.Context {
+	if value == nil {
+		c.event.Error = ""
+	} else {
+		c.event.Error = value.Error()
+	}
+	return c
+}
+
+func (c *context) SetStack(value []logger.StackFrame) logger.Context {
+	c.event.Stack = nil
+	for _, frame := range value {
+		c.event.Stack = append(c.event.Stack, frame.String())
+	}
+	return c
+}
+
+func (c *context) AppendString(k string, v string) logger.Context          { return c.addField(k, v) }
+func (c *context) AppendRune(k string, v rune) logger.Context              { return c.addField(k, string(v)) }
+func (c *context) AppendBool(k string, v bool) logger.Context              { return c.addField(k, v) }
+func (c *context) AppendInt(k string, v int) logger.Context                { return c.addField(k, v) }
+func (c *context) AppendInt32(k string, v int32) logger.Context            { return c.addField(k, v) }
+func (c *context) AppendInt64(k string, v int64) logger.Context            { return c.addField(k, v) }
+func (c *context) AppendUint(k string, v uint) logger.Context              { return c.addField(k, v) }
+func (c *context) AppendUint32(k string, v uint32) logger.Context          { return c.addField(k, v) }
+func (c *context) AppendUint64(k string, v uint64) logger.Context          { return c.addField(k, v) }
+func (c *context) AppendFloat32(k string, v float32) logger.Context        { return c.addField(k, v) }
+func (c *context) AppendFloat64(k string, v float64) logger.Context        { return c.addField(k, v) }
+func (c *context) AppendTime(k string, v time.Time) logger.Context         { return c.addField(k, v) }
+func (c *context) AppendDuration(k string, v time.Duration) logger.Context { return c.addField(k, v) }
+func (c *context) AppendObject(k string, v func(logger.ObjectEncoder)) logger.Context {
+	return c.addField(k, objectMap(logger.ObjectFields(v)))
+}
+
+func objectMap(fields []logger.Field) map[string]any {
+	obj := make(map[string]any, len(fields))
+	for _, f := range fields {
+		if nested, ok := f.Value.([]logger.Field); ok {
+			obj[f.Key] = objectMap(nested)
+		} else {
+			obj[f.Key] = f.Value
+		}
+	}
+	return obj
+}
+
+func (c *context) addField(key string, value any) logger.Context {
+	if c.event.Fields == nil {
+		c.event.Fields = make(map[string]any)
+	}
+	c.event.Fields[key] = value
+	return c
+}
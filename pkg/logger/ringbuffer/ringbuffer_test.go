@@ -0,0 +1,110 @@
+package ringbuffer
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func logTo(sink logger.Sink, scope string, level logger.Level, message string) {
+	sink.NewContext(scope).AppendInt("id", 1).WriteOut(level, message)
+}
+
+func messages(events []Event) []string {
+	var msgs []string
+	for _, ev := range events {
+		msgs = append(msgs, ev.Message)
+	}
+	return msgs
+}
+
+func TestSink_wrapsAround(t *testing.T) {
+	sink := New(Config{Size: 3})
+	for i := 1; i <= 5; i++ {
+		logTo(sink, "", logger.LevelInfo, fmt.Sprint(i))
+	}
+	assert.Equal(t, 3, sink.Len())
+	assert.Equal(t, []string{"3", "4", "5"}, messages(sink.Query(Filter{})))
+
+	sink.Clear()
+	assert.Equal(t, 0, sink.Len())
+	assert.Empty(t, sink.Query(Filter{}))
+}
+
+func TestSink_Query(t *testing.T) {
+	sink := New(Config{})
+	logTo(sink, "GIN", logger.LevelDebug, "gin debug")
+	logTo(sink, "GIN", logger.LevelError, "gin error")
+	logTo(sink, "GORM", logger.LevelWarn, "gorm warn")
+	logTo(sink, "GORM", logger.LevelInfo, "gorm info")
+
+	testCases := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{
+			name:   "no filter",
+			filter: Filter{},
+			want:   []string{"gin debug", "gin error", "gorm warn", "gorm info"},
+		},
+		{
+			name:   "min level",
+			filter: Filter{MinLevel: logger.LevelWarn},
+			want:   []string{"gin error", "gorm warn"},
+		},
+		{
+			name:   "scope",
+			filter: Filter{Scope: "gorm"},
+			want:   []string{"gorm warn", "gorm info"},
+		},
+		{
+			name:   "limit keeps most recent",
+			filter: Filter{Limit: 2},
+			want:   []string{"gorm warn", "gorm info"},
+		},
+		{
+			name:   "until",
+			filter: Filter{Until: time.Now().Add(-time.Hour)},
+			want:   nil,
+		},
+		{
+			name:   "since",
+			filter: Filter{Since: time.Now().Add(-time.Hour), Scope: "GIN"},
+			want:   []string{"gin debug", "gin error"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, messages(sink.Query(tc.filter)))
+		})
+	}
+}
+
+func TestEvent_MarshalJSON(t *testing.T) {
+	sink := New(Config{})
+	sink.NewContext("TEST").
+		SetCaller("main.go", 12).
+		AppendString("foo", "bar").
+		WriteOut(logger.LevelWarn, "Hello")
+
+	events := sink.Query(Filter{})
+	require.Len(t, events, 1)
+	events[0].Date = time.Date(2021, 2, 9, 0, 0, 0, 0, time.UTC)
+	b, err := json.Marshal(events[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"level": "Warning",
+		"date": "2021-02-09T00:00:00Z",
+		"scope": "TEST",
+		"message": "Hello",
+		"caller": "main.go",
+		"line": 12,
+		"fields": {"foo": "bar"}
+	}`, string(b))
+}
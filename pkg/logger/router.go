@@ -0,0 +1,39 @@
+package logger
+
+// NewLevelRouter creates a Sink that sends all events of the given logging
+// level or higher to the high sink, and all other events to the low sink.
+// Either sink may be nil to discard those events.
+//
+// Useful to follow the convention of writing errors to STDERR and everything
+// else to STDOUT, which lets log collectors such as the one in Kubernetes
+// distinguish the two streams:
+//
+// 	logger.AddOutput(logger.LevelDebug, logger.NewLevelRouter(logger.LevelError,
+// 		consolepretty.New(consolepretty.Config{Writer: os.Stderr}),
+// 		consolepretty.New(consolepretty.Config{Writer: os.Stdout}),
+// 	))
+func NewLevelRouter(minLevel Level, high, low Sink) Sink {
+	return levelRouter{minLevel, high, low}
+}
+
+type levelRouter struct {
+	minLevel Level
+	high     Sink
+	low      Sink
+}
+
+func (r levelRouter) NewContext(scope string) Context {
+	// both contexts are populated, as the logging level is not known until
+	// WriteOut is called
+	return newMultiContext(scope, []Sink{r.high, r.low}, r.writeOut)
+}
+
+func (r levelRouter) writeOut(ctxs []Context, level Level, message string) {
+	ctx := ctxs[1]
+	if level >= r.minLevel {
+		ctx = ctxs[0]
+	}
+	if ctx != nil {
+		ctx.WriteOut(level, message)
+	}
+}
@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLevelRouter(t *testing.T) {
+	t.Cleanup(reset)
+
+	high := NewMock()
+	low := NewMock()
+	AddOutput(LevelDebug, NewLevelRouter(LevelError, high, low))
+
+	log := NewScoped("ROUTER")
+	log.Debug().Message("Debug")
+	log.Info().WithString("foo", "bar").Message("Info")
+	log.Warn().Message("Warn")
+	log.Error().WithString("foo", "bar").Message("Error")
+
+	assert.Equal(t, []string{"Error"}, high.LogMessages)
+	assert.Equal(t, []string{"Debug", "Info", "Warn"}, low.LogMessages)
+
+	require.Len(t, high.Logs, 1)
+	assert.Equal(t, "bar", high.Logs[0].Fields["foo"])
+	assert.Equal(t, "ROUTER", high.Logs[0].Fields["scope"])
+}
+
+func TestNewLevelRouter_nilSink(t *testing.T) {
+	t.Cleanup(reset)
+
+	high := NewMock()
+	AddOutput(LevelDebug, NewLevelRouter(LevelWarn, high, nil))
+
+	log := New()
+	log.Info().WithString("foo", "bar").Message("Info")
+	log.Warn().WithString("foo", "bar").Message("Warn")
+
+	assert.Equal(t, []string{"Warn"}, high.LogMessages)
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+)
+
+// maxStackDepth is the maximum number of frames captured by CaptureStack.
+const maxStackDepth = 64
+
+// StackFrame is a single function call in a stack trace.
+type StackFrame struct {
+	// Function is the fully qualified name of the function, such as
+	// "github.com/iver-wharf/wharf-core/v2/pkg/logger.CaptureStack".
+	Function string
+	// File is the full path of the source file of the function.
+	File string
+	// Line is the line number in the source file.
+	Line int
+}
+
+// String returns the frame in the form "function (file:line)".
+func (f StackFrame) String() string {
+	return f.Function + " (" + f.File + ":" + strconv.Itoa(f.Line) + ")"
+}
+
+// CaptureStack returns the stack trace of the calling goroutine. The argument
+// skip is the number of stack frames to skip, where 0 identifies the caller of
+// CaptureStack.
+func CaptureStack(skip int) []StackFrame {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	return CallersFrames(pcs[:n])
+}
+
+// CallersFrames converts the program counters, as returned by
+// runtime.Callers, into stack frames.
+func CallersFrames(pcs []uintptr) []StackFrame {
+	if len(pcs) == 0 {
+		return nil
+	}
+	stack := make([]StackFrame, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, StackFrame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+		})
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// StackCallers is implemented by errors that carry the program counters of
+// where they were created, such as the errors from the
+// github.com/go-errors/errors package.
+type StackCallers interface {
+	Callers() []uintptr
+}
+
+// ErrorStack returns the stack trace carried by the error or any error it
+// wraps, via the StackCallers interface. Returns nil if there is none.
+func ErrorStack(err error) []StackFrame {
+	var callers StackCallers
+	if errors.As(err, &callers) {
+		return CallersFrames(callers.Callers())
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type callersError struct {
+	pcs []uintptr
+}
+
+func (err callersError) Error() string      { return "callers error" }
+func (err callersError) Callers() []uintptr { return err.pcs }
+
+func newCallersError() error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return callersError{pcs[:n]}
+}
+
+func TestCaptureStack(t *testing.T) {
+	stack := CaptureStack(0)
+	require.NotEmpty(t, stack)
+	assert.True(t, strings.HasSuffix(stack[0].Function, ".TestCaptureStack"), stack[0].Function)
+	assert.True(t, strings.HasSuffix(stack[0].File, "stack_test.go"), stack[0].File)
+}
+
+func TestErrorStack(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", newCallersError())
+	stack := ErrorStack(err)
+	require.NotEmpty(t, stack)
+	assert.True(t, strings.HasSuffix(stack[0].Function, ".TestErrorStack"), stack[0].Function)
+
+	assert.Nil(t, ErrorStack(fmt.Errorf("no stack")))
+}
+
+func TestEvent_WithStack(t *testing.T) {
+	mock := NewMock()
+	mock.Error().WithStack().Message("")
+
+	require.Len(t, mock.Logs, 1)
+	stack, ok := mock.Logs[0].Fields["stack"].([]StackFrame)
+	require.True(t, ok, "stack field of type []StackFrame")
+	require.NotEmpty(t, stack)
+	assert.True(t, strings.HasSuffix(stack[0].Function, ".TestEvent_WithStack"), stack[0].Function)
+}
+
+func TestStackFrame_String(t *testing.T) {
+	frame := StackFrame{Function: "main.main", File: "/src/main.go", Line: 12}
+	assert.Equal(t, "main.main (/src/main.go:12)", frame.String())
+}
+
+func TestWithStack_skippedWithoutStackContext(t *testing.T) {
+	mock := NewMock()
+	setStack(contextOnly{mock.NewContext("")}, CaptureStack(0)).
+		WriteOut(LevelInfo, "")
+
+	require.Len(t, mock.Logs, 1)
+	assert.NotContains(t, mock.Logs[0].Fields, "stack")
+}
@@ -0,0 +1,82 @@
+package logger
+
+// FallibleContext is an optional interface that a Context can implement to
+// report when it fails to write out a log event, such as when a network sink
+// is unable to accept more events.
+//
+// It is used by the Sink created by NewFailover to decide when to fall back
+// to the secondary sink.
+type FallibleContext interface {
+	Context
+	// TryWriteOut works the same as WriteOut, but returns an error if the log
+	// event could not be written out.
+	TryWriteOut(level Level, message string) error
+}
+
+// FailoverErrorKey is the field key added to log events written to the
+// secondary sink of NewFailover, with the error message of why the primary
+// sink failed.
+const FailoverErrorKey = "failoverError"
+
+// NewTee creates a Sink that writes each event to all of the given sinks.
+//
+// In contrast to calling AddOutput once per sink, the resulting sink can be
+// used wherever a single Sink is expected, such as in NewLevelRouter.
+func NewTee(sinks ...Sink) Sink {
+	return teeSink(sinks)
+}
+
+type teeSink []Sink
+
+func (s teeSink) NewContext(scope string) Context {
+	return newMultiContext(scope, s, teeWriteOut)
+}
+
+func teeWriteOut(ctxs []Context, level Level, message string) {
+	for _, ctx := range ctxs {
+		if ctx != nil {
+			ctx.WriteOut(level, message)
+		}
+	}
+}
+
+// NewFailover creates a Sink that writes each event to the primary sink, and
+// falls back to writing it to the secondary sink if the primary sink fails,
+// such as writing to a local file when a network sink is down.
+//
+// Only primary sinks with contexts that implement FallibleContext can report
+// failures. Events written to the secondary sink have the additional field
+// "failoverError" with the error message from the primary sink.
+func NewFailover(primary, secondary Sink) Sink {
+	return failoverSink{primary, secondary}
+}
+
+type failoverSink struct {
+	primary   Sink
+	secondary Sink
+}
+
+func (s failoverSink) NewContext(scope string) Context {
+	return newMultiContext(scope, []Sink{s.primary, s.secondary}, failoverWriteOut)
+}
+
+func failoverWriteOut(ctxs []Context, level Level, message string) {
+	primary, secondary := ctxs[0], ctxs[1]
+	if primary == nil {
+		if secondary != nil {
+			secondary.WriteOut(level, message)
+		}
+		return
+	}
+	fallible, ok := primary.(FallibleContext)
+	if !ok {
+		primary.WriteOut(level, message)
+		return
+	}
+	err := fallible.TryWriteOut(level, message)
+	if err == nil || secondary == nil {
+		return
+	}
+	secondary.AppendString(FailoverErrorKey, err.Error()).
+		WriteOut(level, message)
+}
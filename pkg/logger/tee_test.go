@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fallibleMock struct {
+	*Mock
+	err error
+}
+
+func (m fallibleMock) NewContext(scope string) Context {
+	return fallibleMockCtx{m.Mock.NewContext(scope).(mockCtx), m.err}
+}
+
+type fallibleMockCtx struct {
+	mockCtx
+	err error
+}
+
+func (c fallibleMockCtx) TryWriteOut(level Level, message string) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.WriteOut(level, message)
+	return nil
+}
+
+func TestNewTee(t *testing.T) {
+	t.Cleanup(reset)
+
+	first := NewMock()
+	second := NewMock()
+	AddOutput(LevelDebug, NewTee(first, nil, second))
+
+	New().Info().WithString("foo", "bar").Message("Teed")
+
+	for _, mock := range []*Mock{first, second} {
+		require.Len(t, mock.Logs, 1)
+		assert.Equal(t, "Teed", mock.Logs[0].Message)
+		assert.Equal(t, "bar", mock.Logs[0].Fields["foo"])
+	}
+}
+
+func TestNewFailover(t *testing.T) {
+	testCases := []struct {
+		name          string
+		primaryErr    error
+		wantPrimary   []string
+		wantSecondary []string
+	}{
+		{
+			name:          "primary succeeds",
+			wantPrimary:   []string{"Logged"},
+			wantSecondary: nil,
+		},
+		{
+			name:          "primary fails",
+			primaryErr:    errors.New("sink is down"),
+			wantPrimary:   nil,
+			wantSecondary: []string{"Logged"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			primary := NewMock()
+			secondary := NewMock()
+			sink := NewFailover(fallibleMock{primary, tc.primaryErr}, secondary)
+
+			// not appending any fields, as the fallibleMockCtx is lost when
+			// calling the Append... methods on the embedded mockCtx
+			sink.NewContext("").WriteOut(LevelInfo, "Logged")
+
+			assert.Equal(t, tc.wantPrimary, primary.LogMessages)
+			assert.Equal(t, tc.wantSecondary, secondary.LogMessages)
+			if tc.primaryErr != nil {
+				require.Len(t, secondary.Logs, 1)
+				assert.Equal(t, tc.primaryErr.Error(), secondary.Logs[0].Fields[FailoverErrorKey])
+			}
+		})
+	}
+}
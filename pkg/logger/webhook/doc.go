@@ -0,0 +1,8 @@
+// Package webhook is a concrete implementation of the logger.Sink and
+// logger.Context used for sending batches of JSON-formatted log events to a
+// HTTP endpoint, such as the Datadog logs intake API or the Splunk HTTP Event
+// Collector.
+//
+// Failed requests are retried with an exponential backoff, and events that
+// cannot be delivered can be spilled to a local file so they are not lost.
+package webhook
@@ -0,0 +1,532 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+)
+
+// ErrClosed is returned when flushing a Sink that has already been closed.
+var ErrClosed = errors.New("webhook sink is closed")
+
+// ErrBufferFull is returned when logging via logger.FallibleContext.TryWriteOut
+// while the buffer of events waiting to be sent is full.
+var ErrBufferFull = errors.New("webhook sink buffer is full")
+
+// BatchFormat specifies how a batch of events is composed in the body of each
+// HTTP request.
+type BatchFormat byte
+
+const (
+	// BatchJSONArray will send the batch as a JSON array of event objects, like
+	// so:
+	// 	[{"level":"info","message":"first"},{"level":"info","message":"second"}]
+	BatchJSONArray BatchFormat = iota
+	// BatchNDJSON will send the batch as newline-delimited JSON event objects,
+	// as expected by for example the Splunk HTTP Event Collector, like so:
+	// 	{"level":"info","message":"first"}
+	// 	{"level":"info","message":"second"}
+	BatchNDJSON
+)
+
+// Config lets you configure the target endpoint and the batching and retry
+// behavior of the Sink.
+type Config struct {
+	// URL is the HTTP endpoint that the batches of events are POSTed to.
+	URL string
+	// Headers are added to each HTTP request. Useful for authentication, such
+	// as an "Authorization" or "DD-API-KEY" header.
+	Headers map[string]string
+	// Client is the HTTP client used when sending the events. Defaults to a
+	// client with a timeout of 10 seconds.
+	Client *http.Client
+	// BatchFormat defines how the batch of events is composed in the HTTP
+	// request body. Defaults to BatchJSONArray.
+	BatchFormat BatchFormat
+	// BatchSize is the maximum number of events sent in a single HTTP
+	// request. Defaults to 100.
+	BatchSize int
+	// BufferSize is the maximum number of events waiting to be sent. Any
+	// events logged while the buffer is full is written directly to the
+	// SpillFile, or dropped if SpillFile is unset. Defaults to 10000.
+	BufferSize int
+	// FlushInterval is how often the buffered events are sent, even if the
+	// batch is not full. Defaults to 5 seconds.
+	FlushInterval time.Duration
+	// MaxRetries is how many times a failed HTTP request is retried before
+	// giving up on the batch. Defaults to 5. Set to a negative value to
+	// disable retries.
+	MaxRetries int
+	// RetryBackoff is the wait time before the first retry. The wait time is
+	// doubled on each consecutive retry. Defaults to 500 milliseconds.
+	RetryBackoff time.Duration
+	// MaxRetryBackoff is the upper limit of the wait time between retries.
+	// Defaults to 30 seconds.
+	MaxRetryBackoff time.Duration
+	// SpillFile is an optional file path where event batches are appended as
+	// newline-delimited JSON when they fail to be sent after all retries, or
+	// when the buffer is full.
+	SpillFile string
+	// OnError is an optional function called whenever sending a batch fails.
+	// Logging via the logger package from within this function could cause
+	// an infinite loop, and should be avoided.
+	OnError func(err error)
+	// DisableDate removes the date field from the events when set to true.
+	DisableDate bool
+	// DisableCaller removes the caller file name and line fields from the
+	// events when set to true.
+	DisableCaller bool
+}
+
+// Sink is a logger.Sink that buffers events and sends them in batches of JSON
+// objects to a HTTP endpoint. Make sure to call Sink.Close before the
+// application exits to not lose any buffered events.
+type Sink struct {
+	config   Config
+	events   chan []byte
+	flushReq chan chan error
+	done     chan struct{}
+	stopped  chan struct{}
+	close    sync.Once
+	spillMu  sync.Mutex
+}
+
+// New creates a new webhook logging Sink and starts its background goroutine
+// that sends the events.
+func New(conf Config) *Sink {
+	if conf.Client == nil {
+		conf.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if conf.BatchSize <= 0 {
+		conf.BatchSize = 100
+	}
+	if conf.BufferSize <= 0 {
+		conf.BufferSize = 10000
+	}
+	if conf.FlushInterval <= 0 {
+		conf.FlushInterval = 5 * time.Second
+	}
+	if conf.MaxRetries == 0 {
+		conf.MaxRetries = 5
+	}
+	if conf.RetryBackoff <= 0 {
+		conf.RetryBackoff = 500 * time.Millisecond
+	}
+	if conf.MaxRetryBackoff <= 0 {
+		conf.MaxRetryBackoff = 30 * time.Second
+	}
+	s := &Sink{
+		config:   conf,
+		events:   make(chan []byte, conf.BufferSize),
+		flushReq: make(chan chan error),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// NewContext creates a new webhook logging Context using the same
+// configuration as the one given when creating the Sink.
+func (s *Sink) NewContext(scope string) logger.Context {
+	return context{sink: s, scope: scope}
+}
+
+// Flush sends all buffered events right away, and blocks until they have been
+// sent or failed to do so.
+func (s *Sink) Flush() error {
+	reply := make(chan error)
+	select {
+	case s.flushReq <- reply:
+		return <-reply
+	case <-s.stopped:
+		return ErrClosed
+	}
+}
+
+// Close sends all buffered events and then stops the background goroutine.
+// Any events logged after closing are written directly to the SpillFile, or
+// dropped if SpillFile is unset.
+func (s *Sink) Close() error {
+	s.close.Do(func() {
+		close(s.done)
+	})
+	<-s.stopped
+	return nil
+}
+
+func (s *Sink) enqueue(ev []byte) {
+	switch err := s.tryEnqueue(ev); err {
+	case nil:
+	case ErrBufferFull:
+		s.reportError(err)
+		s.spill([][]byte{ev})
+	default:
+		s.spill([][]byte{ev})
+	}
+}
+
+func (s *Sink) tryEnqueue(ev []byte) error {
+	select {
+	case <-s.done:
+		return ErrClosed
+	default:
+	}
+	select {
+	case s.events <- ev:
+		return nil
+	default:
+		return ErrBufferFull
+	}
+}
+
+func (s *Sink) run() {
+	defer close(s.stopped)
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+	var batch [][]byte
+	for {
+		select {
+		case ev := <-s.events:
+			batch = append(batch, ev)
+			if len(batch) >= s.config.BatchSize {
+				s.send(batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			s.send(batch)
+			batch = nil
+		case reply := <-s.flushReq:
+			batch = s.drain(batch)
+			reply <- s.send(batch)
+			batch = nil
+		case <-s.done:
+			s.send(s.drain(batch))
+			return
+		}
+	}
+}
+
+func (s *Sink) drain(batch [][]byte) [][]byte {
+	for {
+		select {
+		case ev := <-s.events:
+			batch = append(batch, ev)
+		default:
+			return batch
+		}
+	}
+}
+
+func (s *Sink) send(batch [][]byte) error {
+	var firstErr error
+	for len(batch) > 0 {
+		n := len(batch)
+		if n > s.config.BatchSize {
+			n = s.config.BatchSize
+		}
+		if err := s.sendWithRetry(batch[:n]); err != nil {
+			s.reportError(err)
+			s.spill(batch[:n])
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		batch = batch[n:]
+	}
+	return firstErr
+}
+
+func (s *Sink) sendWithRetry(batch [][]byte) error {
+	body := s.composeBody(batch)
+	backoff := s.config.RetryBackoff
+	var err error
+	for attempt := 0; ; attempt++ {
+		var retryable bool
+		retryable, err = s.post(body)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt >= s.config.MaxRetries {
+			break
+		}
+		select {
+		case <-time.After(backoff):
+		case <-s.done:
+			return fmt.Errorf("webhook sink closed while retrying: %w", err)
+		}
+		backoff *= 2
+		if backoff > s.config.MaxRetryBackoff {
+			backoff = s.config.MaxRetryBackoff
+		}
+	}
+	return err
+}
+
+func (s *Sink) composeBody(batch [][]byte) []byte {
+	var buf bytes.Buffer
+	switch s.config.BatchFormat {
+	case BatchNDJSON:
+		for _, ev := range batch {
+			buf.Write(ev)
+			buf.WriteByte('\n')
+		}
+	default:
+		buf.WriteByte('[')
+		for i, ev := range batch {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.Write(ev)
+		}
+		buf.WriteByte(']')
+	}
+	return buf.Bytes()
+}
+
+func (s *Sink) post(body []byte) (retryable bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, s.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("create webhook request: %w", err)
+	}
+	switch s.config.BatchFormat {
+	case BatchNDJSON:
+		req.Header.Set("Content-Type", "application/x-ndjson")
+	default:
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range s.config.Headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := s.config.Client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("send webhook request: %w", err)
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("send webhook request: unexpected status: %s", resp.Status)
+	default:
+		return false, fmt.Errorf("send webhook request: unexpected status: %s", resp.Status)
+	}
+}
+
+func (s *Sink) spill(batch [][]byte) {
+	if s.config.SpillFile == "" || len(batch) == 0 {
+		return
+	}
+	s.spillMu.Lock()
+	defer s.spillMu.Unlock()
+	file, err := os.OpenFile(s.config.SpillFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		s.reportError(fmt.Errorf("open webhook spill file: %w", err))
+		return
+	}
+	defer file.Close()
+	var buf bytes.Buffer
+	for _, ev := range batch {
+		buf.Write(ev)
+		buf.WriteByte('\n')
+	}
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		s.reportError(fmt.Errorf("write webhook spill file: %w", err))
+	}
+}
+
+func (s *Sink) reportError(err error) {
+	if s.config.OnError != nil {
+		s.config.OnError(err)
+	}
+}
+
+type field struct {
+	key   string
+	value any
+}
+
+type context struct {
+	sink       *Sink
+	fields     []field
+	scope      string
+	caller     string
+	callerLine int
+	err        error
+	stack      []logger.StackFrame
+}
+
+func (c context) WriteOut(level logger.Level, message string) {
+	c.sink.enqueue(c.encode(level, message))
+}
+
+// TryWriteOut implements logger.FallibleContext. In contrast to WriteOut, the
+// event is not written to the SpillFile if it could not be buffered, and
+// ErrBufferFull or ErrClosed is returned instead.
+func (c context) TryWriteOut(level logger.Level, message string) error {
+	return c.sink.tryEnqueue(c.encode(level, message))
+}
+
+func (c context) encode(level logger.Level, message string) []byte {
+	var buf []byte
+	buf = append(buf, `{"level":`...)
+	buf = appendJSON(buf, levelString(level))
+	if !c.sink.config.DisableDate {
+		buf = append(buf, `,"date":`...)
+		buf = appendJSON(buf, time.Now().Format(time.RFC3339Nano))
+	}
+	if !c.sink.config.DisableCaller && c.caller != "" {
+		buf = append(buf, `,"caller":`...)
+		buf = appendJSON(buf, c.caller)
+		buf = append(buf, `,"line":`...)
+		buf = strconv.AppendInt(buf, int64(c.callerLine), 10)
+	}
+	if c.scope != "" {
+		buf = append(buf, `,"scope":`...)
+		buf = appendJSON(buf, c.scope)
+	}
+	if message != "" {
+		buf = append(buf, `,"message":`...)
+		buf = appendJSON(buf, message)
+	}
+	if c.err != nil {
+		buf = append(buf, `,"error":`...)
+		buf = appendJSON(buf, c.err.Error())
+	}
+	if c.stack != nil {
+		buf = append(buf, `,"stack":[`...)
+		for i, frame := range c.stack {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = append(buf, `{"function":`...)
+			buf = appendJSON(buf, frame.Function)
+			buf = append(buf, `,"file":`...)
+			buf = appendJSON(buf, frame.File)
+			buf = append(buf, `,"line":`...)
+			buf = strconv.AppendInt(buf, int64(frame.Line), 10)
+			buf = append(buf, '}')
+		}
+		buf = append(buf, ']')
+	}
+	for _, f := range c.fields {
+		buf = append(buf, ',')
+		buf = appendJSON(buf, f.key)
+		buf = append(buf, ':')
+		buf = appendJSON(buf, f.value)
+	}
+	buf = append(buf, '}')
+	return buf
+}
+
+func (c context) SetCaller(file string, line int) logger.Context {
+	c.caller, c.callerLine = file, line
+	return c
+}
+
+func (c context) SetError(value error) logger.Context {
+	c.err = value
+	return c
+}
+
+func (c context) SetStack(value []logger.StackFrame) logger.Context {
+	c.stack = value
+	return c
+}
+
+func (c context) AppendString(k string, v string) logger.Context { return c.addField(k, v) }
+func (c context) AppendRune(k string, v rune) logger.Context     { return c.addField(k, string(v)) }
+func (c context) AppendBool(k string, v bool) logger.Context     { return c.addField(k, v) }
+func (c context) AppendInt(k string, v int) logger.Context       { return c.addField(k, v) }
+func (c context) AppendInt32(k string, v int32) logger.Context   { return c.addField(k, v) }
+func (c context) AppendInt64(k string, v int64) logger.Context   { return c.addField(k, v) }
+func (c context) AppendUint(k string, v uint) logger.Context     { return c.addField(k, v) }
+func (c context) AppendUint32(k string, v uint32) logger.Context { return c.addField(k, v) }
+func (c context) AppendUint64(k string, v uint64) logger.Context { return c.addField(k, v) }
+func (c context) AppendFloat32(k string, v float32) logger.Context {
+	return c.addField(k, float64(v))
+}
+func (c context) AppendFloat64(k string, v float64) logger.Context { return c.addField(k, v) }
+func (c context) AppendTime(k string, v time.Time) logger.Context {
+	return c.addField(k, v.Format(time.RFC3339Nano))
+}
+func (c context) AppendDuration(k string, v time.Duration) logger.Context {
+	return c.addField(k, int64(v))
+}
+
+func (c context) AppendObject(k string, v func(logger.ObjectEncoder)) logger.Context {
+	return c.addField(k, object(logger.ObjectFields(v)))
+}
+
+// object is a nested object that is marshaled as a JSON object with its
+// fields in the order they were added.
+type object []logger.Field
+
+func (o object) MarshalJSON() ([]byte, error) {
+	buf := []byte{'{'}
+	for i, f := range o {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendJSON(buf, f.Key)
+		buf = append(buf, ':')
+		switch v := f.Value.(type) {
+		case []logger.Field:
+			buf = appendJSON(buf, object(v))
+		case time.Duration:
+			buf = appendJSON(buf, int64(v))
+		default:
+			buf = appendJSON(buf, v)
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+func (c context) addField(key string, value any) logger.Context {
+	c.fields = append(c.fields, field{key, value})
+	return c
+}
+
+func appendJSON(b []byte, value any) []byte {
+	if f, ok := value.(float64); ok {
+		switch {
+		case math.IsNaN(f):
+			return append(b, `"NaN"`...)
+		case math.IsInf(f, 1):
+			return append(b, `"+Inf"`...)
+		case math.IsInf(f, -1):
+			return append(b, `"-Inf"`...)
+		}
+	}
+	out, err := json.Marshal(value)
+	if err != nil {
+		return append(b, `""`...)
+	}
+	return append(b, out...)
+}
+
+func levelString(level logger.Level) string {
+	switch level {
+	case logger.LevelDebug:
+		return "debug"
+	case logger.LevelInfo:
+		return "info"
+	case logger.LevelWarn:
+		return "warn"
+	case logger.LevelError:
+		return "error"
+	case logger.LevelPanic:
+		return "panic"
+	default:
+		return "unknown"
+	}
+}
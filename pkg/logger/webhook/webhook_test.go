@@ -0,0 +1,163 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	bodies   []string
+	headers  []http.Header
+	statuses []int
+}
+
+func newTestServer(t *testing.T, statuses ...int) *testServer {
+	srv := &testServer{statuses: statuses}
+	srv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		srv.bodies = append(srv.bodies, string(body))
+		srv.headers = append(srv.headers, r.Header)
+		if len(srv.statuses) > 0 {
+			w.WriteHeader(srv.statuses[0])
+			srv.statuses = srv.statuses[1:]
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestSink(conf Config) *Sink {
+	conf.DisableDate = true
+	conf.DisableCaller = true
+	conf.FlushInterval = time.Hour
+	conf.RetryBackoff = time.Millisecond
+	return New(conf)
+}
+
+func logTo(sink logger.Sink, level logger.Level, message string) {
+	sink.NewContext("TEST").AppendInt("id", 1).WriteOut(level, message)
+}
+
+func TestSink_Flush(t *testing.T) {
+	srv := newTestServer(t)
+	sink := newTestSink(Config{
+		URL:     srv.URL,
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	})
+	defer sink.Close()
+
+	logTo(sink, logger.LevelInfo, "first")
+	logTo(sink, logger.LevelWarn, "second")
+	require.NoError(t, sink.Flush())
+
+	require.Len(t, srv.bodies, 1)
+	assert.Equal(t,
+		`[{"level":"info","scope":"TEST","message":"first","id":1},{"level":"warn","scope":"TEST","message":"second","id":1}]`,
+		srv.bodies[0])
+	assert.Equal(t, "Bearer token", srv.headers[0].Get("Authorization"))
+	assert.Equal(t, "application/json", srv.headers[0].Get("Content-Type"))
+}
+
+func TestSink_batchSize(t *testing.T) {
+	srv := newTestServer(t)
+	sink := newTestSink(Config{
+		URL:         srv.URL,
+		BatchSize:   2,
+		BatchFormat: BatchNDJSON,
+	})
+
+	for i := 0; i < 5; i++ {
+		logTo(sink, logger.LevelInfo, "")
+	}
+	require.NoError(t, sink.Close())
+
+	require.Len(t, srv.bodies, 3)
+	assert.Equal(t, 2, strings.Count(srv.bodies[0], "\n"))
+	assert.Equal(t, 1, strings.Count(srv.bodies[2], "\n"))
+	for _, line := range strings.Split(strings.TrimSpace(srv.bodies[0]), "\n") {
+		assert.True(t, json.Valid([]byte(line)), "valid JSON: %s", line)
+	}
+}
+
+func TestSink_retry(t *testing.T) {
+	srv := newTestServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	sink := newTestSink(Config{URL: srv.URL})
+	defer sink.Close()
+
+	logTo(sink, logger.LevelInfo, "retried")
+	require.NoError(t, sink.Flush())
+
+	require.Len(t, srv.bodies, 3)
+	assert.Equal(t, srv.bodies[0], srv.bodies[2])
+}
+
+func TestSink_spillOnFailure(t *testing.T) {
+	srv := newTestServer(t, http.StatusBadRequest)
+	spillFile := filepath.Join(t.TempDir(), "spill.log")
+	var errs []error
+	sink := newTestSink(Config{
+		URL:       srv.URL,
+		SpillFile: spillFile,
+		OnError:   func(err error) { errs = append(errs, err) },
+	})
+	defer sink.Close()
+
+	logTo(sink, logger.LevelError, "spilled")
+	assert.Error(t, sink.Flush())
+
+	require.Len(t, srv.bodies, 1, "bad request should not be retried")
+	assert.Len(t, errs, 1)
+	content, err := os.ReadFile(spillFile)
+	require.NoError(t, err)
+	assert.Equal(t, `{"level":"error","scope":"TEST","message":"spilled","id":1}`+"\n", string(content))
+}
+
+func TestSink_failoverWhenClosed(t *testing.T) {
+	srv := newTestServer(t)
+	sink := newTestSink(Config{URL: srv.URL})
+	require.NoError(t, sink.Close())
+
+	mock := logger.NewMock()
+	logTo(logger.NewFailover(sink, mock), logger.LevelInfo, "failed over")
+
+	require.Len(t, mock.Logs, 1)
+	assert.Equal(t, "failed over", mock.Logs[0].Message)
+	assert.Equal(t, ErrClosed.Error(), mock.Logs[0].Fields[logger.FailoverErrorKey])
+}
+
+func TestSink_nestedObject(t *testing.T) {
+	srv := newTestServer(t)
+	sink := newTestSink(Config{URL: srv.URL, BatchFormat: BatchNDJSON})
+	defer sink.Close()
+
+	sink.NewContext("").(logger.ObjectContext).
+		AppendObject("http", func(enc logger.ObjectEncoder) {
+			enc.AddString("method", "GET")
+			enc.AddObject("client", func(enc logger.ObjectEncoder) {
+				enc.AddDuration("latency", time.Second)
+			})
+		}).
+		WriteOut(logger.LevelInfo, "")
+	require.NoError(t, sink.Flush())
+
+	require.Len(t, srv.bodies, 1)
+	assert.Equal(t,
+		`{"level":"info","http":{"method":"GET","client":{"latency":1000000000}}}`+"\n",
+		srv.bodies[0])
+}
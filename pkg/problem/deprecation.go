@@ -0,0 +1,91 @@
+package problem
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Deprecation holds information about an endpoint that is slated for removal.
+// It can be added to a problem response via the Response.Deprecation field, as
+// well as written as HTTP headers via Deprecation.WriteHeaders.
+type Deprecation struct {
+	// Date is when the endpoint was, or will be, deprecated. A zero value
+	// signifies that the endpoint is deprecated, without any specific date.
+	Date time.Time `json:"date,omitempty" xml:"date,omitempty" format:"date-time"`
+
+	// Sunset is when the endpoint is expected to become unresponsive. A zero
+	// value signifies that no sunset date has been decided.
+	Sunset time.Time `json:"sunset,omitempty" xml:"sunset,omitempty" format:"date-time"`
+
+	// Link is an optional URL to documentation on how to migrate away from
+	// the deprecated endpoint.
+	Link string `json:"link,omitempty" xml:"link,omitempty" example:"https://wharf.iver.com/#/development/migrations/v5"`
+
+	// Message is an optional human-readable explanation of the deprecation.
+	Message string `json:"message,omitempty" xml:"message,omitempty" example:"Use the /api/v5/projects endpoint instead."`
+}
+
+// WriteHeaders sets the standardized HTTP headers for the deprecation:
+//
+// The Deprecation header, as defined in IETF RFC-9745, with the deprecation
+// date as a UNIX timestamp, or "true" if no date is set.
+//
+// The Sunset header, as defined in IETF RFC-8594, with the sunset date
+// formatted as a HTTP-date, if any.
+//
+// The Link header with the deprecation relation type, if a link is set.
+func (d Deprecation) WriteHeaders(h http.Header) {
+	if d.Date.IsZero() {
+		h.Set("Deprecation", "true")
+	} else {
+		h.Set("Deprecation", "@"+strconv.FormatInt(d.Date.Unix(), 10))
+	}
+	if !d.Sunset.IsZero() {
+		h.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Link != "" {
+		h.Add("Link", fmt.Sprintf(`<%s>; rel="deprecation"`, d.Link))
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface, and omits the zero
+// value dates from the JSON output.
+func (d Deprecation) MarshalJSON() ([]byte, error) {
+	type deprecationJSON struct {
+		Date    *time.Time `json:"date,omitempty"`
+		Sunset  *time.Time `json:"sunset,omitempty"`
+		Link    string     `json:"link,omitempty"`
+		Message string     `json:"message,omitempty"`
+	}
+	out := deprecationJSON{Link: d.Link, Message: d.Message}
+	if !d.Date.IsZero() {
+		out.Date = &d.Date
+	}
+	if !d.Sunset.IsZero() {
+		out.Sunset = &d.Sunset
+	}
+	return json.Marshal(out)
+}
+
+// MarshalXML implements the xml.Marshaler interface, and omits the zero value
+// dates from the XML output.
+func (d Deprecation) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type deprecationXML struct {
+		Date    *time.Time `xml:"date,omitempty"`
+		Sunset  *time.Time `xml:"sunset,omitempty"`
+		Link    string     `xml:"link,omitempty"`
+		Message string     `xml:"message,omitempty"`
+	}
+	out := deprecationXML{Link: d.Link, Message: d.Message}
+	if !d.Date.IsZero() {
+		out.Date = &d.Date
+	}
+	if !d.Sunset.IsZero() {
+		out.Sunset = &d.Sunset
+	}
+	return e.EncodeElement(out, start)
+}
@@ -0,0 +1,55 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecation_WriteHeaders(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		deprecation Deprecation
+		want        http.Header
+	}{
+		{
+			name:        "no date",
+			deprecation: Deprecation{},
+			want:        http.Header{"Deprecation": {"true"}},
+		},
+		{
+			name: "all set",
+			deprecation: Deprecation{
+				Date:   time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+				Sunset: time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+				Link:   "https://example.com/migrate",
+			},
+			want: http.Header{
+				"Deprecation": {"@1654041600"},
+				"Sunset":      {"Sun, 01 Jan 2023 12:00:00 GMT"},
+				"Link":        {`<https://example.com/migrate>; rel="deprecation"`},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := make(http.Header)
+			tc.deprecation.WriteHeaders(h)
+			assert.Equal(t, tc.want, h)
+		})
+	}
+}
+
+func TestDeprecation_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(Response{Deprecation: &Deprecation{Message: "Use v5."}})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"deprecation":{"message":"Use v5."}`)
+
+	b, err = json.Marshal(Response{})
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "deprecation")
+}
@@ -0,0 +1,51 @@
+package problem
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// InvalidParam holds information about a single request parameter or body
+// field that failed validation. It can be added to a problem response via the
+// Response.InvalidParams field.
+type InvalidParam struct {
+	// Name is the name of the offending parameter or field, where nested
+	// fields are delimited by dots.
+	Name string `json:"name" xml:"name" example:"Project.Name"`
+
+	// Reason is a human-readable explanation of why the value is invalid.
+	Reason string `json:"reason" xml:"reason" example:"Value failed the \"required\" validation rule."`
+
+	// Rule is the name of the validation rule that failed, such as
+	// "required" or "max".
+	Rule string `json:"rule,omitempty" xml:"rule,omitempty" example:"required"`
+
+	// Value is the offending value, if any. When written as XML, the value is
+	// formatted as a string using fmt.Sprint, as the encoding/xml package
+	// cannot marshal all types, such as maps.
+	Value any `json:"value,omitempty" xml:"value,omitempty"`
+}
+
+type invalidParamXML struct {
+	Name   string `xml:"name"`
+	Reason string `xml:"reason"`
+	Rule   string `xml:"rule,omitempty"`
+	Value  string `xml:"value,omitempty"`
+}
+
+// MarshalXML writes the invalid parameter as XML, where the Value is formatted
+// as a string using fmt.Sprint.
+//
+// This method provides compatibility with the encoding/xml.Marshaler
+// interface.
+func (p InvalidParam) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	x := invalidParamXML{
+		Name:   p.Name,
+		Reason: p.Reason,
+		Rule:   p.Rule,
+	}
+	if p.Value != nil {
+		x.Value = fmt.Sprint(p.Value)
+	}
+	return e.EncodeElement(x, start)
+}
@@ -2,6 +2,7 @@ package problem
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -15,18 +16,27 @@ import (
 // not be changed.
 const HTTPContentType = "application/problem+json"
 
-// Response can be serialized into JSON and its fields follow the problem schema
-// defined by IETF RFC-7807.
+// HTTPContentTypeXML is the value used in HTTP requests and responses for the
+// Content-Type header when the problem is serialized into XML. This is defined
+// by the IETF RFC-7807 and can therefore not be changed.
+const HTTPContentTypeXML = "application/problem+xml"
+
+// Response can be serialized into JSON or XML and its fields follow the problem
+// schema defined by IETF RFC-7807.
 //
 // This type also conforms to the error interface by using the title and list
 // of errors as the error message.
 type Response struct {
+	// XMLName is the name of the root element when serialized into XML, as
+	// defined by IETF RFC-7807.
+	XMLName xml.Name `json:"-" xml:"urn:ietf:rfc:7807 problem"`
+
 	// Type is a URI reference that identifies the problem type. The IETF
 	// RFC-7807 specification encourages that, when dereferenced, it provide
 	// human-readable documentation for the problem type (e.g., using HTML).
 	// When this member is not present, its value is assumed to be
 	// "about:blank".
-	Type string `json:"type" example:"https://wharf.iver.com/#/prob/build/run/invalid-input"`
+	Type string `json:"type" xml:"type" example:"https://wharf.iver.com/#/prob/build/run/invalid-input"`
 
 	// Title is a short, human-readable summary of the problem type.
 	// It SHOULD NOT change from occurrence to ocurrence of the problem, except
@@ -34,11 +44,11 @@ type Response struct {
 	//
 	// Recommended to be kept brief, have proper punctuation, and be
 	// capitalized, like a short sentence.
-	Title string `json:"title" example:"Invalid input variable for build."`
+	Title string `json:"title" xml:"title" example:"Invalid input variable for build."`
 
 	// Status is the HTTP status code generated by the origin server for this
 	// occurrence of the problem.
-	Status int `json:"status" example:"400"`
+	Status int `json:"status" xml:"status" example:"400"`
 
 	// Detail is a human-readable explanation specific to this occurrence of the
 	// problem.
@@ -46,15 +56,35 @@ type Response struct {
 	// Recommended to have proper punctuation, and be capitalized,
 	// like a sentence. Compared to Title this field may stretch on and be
 	// longer.
-	Detail string `json:"detail" example:"Build requires input variable 'myInput' to be of type 'string', but got 'int' instead."`
+	Detail string `json:"detail" xml:"detail" example:"Build requires input variable 'myInput' to be of type 'string', but got 'int' instead."`
 
 	// Instance is a URI reference that identifies the specific occurrence of
 	// the problem. It may or may not yield further information if dereferenced.
-	Instance string `json:"instance" example:"/projects/12345/builds/run/6789"`
+	Instance string `json:"instance" xml:"instance" example:"/projects/12345/builds/run/6789"`
 
 	// Error is an extended field for the regular Problem model defined in
 	// RFC-7807. It contains the string message of the error (if any).
-	Errors []string `json:"errors" example:"strconv.ParseUint: parsing \"-1\": invalid syntax"`
+	Errors []string `json:"errors" xml:"errors>error" example:"strconv.ParseUint: parsing \"-1\": invalid syntax"`
+
+	// Deprecation is an extended field for the regular Problem model defined
+	// in RFC-7807. It contains the deprecation notice of the endpoint, if the
+	// endpoint is slated for removal.
+	Deprecation *Deprecation `json:"deprecation,omitempty" xml:"deprecation,omitempty"`
+
+	// RequestID is an extended field for the regular Problem model defined in
+	// RFC-7807. It contains the ID of the request that caused the problem, if
+	// any, so the problem can be correlated with the server logs.
+	RequestID string `json:"requestId,omitempty" xml:"requestId,omitempty" example:"4a8ae2e4b3e6c2b39f1e5d7b1c0f93a2"`
+
+	// TraceID is an extended field for the regular Problem model defined in
+	// RFC-7807. It contains the ID of the distributed trace of the request
+	// that caused the problem, if any.
+	TraceID string `json:"traceId,omitempty" xml:"traceId,omitempty" example:"4bf92f3577b34da6a3ce929d0e0e4736"`
+
+	// InvalidParams is an extended field for the regular Problem model
+	// defined in RFC-7807. It contains the request parameters or body fields
+	// that failed validation, if any.
+	InvalidParams []InvalidParam `json:"invalidParams,omitempty" xml:"invalidParams>param,omitempty"`
 }
 
 func (r Response) Error() string {
@@ -70,17 +100,22 @@ func (r Response) String() string {
 // IsHTTPResponse returns true if the HTTP response has the Content-Type of a
 // problem response:
 // 	Content-Type: application/problem+json
+// 	Content-Type: application/problem+xml
 func IsHTTPResponse(response *http.Response) bool {
 	if response.Header == nil {
 		return false
 	}
-	return response.Header.Get("Content-Type") == HTTPContentType
+	contentType := response.Header.Get("Content-Type")
+	return contentType == HTTPContentType || contentType == HTTPContentTypeXML
 }
 
 // ParseHTTPResponse attempts to unmarshal the body response as a problem
 // response. No validation check is made, so the user of this function is
 // assumed to check if the body is of the correct content type before calling
 // this function, for example via the IsHTTPResponse function.
+//
+// The body is parsed as XML if the response has the Content-Type of
+// HTTPContentTypeXML, and as JSON otherwise.
 func ParseHTTPResponse(response *http.Response) (Response, error) {
 	resp, readErr := ioutil.ReadAll(response.Body)
 	if readErr != nil {
@@ -92,6 +127,13 @@ func ParseHTTPResponse(response *http.Response) (Response, error) {
 			"failed to close problem response body reading: %w", closeErr)
 	}
 	var prob Response
+	if response.Header.Get("Content-Type") == HTTPContentTypeXML {
+		if xmlErr := xml.Unmarshal(resp, &prob); xmlErr != nil {
+			return Response{}, fmt.Errorf(
+				`failed to parse "%s" problem response: %w`, HTTPContentTypeXML, xmlErr)
+		}
+		return prob, nil
+	}
 	if jsonErr := json.Unmarshal(resp, &prob); jsonErr != nil {
 		return Response{}, fmt.Errorf(
 			`failed to parse "%s" problem response: %w`, HTTPContentType, jsonErr)
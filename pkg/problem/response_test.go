@@ -57,7 +57,7 @@ func TestParseHTTPResponse_fail(t *testing.T) {
 		name   string
 		reader io.ReadCloser
 		errIs  error
-		errAs  error
+		errAs  any
 	}{
 		{
 			name:   "read",
@@ -72,7 +72,7 @@ func TestParseHTTPResponse_fail(t *testing.T) {
 		{
 			name:   "parse",
 			reader: io.NopCloser(strings.NewReader("???")),
-			errAs:  &json.SyntaxError{},
+			errAs:  new(*json.SyntaxError),
 		},
 	}
 	for _, tc := range testCases {
@@ -89,7 +89,7 @@ func TestParseHTTPResponse_fail(t *testing.T) {
 					t.Errorf("wanted: %s; got: %s", tc.errIs, err)
 				}
 			} else {
-				if !errors.As(err, &tc.errAs) {
+				if !errors.As(err, tc.errAs) {
 					t.Errorf("wanted: %T; got: %s", tc.errAs, err)
 				}
 			}
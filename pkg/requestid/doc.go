@@ -0,0 +1,5 @@
+// Package requestid contains functions for storing and retrieving request IDs
+// in a context.Context, without depending on any HTTP framework, so that
+// integrations such as ginutil and gormutil can share the request ID of a
+// single request.
+package requestid
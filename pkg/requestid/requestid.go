@@ -0,0 +1,39 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of the context that holds the request ID, which
+// can be retrieved using FromContext.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID stored in the context via NewContext, or
+// an empty string if none has been set.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// FieldsProvider is a logger.FieldsProvider that adds the request ID from the
+// context.Context, if any, as the "requestId" field, namespaced using
+// logger.NamespaceHTTP.
+//
+// Meant to be registered using logger.RegisterFieldsProvider, so that the
+// request ID is added to all log events that use
+// logger.Event.WithProvidedFields:
+//
+//	logger.RegisterFieldsProvider(requestid.FieldsProvider)
+func FieldsProvider(ctx context.Context) []logger.Field {
+	id := FromContext(ctx)
+	if id == "" {
+		return nil
+	}
+	return []logger.Field{{Key: logger.NamespacedKey(logger.NamespaceHTTP, "requestId"), Value: id}}
+}
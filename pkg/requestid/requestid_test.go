@@ -0,0 +1,21 @@
+package requestid
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iver-wharf/wharf-core/v2/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, "", FromContext(context.Background()))
+	ctx := NewContext(context.Background(), "abc123")
+	assert.Equal(t, "abc123", FromContext(ctx))
+}
+
+func TestFieldsProvider(t *testing.T) {
+	assert.Nil(t, FieldsProvider(context.Background()))
+	ctx := NewContext(context.Background(), "abc123")
+	assert.Equal(t, []logger.Field{{Key: "requestId", Value: "abc123"}}, FieldsProvider(ctx))
+}